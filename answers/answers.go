@@ -0,0 +1,323 @@
+// Package answers pre-declares the responses to the questions asked during an import, so that a
+// migration can run unattended (e.g. from CI) and be replayed across many organizations.
+//
+// An answers file can be written in YAML or JSON:
+//
+//	team_interface: service
+//	continue_without_team_members: true
+//	teams:
+//	  include: [P1TEAM, "Platform Team"]
+//	team_links:
+//	  P1TEAM: platform          # FireHydrant team ID, slug or name
+//	  Platform Team: new        # create a new team in FireHydrant
+//	users:
+//	  default: skip             # applies to unmatched users not listed below
+//	  create: [jane@example.com]
+//	  link:
+//	    PUSER01: john.doe@example.com
+//	escalation_policies:
+//	  exclude: [PPOLICY]
+//	default_escalation_policies:
+//	  P1TEAM: Platform Primary  # for teams with several escalation policies
+//	existing_resources:         # schedules and policies to import rather than create again
+//	  all: true
+//
+// Teams, users, escalation policies and existing resources may be referred to by their ID in the
+// provider, or by their name (or email, for users), case-insensitively.
+package answers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ErrUnanswered is returned when a question has no answer and prompting is not allowed.
+var ErrUnanswered = errors.New("question is not answered")
+
+const (
+	// NewTeam is the team link value which creates a new team in FireHydrant.
+	NewTeam = "new"
+
+	UserCreate = "create"
+	UserSkip   = "skip"
+	UserLink   = "link"
+)
+
+// Answers holds the responses to the import prompts. Questions which are not answered are
+// prompted for interactively, and the responses recorded back so that they can be saved.
+type Answers struct {
+	TeamInterface              string            `json:"team_interface,omitempty" yaml:"team_interface,omitempty"`
+	ContinueWithoutTeamMembers *bool             `json:"continue_without_team_members,omitempty" yaml:"continue_without_team_members,omitempty"`
+	Teams                      Selection         `json:"teams" yaml:"teams,omitempty"`
+	TeamLinks                  map[string]string `json:"team_links,omitempty" yaml:"team_links,omitempty"`
+	Users                      Users             `json:"users" yaml:"users,omitempty"`
+	EscalationPolicies         Selection         `json:"escalation_policies" yaml:"escalation_policies,omitempty"`
+	DefaultEscalationPolicies  map[string]string `json:"default_escalation_policies,omitempty" yaml:"default_escalation_policies,omitempty"`
+	ExistingResources          Selection         `json:"existing_resources" yaml:"existing_resources,omitempty"`
+
+	// NonInteractive makes Ask fail for every question not covered by the answers.
+	NonInteractive bool `json:"-" yaml:"-"`
+}
+
+// Selection chooses a subset of resources, e.g. which teams to migrate.
+type Selection struct {
+	All     bool     `json:"all,omitempty" yaml:"all,omitempty"`
+	None    bool     `json:"none,omitempty" yaml:"none,omitempty"`
+	Include []string `json:"include,omitempty" yaml:"include,omitempty"`
+	Exclude []string `json:"exclude,omitempty" yaml:"exclude,omitempty"`
+}
+
+// Users declares what to do with users who could not be matched to FireHydrant by email.
+type Users struct {
+	Default string            `json:"default,omitempty" yaml:"default,omitempty"`
+	Create  []string          `json:"create,omitempty" yaml:"create,omitempty"`
+	Skip    []string          `json:"skip,omitempty" yaml:"skip,omitempty"`
+	Link    map[string]string `json:"link,omitempty" yaml:"link,omitempty"`
+}
+
+// Load reads answers from a YAML or JSON file.
+func Load(path string) (*Answers, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading answers file: %w", err)
+	}
+	a := &Answers{}
+	if isJSON(path) {
+		dec := json.NewDecoder(bytes.NewReader(b))
+		dec.DisallowUnknownFields()
+		err = dec.Decode(a)
+	} else {
+		dec := yaml.NewDecoder(bytes.NewReader(b))
+		dec.KnownFields(true)
+		err = dec.Decode(a)
+	}
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("parsing answers file '%s': %w", path, err)
+	}
+	if err := a.validate(); err != nil {
+		return nil, fmt.Errorf("invalid answers file '%s': %w", path, err)
+	}
+	return a, nil
+}
+
+// Save writes answers to a file, as JSON when the path ends in ".json" and YAML otherwise.
+func (a *Answers) Save(path string) error {
+	var b []byte
+	var err error
+	if isJSON(path) {
+		b, err = json.MarshalIndent(a, "", "  ")
+		b = append(b, '\n')
+	} else {
+		b, err = yaml.Marshal(a)
+	}
+	if err != nil {
+		return fmt.Errorf("encoding answers: %w", err)
+	}
+	if err := os.WriteFile(path, b, 0o644); err != nil {
+		return fmt.Errorf("writing answers file: %w", err)
+	}
+	return nil
+}
+
+func isJSON(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), ".json")
+}
+
+func (a *Answers) validate() error {
+	switch a.Users.Default {
+	case "", UserCreate, UserSkip:
+	default:
+		return fmt.Errorf("users.default must be '%s' or '%s', got '%s'", UserCreate, UserSkip, a.Users.Default)
+	}
+	for name, s := range map[string]Selection{"teams": a.Teams, "escalation_policies": a.EscalationPolicies} {
+		if s.All && s.None {
+			return fmt.Errorf("%s: 'all' and 'none' are mutually exclusive", name)
+		}
+	}
+	// Keys are looked up case-insensitively, so keys differing only by case would be ambiguous.
+	for name, m := range map[string]map[string]string{
+		"team_links":                  a.TeamLinks,
+		"default_escalation_policies": a.DefaultEscalationPolicies,
+		"users.link":                  a.Users.Link,
+	} {
+		seen := map[string]string{}
+		for _, k := range slices.Sorted(maps.Keys(m)) {
+			if other, ok := seen[strings.ToLower(k)]; ok {
+				return fmt.Errorf("%s: '%s' and '%s' differ only by case", name, other, k)
+			}
+			seen[strings.ToLower(k)] = k
+		}
+	}
+	return nil
+}
+
+// Merge adds the answers in other to a, replacing those to the same questions. A selection is
+// replaced as a whole when other declares it, while team, user and escalation policy answers are
+// replaced one at a time.
+func (a *Answers) Merge(other *Answers) {
+	if other.TeamInterface != "" {
+		a.TeamInterface = other.TeamInterface
+	}
+	if other.ContinueWithoutTeamMembers != nil {
+		a.ContinueWithoutTeamMembers = other.ContinueWithoutTeamMembers
+	}
+	for _, s := range []struct{ dst, src *Selection }{
+		{&a.Teams, &other.Teams},
+		{&a.EscalationPolicies, &other.EscalationPolicies},
+		{&a.ExistingResources, &other.ExistingResources},
+	} {
+		if s.src.Answered() {
+			*s.dst = *s.src
+		}
+	}
+	for id, fhTeam := range other.TeamLinks {
+		a.LinkTeam(id, fhTeam)
+	}
+	for id, policy := range other.DefaultEscalationPolicies {
+		a.SetDefaultEscalationPolicy(id, policy)
+	}
+
+	if other.Users.Default != "" {
+		a.Users.Default = other.Users.Default
+	}
+	// A user has a single decision, so whichever one other declares replaces any other in a.
+	decided := append(slices.Clone(other.Users.Create), other.Users.Skip...)
+	for id := range other.Users.Link {
+		decided = append(decided, id)
+	}
+	redecided := func(v string) bool { return contains(decided, v) }
+	a.Users.Create = append(slices.DeleteFunc(a.Users.Create, redecided), other.Users.Create...)
+	a.Users.Skip = append(slices.DeleteFunc(a.Users.Skip, redecided), other.Users.Skip...)
+	maps.DeleteFunc(a.Users.Link, func(id, _ string) bool { return redecided(id) })
+	for id, fhUser := range other.Users.Link {
+		a.LinkUser(id, fhUser)
+	}
+}
+
+// Ask is called before prompting for a question which the answers do not cover.
+// It returns ErrUnanswered in non-interactive mode.
+func (a *Answers) Ask(question string) error {
+	if a.NonInteractive {
+		return fmt.Errorf("%w: %s", ErrUnanswered, question)
+	}
+	return nil
+}
+
+// TeamLink returns the FireHydrant team (or NewTeam) which the given provider team is linked to.
+func (a *Answers) TeamLink(id, name string) (string, bool) {
+	return lookup(a.TeamLinks, id, name)
+}
+
+// LinkTeam records the FireHydrant team (or NewTeam) which the given provider team is linked to.
+func (a *Answers) LinkTeam(id, fhTeam string) {
+	if a.TeamLinks == nil {
+		a.TeamLinks = map[string]string{}
+	}
+	set(a.TeamLinks, id, fhTeam)
+}
+
+// DefaultEscalationPolicy returns the escalation policy which is the default of the given provider
+// team.
+func (a *Answers) DefaultEscalationPolicy(id, name string) (string, bool) {
+	return lookup(a.DefaultEscalationPolicies, id, name)
+}
+
+// SetDefaultEscalationPolicy records the escalation policy which is the default of the given
+// provider team.
+func (a *Answers) SetDefaultEscalationPolicy(id, policy string) {
+	if a.DefaultEscalationPolicies == nil {
+		a.DefaultEscalationPolicies = map[string]string{}
+	}
+	set(a.DefaultEscalationPolicies, id, policy)
+}
+
+// LinkUser records the FireHydrant user which the given unmatched provider user is linked to.
+func (a *Answers) LinkUser(id, fhUser string) {
+	if a.Users.Link == nil {
+		a.Users.Link = map[string]string{}
+	}
+	set(a.Users.Link, id, fhUser)
+}
+
+// Answered reports whether the selection has been declared.
+func (s Selection) Answered() bool {
+	return s.All || s.None || len(s.Include) > 0 || len(s.Exclude) > 0
+}
+
+// Selects reports whether a resource identified by any of keys is part of the selection.
+// When only exclusions are declared, everything else is selected.
+func (s Selection) Selects(keys ...string) bool {
+	switch {
+	case s.None:
+		return false
+	case contains(s.Exclude, keys...):
+		return false
+	case s.All || len(s.Include) == 0:
+		return true
+	default:
+		return contains(s.Include, keys...)
+	}
+}
+
+// Decide returns the decision (UserCreate, UserSkip or UserLink) for an unmatched user and, when
+// linking, the FireHydrant user ID or email to link to.
+func (u Users) Decide(id, email string) (decision string, fhUser string, ok bool) {
+	if fhUser, ok := lookup(u.Link, id, email); ok {
+		return UserLink, fhUser, true
+	}
+	if contains(u.Create, id, email) {
+		return UserCreate, "", true
+	}
+	if contains(u.Skip, id, email) {
+		return UserSkip, "", true
+	}
+	if u.Default != "" {
+		return u.Default, "", true
+	}
+	return "", "", false
+}
+
+func contains(list []string, keys ...string) bool {
+	for _, v := range list {
+		for _, k := range keys {
+			if k != "" && strings.EqualFold(v, k) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// set sets the value of key in m, replacing the value of any key which only differs by case, so
+// that lookup finds a single value for it.
+func set(m map[string]string, key, value string) {
+	maps.DeleteFunc(m, func(k, _ string) bool { return strings.EqualFold(k, key) })
+	m[key] = value
+}
+
+func lookup(m map[string]string, keys ...string) (string, bool) {
+	for _, k := range keys {
+		if k == "" {
+			continue
+		}
+		if v, ok := m[k]; ok {
+			return v, true
+		}
+		for mk, v := range m {
+			if strings.EqualFold(mk, k) {
+				return v, true
+			}
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,238 @@
+package answers_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/firehydrant/signals-migrator/answers"
+)
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoad_YAML(t *testing.T) {
+	path := writeFile(t, "answers.yaml", `
+team_interface: service
+continue_without_team_members: true
+teams:
+  include: [P1TEAM, "Platform Team"]
+team_links:
+  P1TEAM: platform
+  Platform Team: new
+users:
+  default: skip
+  create: [jane@example.com]
+  link:
+    PUSER01: john.doe@example.com
+escalation_policies:
+  exclude: [PPOLICY]
+default_escalation_policies:
+  Platform Team: Platform Primary
+`)
+	a, err := answers.Load(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if a.TeamInterface != "service" {
+		t.Errorf("team interface: got %q", a.TeamInterface)
+	}
+	if a.ContinueWithoutTeamMembers == nil || !*a.ContinueWithoutTeamMembers {
+		t.Errorf("continue without team members: got %v", a.ContinueWithoutTeamMembers)
+	}
+	if link, ok := a.TeamLink("p1team", "Whatever"); !ok || link != "platform" {
+		t.Errorf("team link by ID: got %q, %v", link, ok)
+	}
+	if link, ok := a.TeamLink("P2TEAM", "platform team"); !ok || link != answers.NewTeam {
+		t.Errorf("team link by name: got %q, %v", link, ok)
+	}
+	if _, ok := a.TeamLink("P3TEAM", "Other"); ok {
+		t.Errorf("expected no team link for unlisted team")
+	}
+	if policy, ok := a.DefaultEscalationPolicy("P2TEAM", "platform team"); !ok || policy != "Platform Primary" {
+		t.Errorf("default escalation policy by name: got %q, %v", policy, ok)
+	}
+}
+
+func TestLoad_JSON(t *testing.T) {
+	path := writeFile(t, "answers.json", `{
+	"teams": {"all": true},
+	"escalation_policies": {"none": true}
+}`)
+	a, err := answers.Load(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !a.Teams.Selects("ANY") {
+		t.Errorf("expected all teams to be selected")
+	}
+	if a.EscalationPolicies.Selects("ANY") {
+		t.Errorf("expected no escalation policies to be selected")
+	}
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	for name, content := range map[string]string{
+		"unknown.yaml":       "team_links_typo: {}\n",
+		"default.yaml":       "users:\n  default: link\n",
+		"all_and_none.yaml":  "teams:\n  all: true\n  none: true\n",
+		"unknown_field.json": `{"nope": 1}`,
+		"case.yaml":          "team_links:\n  Platform: platform\n  platform: new\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := answers.Load(writeFile(t, name, content)); err == nil {
+				t.Errorf("expected error")
+			}
+		})
+	}
+}
+
+func TestSaveRoundTrip(t *testing.T) {
+	yes := true
+	want := &answers.Answers{
+		TeamInterface:              "team",
+		ContinueWithoutTeamMembers: &yes,
+		Teams:                      answers.Selection{Include: []string{"P1", "P2"}},
+		TeamLinks:                  map[string]string{"P1": "fh-team-1", "P2": answers.NewTeam},
+		Users: answers.Users{
+			Create: []string{"U1"},
+			Skip:   []string{"U2"},
+			Link:   map[string]string{"U3": "fh-user-3"},
+		},
+		EscalationPolicies:        answers.Selection{All: true},
+		DefaultEscalationPolicies: map[string]string{"P1": "EP1"},
+	}
+	for _, name := range []string{"answers.yaml", "answers.json"} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), name)
+			if err := want.Save(path); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			got, err := answers.Load(path)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("got %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestSelection(t *testing.T) {
+	tests := []struct {
+		name     string
+		s        answers.Selection
+		answered bool
+		selected []string
+	}{
+		{name: "empty", s: answers.Selection{}, answered: false, selected: []string{"P1", "P2", "P3"}},
+		{name: "include", s: answers.Selection{Include: []string{"P1", "team two"}}, answered: true, selected: []string{"P1", "P2"}},
+		{name: "exclude", s: answers.Selection{Exclude: []string{"P1"}}, answered: true, selected: []string{"P2", "P3"}},
+		{name: "include and exclude", s: answers.Selection{Include: []string{"P1", "P2"}, Exclude: []string{"p2"}}, answered: true, selected: []string{"P1"}},
+		{name: "all but excluded", s: answers.Selection{All: true, Exclude: []string{"P3"}}, answered: true, selected: []string{"P1", "P2"}},
+		{name: "none", s: answers.Selection{None: true}, answered: true, selected: nil},
+	}
+	resources := [][2]string{{"P1", "Team One"}, {"P2", "Team Two"}, {"P3", "Team Three"}}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.s.Answered(); got != tc.answered {
+				t.Errorf("answered: got %v, want %v", got, tc.answered)
+			}
+			var selected []string
+			for _, r := range resources {
+				if tc.s.Selects(r[0], r[1]) {
+					selected = append(selected, r[0])
+				}
+			}
+			if !reflect.DeepEqual(selected, tc.selected) {
+				t.Errorf("selected: got %v, want %v", selected, tc.selected)
+			}
+		})
+	}
+}
+
+func TestUsersDecide(t *testing.T) {
+	u := answers.Users{
+		Create: []string{"PCREATE"},
+		Skip:   []string{"skip@example.com"},
+		Link:   map[string]string{"Link@Example.com": "fh-user"},
+	}
+	tests := []struct {
+		id, email        string
+		decision, fhUser string
+		ok               bool
+	}{
+		{id: "PCREATE", email: "create@example.com", decision: answers.UserCreate, ok: true},
+		{id: "PSKIP", email: "SKIP@example.com", decision: answers.UserSkip, ok: true},
+		{id: "PLINK", email: "link@example.com", decision: answers.UserLink, fhUser: "fh-user", ok: true},
+		{id: "POTHER", email: "other@example.com", ok: false},
+	}
+	for _, tc := range tests {
+		decision, fhUser, ok := u.Decide(tc.id, tc.email)
+		if decision != tc.decision || fhUser != tc.fhUser || ok != tc.ok {
+			t.Errorf("%s: got (%q, %q, %v), want (%q, %q, %v)", tc.id, decision, fhUser, ok, tc.decision, tc.fhUser, tc.ok)
+		}
+	}
+
+	u.Default = answers.UserCreate
+	if decision, _, ok := u.Decide("POTHER", "other@example.com"); !ok || decision != answers.UserCreate {
+		t.Errorf("default: got (%q, %v)", decision, ok)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	yes := true
+	a := &answers.Answers{
+		TeamInterface: "service",
+		Teams:         answers.Selection{All: true},
+		TeamLinks:     map[string]string{"P1TEAM": "platform", "P2TEAM": answers.NewTeam},
+		Users: answers.Users{
+			Create: []string{"PUSER01", "PUSER02"},
+			Link:   map[string]string{"PUSER03": "fh-user-3"},
+		},
+	}
+	a.Merge(&answers.Answers{
+		ContinueWithoutTeamMembers: &yes,
+		Teams:                      answers.Selection{Exclude: []string{"P2TEAM"}},
+		TeamLinks:                  map[string]string{"p2team": "payments"},
+		Users: answers.Users{
+			Skip: []string{"puser01"},
+			Link: map[string]string{"PUSER02": "fh-user-2"},
+		},
+	})
+
+	expected := &answers.Answers{
+		TeamInterface:              "service",
+		ContinueWithoutTeamMembers: &yes,
+		Teams:                      answers.Selection{Exclude: []string{"P2TEAM"}},
+		TeamLinks:                  map[string]string{"P1TEAM": "platform", "p2team": "payments"},
+		Users: answers.Users{
+			Create: []string{},
+			Skip:   []string{"puser01"},
+			Link:   map[string]string{"PUSER02": "fh-user-2", "PUSER03": "fh-user-3"},
+		},
+	}
+	if !reflect.DeepEqual(a, expected) {
+		t.Errorf("got %+v, want %+v", a, expected)
+	}
+}
+
+func TestAsk(t *testing.T) {
+	a := &answers.Answers{}
+	if err := a.Ask("anything"); err != nil {
+		t.Errorf("interactive: unexpected error: %s", err)
+	}
+	a.NonInteractive = true
+	if err := a.Ask("anything"); !errors.Is(err, answers.ErrUnanswered) {
+		t.Errorf("non-interactive: got %v, want ErrUnanswered", err)
+	}
+}
@@ -0,0 +1,255 @@
+// Package apply creates the imported resources directly in FireHydrant through its API, as an
+// alternative to rendering Terraform configuration with tfrender.
+//
+// Resources are created in dependency order: teams, then on-call schedules, their rotations and
+// upcoming overrides, then escalation policies. Every created resource is recorded in the store by its source ID, so
+// re-running an import with the same state file only creates what is still missing.
+package apply
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/firehydrant/firehydrant-go-sdk/models/components"
+	"github.com/firehydrant/signals-migrator/console"
+	"github.com/firehydrant/signals-migrator/store"
+)
+
+const (
+	ResourceTeam             = "team"
+	ResourceOnCallSchedule   = "on_call_schedule"
+	ResourceRotation         = "rotation"
+	ResourceOverride         = "override"
+	ResourceEscalationPolicy = "escalation_policy"
+)
+
+const (
+	// StatusCreated means the resource was created by this run.
+	StatusCreated = "created"
+	// StatusExisting means the resource was created by a previous run, or linked to an existing
+	// FireHydrant resource during import.
+	StatusExisting = "existing"
+	// StatusSkipped means the resource depends on another one which could not be applied.
+	StatusSkipped = "skipped"
+	// StatusFailed means the FireHydrant API rejected the resource.
+	StatusFailed = "failed"
+)
+
+// Client is the part of the FireHydrant API used to create resources.
+// It is implemented by firehydrant.Client.
+type Client interface {
+	CreateTeam(ctx context.Context, team components.CreateTeam) (string, error)
+	CreateOnCallSchedule(ctx context.Context, teamID string, schedule components.CreateTeamOnCallSchedule) (string, []string, error)
+	CreateRotation(ctx context.Context, teamID string, scheduleID string, rotation components.CreateOnCallScheduleRotation) (string, error)
+	CreateOverride(ctx context.Context, teamID string, scheduleID string, rotationID string, override components.OverrideOnCallScheduleRotationShifts) (string, error)
+	CreateEscalationPolicy(ctx context.Context, teamID string, policy components.CreateTeamEscalationPolicy) (string, error)
+}
+
+// Step is a single resource to be created in FireHydrant.
+type Step struct {
+	Resource  string   `json:"resource"`
+	SourceID  string   `json:"source_id"`
+	Name      string   `json:"name"`
+	DependsOn []string `json:"depends_on,omitempty"`
+
+	// existingID is set for resources which were linked to an existing FireHydrant resource.
+	existingID string
+	create     func(ctx context.Context) (string, error)
+	// createdAlong is set by create to the resources it created along with this one, which are
+	// recorded in the same write.
+	createdAlong []store.InsertAppliedResourceParams
+}
+
+// Key identifies the step within a plan.
+func (s *Step) Key() string {
+	return stepKey(s.Resource, s.SourceID)
+}
+
+func stepKey(resource string, sourceID string) string {
+	return resource + "/" + sourceID
+}
+
+// Result is the outcome of applying a single step.
+type Result struct {
+	Resource string `json:"resource"`
+	SourceID string `json:"source_id"`
+	Name     string `json:"name"`
+	Status   string `json:"status"`
+	FhID     string `json:"fh_id,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+type Applier struct {
+	client Client
+	now    func() time.Time
+
+	// FireHydrant IDs of the applied steps, by step key.
+	ids map[string]string
+	// Team step for each external team ID, as teams may be merged together in FireHydrant.
+	teamSteps map[string]*Step
+	// All planned steps, by step key.
+	steps map[string]*Step
+	// Keys of the steps whose resource was created along with another one by this run.
+	createdAlong map[string]bool
+}
+
+func New(client Client) *Applier {
+	return &Applier{
+		client:       client,
+		now:          time.Now,
+		ids:          map[string]string{},
+		teamSteps:    map[string]*Step{},
+		steps:        map[string]*Step{},
+		createdAlong: map[string]bool{},
+	}
+}
+
+// SetNow overrides the clock used to skip past overrides, so tests can pin it.
+func (a *Applier) SetNow(now func() time.Time) {
+	a.now = now
+}
+
+// Apply creates every planned resource which was not applied yet and returns the result for each.
+// Failures of individual resources are reported in the results rather than as an error, and the
+// resources depending on them are skipped.
+func (a *Applier) Apply(ctx context.Context) ([]Result, error) {
+	plan, err := a.Plan(ctx)
+	if err != nil {
+		return nil, err
+	}
+	console.Infof("Applying %d resources to FireHydrant...\n", len(plan))
+
+	results := make([]Result, 0, len(plan))
+	for _, step := range plan {
+		// An interrupted run stops between steps, and is resumed by running it again.
+		if err := ctx.Err(); err != nil {
+			return results, err
+		}
+		res, err := a.applyStep(ctx, step)
+		if err != nil {
+			return results, err
+		}
+		printResult(res)
+		results = append(results, res)
+	}
+	return results, nil
+}
+
+func (a *Applier) applyStep(ctx context.Context, step *Step) (Result, error) {
+	res := Result{Resource: step.Resource, SourceID: step.SourceID, Name: step.Name}
+	q := store.UseQueries(ctx)
+
+	fhID, err := q.GetAppliedResource(ctx, store.GetAppliedResourceParams{ResourceType: step.Resource, SourceID: step.SourceID})
+	switch {
+	case err == nil:
+	case errors.Is(err, sql.ErrNoRows):
+		fhID = step.existingID
+	default:
+		return res, fmt.Errorf("querying applied %s '%s': %w", step.Resource, step.Name, err)
+	}
+	if fhID != "" {
+		a.ids[step.Key()] = fhID
+		res.Status, res.FhID = StatusExisting, fhID
+		if a.createdAlong[step.Key()] {
+			res.Status = StatusCreated
+		}
+		return res, nil
+	}
+
+	for _, dep := range step.DependsOn {
+		if a.ids[dep] == "" {
+			res.Status = StatusSkipped
+			res.Error = fmt.Sprintf("depends on %s which was not applied", dep)
+			return res, nil
+		}
+	}
+
+	fhID, err = step.create(ctx)
+	if err != nil {
+		res.Status, res.Error = StatusFailed, err.Error()
+		return res, nil
+	}
+	// The resource exists in FireHydrant now, so it is recorded even when the run is interrupted.
+	applied := append([]store.InsertAppliedResourceParams{{
+		ResourceType: step.Resource,
+		SourceID:     step.SourceID,
+		FhID:         fhID,
+	}}, step.createdAlong...)
+	if err := store.RecordAppliedResources(context.WithoutCancel(ctx), applied...); err != nil {
+		return res, err
+	}
+	for _, r := range step.createdAlong {
+		a.createdAlong[stepKey(r.ResourceType, r.SourceID)] = true
+	}
+	a.ids[step.Key()] = fhID
+	res.Status, res.FhID = StatusCreated, fhID
+	return res, nil
+}
+
+func printResult(res Result) {
+	switch res.Status {
+	case StatusCreated:
+		console.Successf("[+] Created %s '%s' (%s).\n", res.Resource, res.Name, res.FhID)
+	case StatusExisting:
+		console.Infof("[=] Found existing %s '%s' (%s).\n", res.Resource, res.Name, res.FhID)
+	case StatusSkipped:
+		console.Warnf("[<] Skipped %s '%s': %s\n", res.Resource, res.Name, res.Error)
+	case StatusFailed:
+		console.Errorf("[!] Failed to create %s '%s': %s\n", res.Resource, res.Name, res.Error)
+	}
+}
+
+// Plan lists the resources to create, ordered so that every step comes after the steps it
+// depends on.
+func (a *Applier) Plan(ctx context.Context) ([]*Step, error) {
+	var steps []*Step
+	for _, plan := range []func(context.Context) ([]*Step, error){
+		a.planTeams,
+		a.planOnCallSchedules,
+		a.planEscalationPolicies,
+	} {
+		s, err := plan(ctx)
+		if err != nil {
+			return nil, err
+		}
+		steps = append(steps, s...)
+	}
+	return sortSteps(steps), nil
+}
+
+func (a *Applier) addStep(step *Step) *Step {
+	a.steps[step.Key()] = step
+	return step
+}
+
+// sortSteps orders the steps by their dependencies, otherwise keeping them in planned order.
+// Steps in a dependency cycle are left at the end, where they will be skipped.
+func sortSteps(steps []*Step) []*Step {
+	sorted := make([]*Step, 0, len(steps))
+	done := map[string]bool{}
+	for len(steps) > 0 {
+		var rest []*Step
+		for _, step := range steps {
+			ready := true
+			for _, dep := range step.DependsOn {
+				if !done[dep] {
+					ready = false
+				}
+			}
+			if ready {
+				sorted = append(sorted, step)
+				done[step.Key()] = true
+			} else {
+				rest = append(rest, step)
+			}
+		}
+		if len(rest) == len(steps) {
+			return append(sorted, rest...)
+		}
+		steps = rest
+	}
+	return sorted
+}
@@ -0,0 +1,260 @@
+package apply_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/firehydrant/firehydrant-go-sdk/models/components"
+	"github.com/firehydrant/signals-migrator/apply"
+	"github.com/firehydrant/signals-migrator/internal/testkit"
+	"github.com/firehydrant/signals-migrator/store"
+)
+
+type request struct {
+	Method     string `json:"method"`
+	TeamID     string `json:"team_id,omitempty"`
+	ScheduleID string `json:"schedule_id,omitempty"`
+	RotationID string `json:"rotation_id,omitempty"`
+	Body       any    `json:"body"`
+}
+
+// fakeClient records every request and returns sequential IDs, failing the resources named in fail
+// and calling interrupt once the resource named in interruptAt is created.
+type fakeClient struct {
+	requests    []request
+	fail        map[string]bool
+	interruptAt string
+	interrupt   context.CancelFunc
+}
+
+func (c *fakeClient) record(req request, name string) (string, error) {
+	c.requests = append(c.requests, req)
+	if c.fail[name] {
+		return "", fmt.Errorf("creating '%s': bad request", name)
+	}
+	if name == c.interruptAt {
+		c.interrupt()
+	}
+	return fmt.Sprintf("fh-%d", len(c.requests)), nil
+}
+
+func (c *fakeClient) CreateTeam(_ context.Context, team components.CreateTeam) (string, error) {
+	return c.record(request{Method: "CreateTeam", Body: team}, team.Name)
+}
+
+func (c *fakeClient) CreateOnCallSchedule(_ context.Context, teamID string, schedule components.CreateTeamOnCallSchedule) (string, []string, error) {
+	id, err := c.record(request{Method: "CreateOnCallSchedule", TeamID: teamID, Body: schedule}, schedule.Name)
+	if err != nil {
+		return "", nil, err
+	}
+	rotationIDs := []string{}
+	for i := range schedule.Rotations {
+		rotationIDs = append(rotationIDs, fmt.Sprintf("%s-rotation-%d", id, i))
+	}
+	return id, rotationIDs, nil
+}
+
+func (c *fakeClient) CreateRotation(_ context.Context, teamID string, scheduleID string, rotation components.CreateOnCallScheduleRotation) (string, error) {
+	return c.record(request{Method: "CreateRotation", TeamID: teamID, ScheduleID: scheduleID, Body: rotation}, rotation.Name)
+}
+
+func (c *fakeClient) CreateOverride(_ context.Context, teamID string, scheduleID string, rotationID string, override components.OverrideOnCallScheduleRotationShifts) (string, error) {
+	return c.record(request{Method: "CreateOverride", TeamID: teamID, ScheduleID: scheduleID, RotationID: rotationID, Body: override}, override.StartTime)
+}
+
+func (c *fakeClient) CreateEscalationPolicy(_ context.Context, teamID string, policy components.CreateTeamEscalationPolicy) (string, error) {
+	return c.record(request{Method: "CreateEscalationPolicy", TeamID: teamID, Body: policy}, policy.Name)
+}
+
+func seedStore(t *testing.T) context.Context {
+	t.Helper()
+
+	ctx := testkit.NewStore(t, context.Background())
+	seed, err := os.ReadFile(filepath.Join("testdata", "seed.sql"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.FromContext(ctx).ExecContext(ctx, string(seed)); err != nil {
+		t.Fatal(err)
+	}
+	return ctx
+}
+
+func newApplier(client apply.Client) *apply.Applier {
+	a := apply.New(client)
+	a.SetNow(func() time.Time { return time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC) })
+	return a
+}
+
+func statuses(results []apply.Result) map[string]string {
+	m := map[string]string{}
+	for _, r := range results {
+		m[r.Resource+"/"+r.SourceID] = r.Status
+	}
+	return m
+}
+
+func TestApply(t *testing.T) {
+	ctx := seedStore(t)
+
+	client := &fakeClient{}
+	results, err := newApplier(client).Apply(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("results", func(t *testing.T) {
+		testkit.GoldenJSON(t, results)
+	})
+	t.Run("requests", func(t *testing.T) {
+		testkit.GoldenJSON(t, client.requests)
+	})
+
+	t.Run("rerun", func(t *testing.T) {
+		rerunClient := &fakeClient{}
+		rerun, err := newApplier(rerunClient).Apply(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(rerunClient.requests) != 0 {
+			t.Errorf("expected no requests on re-run, got %d", len(rerunClient.requests))
+		}
+		if len(rerun) != len(results) {
+			t.Fatalf("expected %d results, got %d", len(results), len(rerun))
+		}
+		for i, r := range rerun {
+			if r.Status != apply.StatusExisting || r.FhID != results[i].FhID {
+				t.Errorf("expected %s '%s' to exist as %s, got %+v", r.Resource, r.Name, results[i].FhID, r)
+			}
+		}
+	})
+}
+
+func TestApplySkipsDependentsOfFailures(t *testing.T) {
+	ctx := seedStore(t)
+
+	client := &fakeClient{fail: map[string]bool{"Payments on-call": true}}
+	results, err := newApplier(client).Apply(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := statuses(results)
+	want := map[string]string{
+		"team/PPAYMENTS":              apply.StatusCreated,
+		"team/PPLATFORM":              apply.StatusExisting,
+		"on_call_schedule/PSCHED":     apply.StatusFailed,
+		"rotation/PLAYER1":            apply.StatusSkipped,
+		"rotation/PLAYER2":            apply.StatusSkipped,
+		"override/POVERRIDE1/PLAYER1": apply.StatusSkipped,
+		"escalation_policy/PPOLICY2":  apply.StatusCreated,
+		"escalation_policy/PPOLICY1":  apply.StatusSkipped,
+	}
+	for key, status := range want {
+		if got[key] != status {
+			t.Errorf("%s: expected %s, got %s", key, status, got[key])
+		}
+	}
+
+	// Fixing the failure and running again only creates what is still missing.
+	retryClient := &fakeClient{}
+	results, err = newApplier(retryClient).Apply(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(retryClient.requests) != 7 {
+		t.Errorf("expected 7 requests on retry, got %d", len(retryClient.requests))
+	}
+	for _, r := range results {
+		if r.Status != apply.StatusCreated && r.Status != apply.StatusExisting {
+			t.Errorf("expected %s '%s' to be applied, got %+v", r.Resource, r.Name, r)
+		}
+	}
+}
+
+func TestApplyReusesExistingResources(t *testing.T) {
+	ctx := seedStore(t)
+	q := store.UseQueries(ctx)
+	for _, existing := range []store.InsertFhExistingResourceParams{
+		{ResourceType: store.EXISTING_RESOURCE_SCHEDULE, SourceID: "PSCHED", FhID: "fh-schedule", ImportID: "fh-team-platform:fh-schedule"},
+		{ResourceType: store.EXISTING_RESOURCE_ROTATION, SourceID: "PLAYER2", FhID: "fh-layer-2", ImportID: "fh-team-platform:fh-schedule:fh-layer-2"},
+		{ResourceType: store.EXISTING_RESOURCE_ESCALATION_POLICY, SourceID: "PPOLICY2", FhID: "fh-policy", ImportID: "fh-team-platform:fh-policy"},
+	} {
+		if err := q.InsertFhExistingResource(ctx, existing); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	client := &fakeClient{}
+	results, err := newApplier(client).Apply(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := statuses(results)
+	want := map[string]string{
+		"team/PPAYMENTS":             apply.StatusCreated,
+		"on_call_schedule/PSCHED":    apply.StatusExisting,
+		"rotation/PLAYER1":           apply.StatusCreated,
+		"rotation/PLAYER2":           apply.StatusExisting,
+		"escalation_policy/PPOLICY2": apply.StatusExisting,
+		"escalation_policy/PPOLICY1": apply.StatusCreated,
+	}
+	for key, status := range want {
+		if got[key] != status {
+			t.Errorf("%s: expected %s, got %s", key, status, got[key])
+		}
+	}
+
+	// The first rotation is added to the existing schedule, since it is not created along with it.
+	for _, req := range client.requests {
+		switch req.Method {
+		case "CreateOnCallSchedule":
+			t.Errorf("expected the existing schedule not to be created again, got %+v", req)
+		case "CreateRotation", "CreateOverride":
+			if req.ScheduleID != "fh-schedule" {
+				t.Errorf("expected %s in the existing schedule, got %+v", req.Method, req)
+			}
+		}
+	}
+}
+
+func TestApplyResumesAfterInterruption(t *testing.T) {
+	ctx := seedStore(t)
+
+	// The run is interrupted right after the schedule is created, before its first rotation step.
+	interrupted, cancel := context.WithCancel(ctx)
+	defer cancel()
+	client := &fakeClient{interruptAt: "Payments on-call", interrupt: cancel}
+	if _, err := newApplier(client).Apply(interrupted); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected the run to be interrupted, got %v", err)
+	}
+	scheduleID := fmt.Sprintf("fh-%d", len(client.requests))
+
+	resumeClient := &fakeClient{}
+	results, err := newApplier(resumeClient).Apply(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range results {
+		if r.Status != apply.StatusCreated && r.Status != apply.StatusExisting {
+			t.Errorf("expected %s '%s' to be applied, got %+v", r.Resource, r.Name, r)
+		}
+		if r.Resource == apply.ResourceRotation && r.SourceID == "PLAYER1" && r.FhID != scheduleID+"-rotation-0" {
+			t.Errorf("expected the first rotation to be recorded along with its schedule, got %+v", r)
+		}
+	}
+	for _, req := range resumeClient.requests {
+		if req.Method == "CreateOnCallSchedule" {
+			t.Errorf("expected the schedule not to be created again, got %+v", req)
+		}
+		if req.Method == "CreateOverride" && req.ScheduleID != scheduleID {
+			t.Errorf("expected overrides in the created schedule, got %+v", req)
+		}
+	}
+}
@@ -0,0 +1,506 @@
+package apply
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/firehydrant/firehydrant-go-sdk/models/components"
+	"github.com/firehydrant/signals-migrator/diagnostics"
+	"github.com/firehydrant/signals-migrator/store"
+)
+
+func (a *Applier) planTeams(ctx context.Context) ([]*Step, error) {
+	q := store.UseQueries(ctx)
+	extTeams, err := q.ListTeamsToImport(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("querying teams: %w", err)
+	}
+
+	// Same as in tfrender, teams sharing a name and the member teams of a "group team" are merged
+	// into a single FireHydrant team, with their members consolidated.
+	steps := []*Step{}
+	byName := map[string]*Step{}
+	members := map[string][]string{}
+	for _, t := range extTeams {
+		name := t.ValidName()
+		step, ok := byName[name]
+		if !ok {
+			step = a.addStep(&Step{Resource: ResourceTeam, SourceID: t.ID, Name: name})
+			if t.FhTeamID.Valid && t.FhTeamID.String != "" {
+				step.existingID = t.FhTeamID.String
+			}
+			byName[name] = step
+			steps = append(steps, step)
+		}
+
+		memberTeams, err := q.ListMemberExtTeams(ctx, t.ID)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("querying member teams: %w", err)
+		}
+		teamIDs := []string{t.ID}
+		for _, mt := range memberTeams {
+			teamIDs = append(teamIDs, mt.ID)
+		}
+		for _, teamID := range teamIDs {
+			a.teamSteps[teamID] = step
+			fhMembers, err := q.ListFhMembersByExtTeamID(ctx, teamID)
+			if err != nil {
+				return nil, fmt.Errorf("querying team members: %w", err)
+			}
+			for _, m := range fhMembers {
+				members[name] = appendUnique(members[name], m.ID)
+			}
+		}
+	}
+
+	for _, step := range steps {
+		team := components.CreateTeam{Name: step.Name}
+		for _, userID := range members[step.Name] {
+			team.Memberships = append(team.Memberships, components.CreateTeamMembership{UserID: &userID})
+		}
+		step.create = func(ctx context.Context) (string, error) {
+			return a.client.CreateTeam(ctx, team)
+		}
+	}
+	return steps, nil
+}
+
+func (a *Applier) planOnCallSchedules(ctx context.Context) ([]*Step, error) {
+	q := store.UseQueries(ctx)
+	schedules, err := q.ListExtSchedulesV2(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("querying schedules: %w", err)
+	}
+
+	steps := []*Step{}
+	for _, s := range schedules {
+		teamStep, ok := a.teamSteps[s.TeamID]
+		if !ok {
+			diagnostics.Warnf(ctx, diagnostics.ResourceSchedule, s.ID, "Skipping schedule '%s', its team '%s' is not imported.\n", s.Name, s.TeamID)
+			continue
+		}
+		rotations, err := q.ListExtRotationsByScheduleID(ctx, s.ID)
+		if err != nil {
+			return nil, fmt.Errorf("querying rotations: %w", err)
+		}
+		requests := make([]components.CreateOnCallScheduleRotation, 0, len(rotations))
+		for _, r := range rotations {
+			req, err := rotationRequest(ctx, r, s.Timezone)
+			if err != nil {
+				return nil, err
+			}
+			requests = append(requests, req)
+		}
+
+		scheduleStep := a.addStep(&Step{
+			Resource:  ResourceOnCallSchedule,
+			SourceID:  s.ID,
+			Name:      s.Name,
+			DependsOn: []string{teamStep.Key()},
+		})
+		if scheduleStep.existingID, err = store.ExistingFhID(ctx, store.EXISTING_RESOURCE_SCHEDULE, s.ID); err != nil {
+			return nil, err
+		}
+		scheduleStep.create = func(ctx context.Context) (string, error) {
+			if len(requests) == 0 {
+				return "", fmt.Errorf("schedule has no rotations")
+			}
+			// Like in tfrender, the first rotation is created along with the schedule.
+			schedule := components.CreateTeamOnCallSchedule{
+				Name:      s.Name,
+				Rotations: []components.CreateTeamOnCallScheduleRotation{teamScheduleRotation(requests[0])},
+			}
+			if s.Description != "" {
+				schedule.Description = &s.Description
+			}
+			id, rotationIDs, err := a.client.CreateOnCallSchedule(ctx, a.ids[teamStep.Key()], schedule)
+			if err != nil {
+				return "", err
+			}
+			// The first rotation is recorded along with the schedule, as its ID is only returned
+			// by this request.
+			if len(rotationIDs) > 0 {
+				scheduleStep.createdAlong = []store.InsertAppliedResourceParams{{
+					ResourceType: ResourceRotation,
+					SourceID:     rotations[0].ID,
+					FhID:         rotationIDs[0],
+				}}
+			}
+			return id, nil
+		}
+		steps = append(steps, scheduleStep)
+
+		rotationSteps := make([]*Step, 0, len(rotations))
+		for i, r := range rotations {
+			rotationStep := a.addStep(&Step{
+				Resource:  ResourceRotation,
+				SourceID:  r.ID,
+				Name:      r.Name,
+				DependsOn: []string{teamStep.Key(), scheduleStep.Key()},
+			})
+			if rotationStep.existingID, err = store.ExistingFhID(ctx, store.EXISTING_RESOURCE_ROTATION, r.ID); err != nil {
+				return nil, err
+			}
+			// An existing schedule keeps its rotations, so the first rotation is only created
+			// along with the schedule when the schedule is created too.
+			if i == 0 && scheduleStep.existingID == "" {
+				// The first rotation is recorded along with its schedule, so it is only created here
+				// when the schedule was created without returning the ID of the rotation.
+				rotationStep.create = func(context.Context) (string, error) {
+					return "", fmt.Errorf("rotation was created along with its schedule, but its ID is unknown")
+				}
+			} else {
+				req := requests[i]
+				rotationStep.create = func(ctx context.Context) (string, error) {
+					return a.client.CreateRotation(ctx, a.ids[teamStep.Key()], a.ids[scheduleStep.Key()], req)
+				}
+			}
+			rotationSteps = append(rotationSteps, rotationStep)
+		}
+		steps = append(steps, rotationSteps...)
+
+		overrideSteps, err := a.planOverrides(ctx, s, teamStep, scheduleStep, rotationSteps)
+		if err != nil {
+			return nil, err
+		}
+		steps = append(steps, overrideSteps...)
+	}
+	return steps, nil
+}
+
+// planOverrides plans the upcoming overrides of a schedule. Overrides replace whoever is on call in
+// the source schedule, while all rotations of a FireHydrant schedule are on call at once, so each
+// override is applied to every rotation of the schedule.
+func (a *Applier) planOverrides(ctx context.Context, s store.ExtSchedulesV2, teamStep *Step, scheduleStep *Step, rotationSteps []*Step) ([]*Step, error) {
+	q := store.UseQueries(ctx)
+	overrides, err := q.ListExtScheduleOverridesByExtScheduleID(ctx, s.ID)
+	if err != nil {
+		return nil, fmt.Errorf("querying overrides: %w", err)
+	}
+
+	steps := []*Step{}
+	for _, o := range overrides {
+		u, err := q.GetUserByEmail(ctx, o.Username)
+		if err != nil || !u.FhUserID.Valid {
+			diagnostics.Warnf(ctx, diagnostics.ResourceOverride, o.ID, "Skipping override %s of %s, user '%s' is not imported.\n", o.ID, s.Name, o.Username)
+			continue
+		}
+		start, err := parseOverrideTime(o.StartTime)
+		if err != nil {
+			diagnostics.Warnf(ctx, diagnostics.ResourceOverride, o.ID, "Skipping override %s of %s: %s\n", o.ID, s.Name, err)
+			continue
+		}
+		end, err := parseOverrideTime(o.EndTime)
+		if err != nil {
+			diagnostics.Warnf(ctx, diagnostics.ResourceOverride, o.ID, "Skipping override %s of %s: %s\n", o.ID, s.Name, err)
+			continue
+		}
+		now := a.now()
+		if !end.After(now) {
+			continue
+		}
+		// FireHydrant does not override the past, so overrides already in progress start now.
+		if start.Before(now) {
+			start = now
+		}
+		override := components.OverrideOnCallScheduleRotationShifts{
+			StartTime: start.Format(time.RFC3339),
+			EndTime:   end.Format(time.RFC3339),
+			UserID:    &u.FhUserID.String,
+		}
+
+		for _, rotationStep := range rotationSteps {
+			overrideStep := a.addStep(&Step{
+				Resource:  ResourceOverride,
+				SourceID:  o.ID + "/" + rotationStep.SourceID,
+				Name:      fmt.Sprintf("%s in %s", o.Username, rotationStep.Name),
+				DependsOn: []string{teamStep.Key(), scheduleStep.Key(), rotationStep.Key()},
+			})
+			overrideStep.create = func(ctx context.Context) (string, error) {
+				return a.client.CreateOverride(ctx, a.ids[teamStep.Key()], a.ids[scheduleStep.Key()], a.ids[rotationStep.Key()], override)
+			}
+			steps = append(steps, overrideStep)
+		}
+	}
+	return steps, nil
+}
+
+// parseOverrideTime parses override times as stored by the providers: ISO 8601 for PagerDuty and
+// RFC 1123 for Opsgenie.
+func parseOverrideTime(value string) (time.Time, error) {
+	for _, layout := range []string{time.RFC3339, time.RFC1123Z} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unrecognized time '%s'", value)
+}
+
+func rotationRequest(ctx context.Context, r store.ExtRotation, timezone string) (components.CreateOnCallScheduleRotation, error) {
+	q := store.UseQueries(ctx)
+	req := components.CreateOnCallScheduleRotation{
+		Name:     r.Name,
+		TimeZone: timezone,
+		Strategy: components.CreateOnCallScheduleRotationStrategy{
+			Type: components.CreateOnCallScheduleRotationType(r.Strategy),
+		},
+	}
+	if r.Description != "" {
+		req.Description = &r.Description
+	}
+	if r.StartTime != "" {
+		req.StartTime = &r.StartTime
+	}
+	switch r.Strategy {
+	case "custom":
+		req.Strategy.ShiftDuration = &r.ShiftDuration
+	case "weekly", "daily":
+		req.Strategy.HandoffTime = &r.HandoffTime
+		if r.HandoffDay != "" {
+			day := components.CreateOnCallScheduleRotationHandoffDay(r.HandoffDay)
+			req.Strategy.HandoffDay = &day
+		}
+	}
+
+	members, err := q.ListFhMembersByExtRotationID(ctx, r.ID)
+	if err != nil {
+		return req, fmt.Errorf("querying members for rotation '%s': %w", r.Name, err)
+	}
+	for _, m := range members {
+		req.Members = append(req.Members, components.CreateOnCallScheduleRotationMember{UserID: &m.ID})
+	}
+
+	restrictions, err := q.ListExtRotationRestrictions(ctx, r.ID)
+	if err != nil {
+		return req, fmt.Errorf("querying restrictions for rotation '%s': %w", r.Name, err)
+	}
+	for _, restriction := range restrictions {
+		req.Restrictions = append(req.Restrictions, components.CreateOnCallScheduleRotationRestriction{
+			StartDay:  components.CreateOnCallScheduleRotationStartDay(restriction.StartDay),
+			StartTime: restriction.StartTime,
+			EndDay:    components.CreateOnCallScheduleRotationEndDay(restriction.EndDay),
+			EndTime:   restriction.EndTime,
+		})
+	}
+	return req, nil
+}
+
+// teamScheduleRotation converts a rotation request to the identical type used when the rotation is
+// created along with its schedule.
+func teamScheduleRotation(r components.CreateOnCallScheduleRotation) components.CreateTeamOnCallScheduleRotation {
+	rotation := components.CreateTeamOnCallScheduleRotation{
+		Name:        r.Name,
+		Description: r.Description,
+		TimeZone:    r.TimeZone,
+		StartTime:   r.StartTime,
+		Strategy: components.CreateTeamOnCallScheduleRotationStrategy{
+			Type:          components.CreateTeamOnCallScheduleRotationType(r.Strategy.Type),
+			HandoffTime:   r.Strategy.HandoffTime,
+			ShiftDuration: r.Strategy.ShiftDuration,
+		},
+	}
+	if r.Strategy.HandoffDay != nil {
+		day := components.CreateTeamOnCallScheduleRotationHandoffDay(*r.Strategy.HandoffDay)
+		rotation.Strategy.HandoffDay = &day
+	}
+	for _, m := range r.Members {
+		rotation.Members = append(rotation.Members, components.CreateTeamOnCallScheduleRotationMember{UserID: m.UserID})
+	}
+	for _, restriction := range r.Restrictions {
+		rotation.Restrictions = append(rotation.Restrictions, components.CreateTeamOnCallScheduleRotationRestriction{
+			StartDay:  components.CreateTeamOnCallScheduleRotationStartDay(restriction.StartDay),
+			StartTime: restriction.StartTime,
+			EndDay:    components.CreateTeamOnCallScheduleRotationEndDay(restriction.EndDay),
+			EndTime:   restriction.EndTime,
+		})
+	}
+	return rotation
+}
+
+// policyTarget is an escalation policy step target, which refers either to an existing FireHydrant
+// user or to a schedule step of the plan.
+type policyTarget struct {
+	targetType components.CreateTeamEscalationPolicyType
+	fhID       string
+	stepKey    string
+}
+
+func (a *Applier) planEscalationPolicies(ctx context.Context) ([]*Step, error) {
+	q := store.UseQueries(ctx)
+	policies, err := q.ListExtEscalationPolicies(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("querying escalation policies: %w", err)
+	}
+	defaults, err := store.DefaultEscalationPolicyIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	steps := []*Step{}
+	for _, p := range policies {
+		step := a.addStep(&Step{Resource: ResourceEscalationPolicy, SourceID: p.ID, Name: p.Name})
+		if step.existingID, err = store.ExistingFhID(ctx, store.EXISTING_RESOURCE_ESCALATION_POLICY, p.ID); err != nil {
+			return nil, err
+		}
+		steps = append(steps, step)
+
+		var teamStep *Step
+		if p.TeamID.Valid && p.TeamID.String != "" {
+			teamStep = a.teamSteps[p.TeamID.String]
+		}
+		if teamStep == nil {
+			step.create = func(context.Context) (string, error) {
+				return "", fmt.Errorf("escalation policy is not assigned to an imported team")
+			}
+			continue
+		}
+		step.DependsOn = append(step.DependsOn, teamStep.Key())
+
+		policySteps, err := q.ListExtEscalationPolicySteps(ctx, p.ID)
+		if err != nil {
+			return nil, fmt.Errorf("querying steps for policy '%s': %w", p.Name, err)
+		}
+		stepTimeouts := make([]string, len(policySteps))
+		stepTargets := make([][]policyTarget, len(policySteps))
+		for i, s := range policySteps {
+			stepTimeouts[i] = s.Timeout
+			targets, err := q.ListExtEscalationPolicyStepTargets(ctx, s.ID)
+			if err != nil {
+				return nil, fmt.Errorf("querying targets for step %d of %s: %w", s.Position, p.Name, err)
+			}
+			skip := func(t store.ExtEscalationPolicyStepTarget, reason string) store.InsertExtEscalationPolicyStepTargetSkipParams {
+				return store.InsertExtEscalationPolicyStepTargetSkipParams{
+					EscalationPolicyID: p.ID,
+					StepPosition:       s.Position,
+					TargetType:         t.TargetType,
+					TargetID:           t.TargetID,
+					Reason:             reason,
+				}
+			}
+			for _, t := range targets {
+				switch t.TargetType {
+				case store.TARGET_TYPE_USER:
+					u, err := q.GetUserByExtID(ctx, t.TargetID)
+					if err != nil || !u.FhUserID.Valid {
+						diagnostics.SkipStepTarget(ctx, skip(t, store.TARGET_SKIP_MISSING_FH_USER),
+							"Skipping user '%s' in step %d of %s, it is not imported.\n", t.TargetID, s.Position, p.Name)
+						continue
+					}
+					stepTargets[i] = append(stepTargets[i], policyTarget{
+						targetType: components.CreateTeamEscalationPolicyTypeUser,
+						fhID:       u.FhUserID.String,
+					})
+				case store.TARGET_TYPE_SCHEDULE:
+					key := stepKey(ResourceOnCallSchedule, t.TargetID)
+					if _, ok := a.steps[key]; !ok {
+						diagnostics.SkipStepTarget(ctx, skip(t, store.TARGET_SKIP_NOT_IMPORTED),
+							"Skipping schedule '%s' in step %d of %s, it is not imported.\n", t.TargetID, s.Position, p.Name)
+						continue
+					}
+					step.DependsOn = appendUnique(step.DependsOn, key)
+					stepTargets[i] = append(stepTargets[i], policyTarget{
+						targetType: components.CreateTeamEscalationPolicyTypeOnCallSchedule,
+						stepKey:    key,
+					})
+				default:
+					diagnostics.SkipStepTarget(ctx, skip(t, store.TARGET_SKIP_UNSUPPORTED_TYPE),
+						"Skipping unknown target type '%s' in step %d of %s.\n", t.TargetType, s.Position, p.Name)
+				}
+			}
+		}
+
+		var handoff *components.CreateTeamEscalationPolicyHandoffStep
+		var handoffKey string
+		switch p.HandoffTargetType {
+		case "":
+		case store.TARGET_TYPE_ESCALATION_POLICY:
+			handoff = &components.CreateTeamEscalationPolicyHandoffStep{
+				TargetType: components.CreateTeamEscalationPolicyTargetTypeEscalationPolicy,
+			}
+			handoffKey = stepKey(ResourceEscalationPolicy, p.HandoffTargetID)
+		case store.TARGET_TYPE_TEAM:
+			handoff = &components.CreateTeamEscalationPolicyHandoffStep{
+				TargetType: components.CreateTeamEscalationPolicyTargetTypeTeam,
+			}
+			if ts, ok := a.teamSteps[p.HandoffTargetID]; ok {
+				handoffKey = ts.Key()
+			}
+		default:
+			diagnostics.Warnf(ctx, diagnostics.ResourceEscalationPolicy, p.ID, "Skipping handoff of %s to unknown target type '%s'.\n", p.Name, p.HandoffTargetType)
+		}
+		if handoff != nil {
+			if !a.isPlannedHandoff(handoffKey, policies) {
+				diagnostics.Warnf(ctx, diagnostics.ResourceEscalationPolicy, p.ID, "Skipping handoff of %s to %s '%s', it is not imported.\n", p.Name, p.HandoffTargetType, p.HandoffTargetID)
+				handoff = nil
+			} else {
+				step.DependsOn = appendUnique(step.DependsOn, handoffKey)
+			}
+		}
+
+		// There is nowhere to put the comment on a policy created through the API.
+		if _, err := diagnostics.RepeatInterval(ctx, p); err != nil {
+			return nil, err
+		}
+
+		policy := p
+		defaultPolicy := defaults[p.ID]
+		step.create = func(ctx context.Context) (string, error) {
+			repetitions := int(policy.RepeatLimit)
+			req := components.CreateTeamEscalationPolicy{
+				Name:        policy.Name,
+				Repetitions: &repetitions,
+				Default:     &defaultPolicy,
+			}
+			if policy.Description != "" {
+				req.Description = &policy.Description
+			}
+			for i, targets := range stepTargets {
+				s := components.CreateTeamEscalationPolicyStep{Timeout: stepTimeouts[i]}
+				for _, t := range targets {
+					id := t.fhID
+					if t.stepKey != "" {
+						id = a.ids[t.stepKey]
+					}
+					s.Targets = append(s.Targets, components.CreateTeamEscalationPolicyTarget{Type: t.targetType, ID: id})
+				}
+				req.Steps = append(req.Steps, s)
+			}
+			if handoff != nil {
+				h := *handoff
+				h.TargetID = a.ids[handoffKey]
+				req.HandoffStep = &h
+			}
+			return a.client.CreateEscalationPolicy(ctx, a.ids[teamStep.Key()], req)
+		}
+	}
+	return steps, nil
+}
+
+// isPlannedHandoff reports whether the handoff target is part of the plan. Escalation policies may
+// hand off to policies which are planned after them, so those are looked up in the full list.
+func (a *Applier) isPlannedHandoff(key string, policies []store.ExtEscalationPolicy) bool {
+	if key == "" {
+		return false
+	}
+	if _, ok := a.steps[key]; ok {
+		return true
+	}
+	for _, p := range policies {
+		if stepKey(ResourceEscalationPolicy, p.ID) == key {
+			return true
+		}
+	}
+	return false
+}
+
+func appendUnique(list []string, s string) []string {
+	for _, v := range list {
+		if v == s {
+			return list
+		}
+	}
+	return append(list, s)
+}
@@ -0,0 +1,44 @@
+BEGIN TRANSACTION;
+
+INSERT INTO fh_users VALUES('fh-user-alice','Alice','alice@example.com');
+INSERT INTO fh_users VALUES('fh-user-bob','Bob','bob@example.com');
+
+INSERT INTO ext_users VALUES('PALICE','Alice','alice@example.com','fh-user-alice','');
+INSERT INTO ext_users VALUES('PBOB','Bob','bob@example.com','fh-user-bob','');
+INSERT INTO ext_users VALUES('PCAROL','Carol','carol@example.com',NULL,'');
+
+INSERT INTO fh_teams VALUES('fh-team-platform','Platform','platform');
+
+INSERT INTO ext_teams VALUES('PPAYMENTS','Payments','payments',NULL,0,1,'');
+INSERT INTO ext_teams VALUES('PPLATFORM','Platform','platform','fh-team-platform',0,1,'');
+
+INSERT INTO ext_memberships VALUES('PALICE','PPAYMENTS');
+INSERT INTO ext_memberships VALUES('PBOB','PPAYMENTS');
+INSERT INTO ext_memberships VALUES('PCAROL','PPAYMENTS');
+
+INSERT INTO ext_schedules_v2 VALUES('PSCHED','Payments on-call','Primary rotation','America/Los_Angeles','PPAYMENTS','pagerduty','PSCHED');
+
+INSERT INTO ext_rotations VALUES('PLAYER1','PSCHED','Layer 1','','weekly','','','12:00:00','friday',0);
+INSERT INTO ext_rotations VALUES('PLAYER2','PSCHED','Layer 2','','custom','PT12H','2024-01-01T09:00:00-08:00','','',1);
+
+INSERT INTO ext_rotation_members VALUES('PLAYER1','PALICE',0);
+INSERT INTO ext_rotation_members VALUES('PLAYER1','PBOB',1);
+INSERT INTO ext_rotation_members VALUES('PLAYER2','PBOB',0);
+
+INSERT INTO ext_schedule_overrides VALUES('POVERRIDE1','PSCHED','bob@example.com','3025-03-01T09:00:00-08:00','3025-03-02T09:00:00-08:00');
+INSERT INTO ext_schedule_overrides VALUES('POVERRIDE2','PSCHED','alice@example.com','Mon, 01 Jan 2024 09:00:00 +0000','Sat, 01 Feb 3025 09:00:00 +0000');
+INSERT INTO ext_schedule_overrides VALUES('POVERRIDE3','PSCHED','PCAROL','3025-03-01T09:00:00-08:00','3025-03-02T09:00:00-08:00');
+INSERT INTO ext_schedule_overrides VALUES('POVERRIDE4','PSCHED','bob@example.com','2024-03-01T09:00:00-08:00','2024-03-02T09:00:00-08:00');
+
+INSERT INTO ext_escalation_policies VALUES('PPOLICY1','Payments primary','','PPAYMENTS',2,NULL,'EscalationPolicy','PPOLICY2','',1);
+INSERT INTO ext_escalation_policies VALUES('PPOLICY2','Platform fallback','','PPLATFORM',0,NULL,'','','',1);
+
+INSERT INTO ext_escalation_policy_steps VALUES('PSTEP1','PPOLICY1',0,'PT5M');
+INSERT INTO ext_escalation_policy_steps VALUES('PSTEP2','PPOLICY1',1,'PT15M');
+INSERT INTO ext_escalation_policy_steps VALUES('PSTEP3','PPOLICY2',0,'PT30M');
+
+INSERT INTO ext_escalation_policy_step_targets VALUES('PSTEP1','OnCallSchedule','PSCHED');
+INSERT INTO ext_escalation_policy_step_targets VALUES('PSTEP2','User','PALICE');
+INSERT INTO ext_escalation_policy_step_targets VALUES('PSTEP3','User','PBOB');
+
+COMMIT;
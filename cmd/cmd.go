@@ -1,6 +1,13 @@
 package cmd
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/firehydrant/signals-migrator/console"
+	"github.com/firehydrant/signals-migrator/pager"
 	"github.com/urfave/cli/v2"
 )
 
@@ -19,6 +26,84 @@ var flags = []cli.Flag{
 	},
 }
 
+var providerFlags = []cli.Flag{
+	&cli.StringFlag{
+		Name:     "provider-api-key",
+		Usage:    "Provider API key",
+		EnvVars:  []string{"PROVIDER_API_KEY"},
+		Required: true,
+	},
+	&cli.StringFlag{
+		Name:     "provider-app-id",
+		Usage:    "Provider APP ID",
+		EnvVars:  []string{"PROVIDER_APP_ID"},
+		Required: false,
+	},
+	&cli.StringFlag{
+		Name:    "provider-api-url",
+		Usage:   "Provider API URL, e.g. https://api.eu.pagerduty.com or https://api.eu.opsgenie.com for accounts hosted in the EU. Defaults to the provider's own",
+		EnvVars: []string{"PROVIDER_API_URL"},
+	},
+	&cli.StringFlag{
+		Name:     "provider",
+		Usage:    "The alerting provider to generate from",
+		EnvVars:  []string{"PROVIDER"},
+		Required: true,
+	},
+	&cli.IntFlag{
+		Name:    "concurrency",
+		Usage:   "How many requests to the provider to have in flight at once while loading teams and schedules",
+		EnvVars: []string{"CONCURRENCY"},
+		Value:   pager.DefaultConcurrency,
+	},
+}
+
+// preflight checks that the provider is reachable and accepts the API key, so that a wrong key or
+// region fails before any prompts, rather than halfway through an import.
+func preflight(ctx context.Context, provider pager.Pager) error {
+	p, ok := provider.(pager.Preflighter)
+	if !ok {
+		return nil
+	}
+	if err := p.Preflight(ctx); err != nil {
+		if errors.Is(err, pager.ErrUnauthorized) {
+			return fmt.Errorf("%s rejected the API key, check --provider-api-key and --provider-api-url: %w", provider.Kind(), err)
+		}
+		return fmt.Errorf("connecting to %s, check --provider-api-url: %w", provider.Kind(), err)
+	}
+	console.Successf("Connected to %s.\n", provider.Kind())
+	return nil
+}
+
+// optionalFlags returns copies of the flags which are not required, for commands which only need
+// them in some cases. Those commands check the flags with requireFlags when they do need them.
+func optionalFlags(flags []cli.Flag) []cli.Flag {
+	result := make([]cli.Flag, 0, len(flags))
+	for _, f := range flags {
+		if sf, ok := f.(*cli.StringFlag); ok && sf.Required {
+			optional := *sf
+			optional.Required = false
+			f = &optional
+		}
+		result = append(result, f)
+	}
+	return result
+}
+
+// requireFlags fails when any of the named flags is not set, the same as a required flag would.
+func requireFlags(cliCtx *cli.Context, names ...string) error {
+	var missing []string
+	for _, name := range names {
+		if !cliCtx.IsSet(name) {
+			missing = append(missing, fmt.Sprintf("%q", name))
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("required flags %s not set", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
 func ConcatFlags[T any](slices [][]T) []T {
 	var totalLen int
 
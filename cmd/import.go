@@ -3,16 +3,23 @@ package cmd
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"os/signal"
 	"path/filepath"
+	"slices"
 	"strings"
+	"time"
 
+	"github.com/firehydrant/signals-migrator/answers"
+	"github.com/firehydrant/signals-migrator/apply"
 	"github.com/firehydrant/signals-migrator/console"
 	"github.com/firehydrant/signals-migrator/diagnostics"
 	"github.com/firehydrant/signals-migrator/internal/firehydrant"
 	"github.com/firehydrant/signals-migrator/pager"
+	"github.com/firehydrant/signals-migrator/pulumirender"
 	"github.com/firehydrant/signals-migrator/store"
 	"github.com/firehydrant/signals-migrator/tfrender"
 	"github.com/urfave/cli/v2"
@@ -20,105 +27,340 @@ import (
 
 var importFlags = []cli.Flag{
 	&cli.StringFlag{
-		Name:     "provider-api-key",
-		Usage:    "Provider API key",
-		EnvVars:  []string{"PROVIDER_API_KEY"},
-		Required: true,
+		Name:    "output-dir",
+		Usage:   "The directory to write the Terraform configuration to",
+		EnvVars: []string{"OUTPUT_DIR"},
+		Value:   "./output",
 	},
 	&cli.StringFlag{
-		Name:     "provider-app-id",
-		Usage:    "Provider APP ID",
-		EnvVars:  []string{"PROVIDER_APP_ID"},
-		Required: false,
+		Name:    "output-mode",
+		Usage:   "Either 'terraform' to write Terraform configuration, 'pulumi' to write a Pulumi YAML program, or 'apply' to create the resources directly in FireHydrant",
+		EnvVars: []string{"OUTPUT_MODE"},
+		Value:   "terraform",
 	},
 	&cli.StringFlag{
-		Name:     "provider",
-		Usage:    "The alerting provider to generate from",
-		EnvVars:  []string{"PROVIDER"},
-		Required: true,
+		Name:    "output-layout",
+		Usage:   "How to split the Terraform configuration: 'single' file, one file per team ('files'), or one module per team ('modules')",
+		EnvVars: []string{"OUTPUT_LAYOUT"},
+		Value:   string(tfrender.LayoutSingle),
 	},
 	&cli.StringFlag{
-		Name:    "output-dir",
-		Usage:   "The directory to write the Terraform configuration to",
-		EnvVars: []string{"OUTPUT_DIR"},
-		Value:   "./output",
+		Name:    "output-format",
+		Usage:   "Syntax of the Terraform configuration: 'hcl', or 'json' for .tf.json files",
+		EnvVars: []string{"OUTPUT_FORMAT"},
+		Value:   string(tfrender.FormatHCL),
+	},
+	&cli.DurationFlag{
+		Name:    "override-window",
+		Usage:   "Import the schedule overrides which are active or start within this window from now, or none when 0",
+		EnvVars: []string{"OVERRIDE_WINDOW"},
+		Value:   30 * 24 * time.Hour,
+	},
+	&cli.BoolFlag{
+		Name:    "adjust-start-times",
+		Usage:   "Move the start time of custom rotations which started more than 30 days ago, which FireHydrant rejects, forward by whole shifts",
+		EnvVars: []string{"ADJUST_START_TIMES"},
+	},
+	&cli.BoolFlag{
+		Name:    "allow-empty-schedules",
+		Usage:   "Write the Terraform configuration even when schedules or rotations have no members, which are then only warned about",
+		EnvVars: []string{"ALLOW_EMPTY_SCHEDULES"},
 	},
 	&cli.StringFlag{
 		Name:    "diagnostics",
 		Usage:   "Write diagnostic report to this file path instead of stdout",
 		EnvVars: []string{"DIAGNOSTICS_FILE"},
 	},
+	&cli.StringFlag{
+		Name:    "diagnostics-format",
+		Usage:   "Format of the diagnostic report: 'text', 'json' or 'markdown'",
+		EnvVars: []string{"DIAGNOSTICS_FORMAT"},
+		Value:   string(diagnostics.FormatText),
+	},
+	&cli.StringFlag{
+		Name:    "answers",
+		Usage:   "YAML or JSON file with pre-declared answers to the import prompts",
+		EnvVars: []string{"ANSWERS_FILE"},
+	},
+	&cli.BoolFlag{
+		Name:    "non-interactive",
+		Usage:   "Fail instead of prompting when a question is not covered by the answers file",
+		EnvVars: []string{"NON_INTERACTIVE"},
+	},
+	&cli.StringFlag{
+		Name:    "save-answers",
+		Usage:   "Write the answers given during this import to this file path for later replay",
+		EnvVars: []string{"SAVE_ANSWERS_FILE"},
+	},
+	&cli.StringFlag{
+		Name:    "state-file",
+		Usage:   "Keep import progress in this SQLite file, so that an interrupted import can be resumed by running it again",
+		EnvVars: []string{"STATE_FILE"},
+	},
 }
 
 var ImportCommand = &cli.Command{
 	Name:   "import",
 	Usage:  "Imports Signals resources from a legacy alerting provider",
 	Action: importAction,
-	Flags:  ConcatFlags([][]cli.Flag{importFlags, flags}),
+	Flags:  ConcatFlags([][]cli.Flag{importFlags, userMatchFlags, providerFlags, flags}),
 }
 
 func importAction(cliCtx *cli.Context) error {
 	ctx, cancel := signal.NotifyContext(cliCtx.Context, os.Interrupt)
 	defer cancel()
 
+	diagnosticsFormat, err := diagnostics.ParseFormat(cliCtx.String("diagnostics-format"))
+	if err != nil {
+		return err
+	}
+	ctx = diagnostics.WithCollector(ctx)
+	outputLayout, err := tfrender.ParseLayout(cliCtx.String("output-layout"))
+	if err != nil {
+		return err
+	}
+	outputFormat, err := tfrender.ParseFormat(cliCtx.String("output-format"))
+	if err != nil {
+		return err
+	}
+	mode := cliCtx.String("output-mode")
+	// Without a state file, a re-run would not know what was already created and create it again.
+	if mode == "apply" && cliCtx.String("state-file") == "" {
+		return fmt.Errorf("--output-mode apply requires --state-file, to record what is created in FireHydrant so that re-runs do not create it again")
+	}
+	matcher, err := userMatcher(cliCtx)
+	if err != nil {
+		return err
+	}
+	userMap, err := loadUserMap(cliCtx.String("user-map"))
+	if err != nil {
+		return err
+	}
+
 	providerName := cliCtx.String("provider")
 	provider, err := pager.NewPager(
 		ctx, providerName,
 		cliCtx.String("provider-api-key"),
 		cliCtx.String("provider-app-id"),
+		cliCtx.String("provider-api-url"),
 	)
 	if err != nil {
 		return fmt.Errorf("initializing pager provider: %w", err)
 	}
+	if err := preflight(ctx, provider); err != nil {
+		return err
+	}
+	if p, ok := provider.(pager.OverrideImporter); ok {
+		p.SetOverrideWindow(cliCtx.Duration("override-window"))
+	}
+	if p, ok := provider.(pager.ConcurrentLoader); ok {
+		p.SetConcurrency(cliCtx.Int("concurrency"))
+	}
 	fh, err := firehydrant.NewClient(cliCtx.String("firehydrant-api-key"), cliCtx.String("firehydrant-api-endpoint"))
 	if err != nil {
 		return fmt.Errorf("initializing FireHydrant client: %w", err)
 	}
 
-	ctx = store.WithContext(ctx)
+	if stateFile := cliCtx.String("state-file"); stateFile != "" {
+		ctx = store.WithContextAndDSN(ctx, store.FileDSN(stateFile))
+	} else {
+		ctx = store.WithContext(ctx)
+	}
 	defer store.FromContext(ctx).Close()
 
-	if err := importUsers(ctx, provider, fh); err != nil {
-		return fmt.Errorf("importing users: %w", err)
+	ans, err := loadAnswers(ctx, cliCtx.String("answers"), providerName)
+	if err != nil {
+		return err
+	}
+	ans.NonInteractive = cliCtx.Bool("non-interactive")
+	console.StaticSpinner = ans.NonInteractive
+
+	// Answers are saved even when the import fails or is interrupted halfway, so that a re-run
+	// with the same state file does not ask them again.
+	defer func() {
+		if err := saveAnswers(context.WithoutCancel(ctx), providerName, ans); err != nil {
+			console.Errorf("%s\n", err.Error())
+		}
+	}()
+
+	completed, err := store.UseQueries(ctx).ListCompletedImportPhases(ctx)
+	if err != nil {
+		return fmt.Errorf("reading import progress: %w", err)
+	}
+	phases := []struct {
+		name string
+		run  func(context.Context) error
+	}{
+		{store.IMPORT_PHASE_USERS, func(ctx context.Context) error { return importUsers(ctx, provider, fh, ans, matcher, userMap) }},
+		{store.IMPORT_PHASE_TEAMS, func(ctx context.Context) error { return importTeams(ctx, provider, fh, ans) }},
+		{store.IMPORT_PHASE_SCHEDULES, provider.LoadSchedules},
+		{store.IMPORT_PHASE_ESCALATION_POLICIES, func(ctx context.Context) error {
+			if err := importEscalationPolicies(ctx, provider, ans); err != nil {
+				return err
+			}
+			return selectDefaultEscalationPolicies(ctx, ans)
+		}},
+	}
+	for _, phase := range phases {
+		title := strings.ReplaceAll(phase.name, "_", " ")
+		if slices.Contains(completed, phase.name) {
+			console.Infof("Skipping %s, already imported from %s in a previous run.\n", title, providerName)
+			// The team interface is not saved by providers, so it has to be selected again for the
+			// phases which depend on it.
+			if phase.name == store.IMPORT_PHASE_TEAMS && ans.TeamInterface != "" && len(provider.TeamInterfaces()) > 1 {
+				if err := provider.UseTeamInterface(ans.TeamInterface); err != nil {
+					return fmt.Errorf("setting team interface: %w", err)
+				}
+			}
+			continue
+		}
+		// Anything saved by an interrupted run of this phase is discarded, and the phase is run again.
+		if err := store.ResetImportPhase(ctx, phase.name); err != nil {
+			return err
+		}
+		if err := phase.run(ctx); err != nil {
+			return fmt.Errorf("importing %s: %w", title, err)
+		}
+		if err := store.UseQueries(ctx).CompleteImportPhase(ctx, store.CompleteImportPhaseParams{
+			Name:        phase.name,
+			CompletedAt: time.Now().UTC().Format(time.RFC3339),
+		}); err != nil {
+			return fmt.Errorf("saving import progress: %w", err)
+		}
+		console.Infof("Imported %s from %s.\n", title, providerName)
+	}
+
+	if cliCtx.Bool("adjust-start-times") {
+		if err := pager.AdjustStartTimes(ctx, time.Now()); err != nil {
+			return fmt.Errorf("adjusting start times: %w", err)
+		}
+	}
+
+	if err := linkExistingResources(ctx, fh, ans); err != nil {
+		return err
+	}
+
+	// Answers are written once nothing is left to ask, so that replaying them never asks again.
+	if path := cliCtx.String("save-answers"); path != "" {
+		if err := ans.Save(path); err != nil {
+			return err
+		}
+		console.Successf("Answers written to %s\n", path)
+	}
+
+	switch mode {
+	case "terraform":
+		tfr, err := tfrender.New(filepath.Join(
+			cliCtx.String("output-dir"),
+			fmt.Sprintf("%s_to_fh_signals.tf", strings.ToLower(providerName)),
+		))
+		if err != nil {
+			return fmt.Errorf("initializing Terraform render space: %w", err)
+		}
+		tfr.SetLayout(outputLayout)
+		tfr.SetFormat(outputFormat)
+		tfr.SetValidation(tfrender.Validation{AllowEmptySchedules: cliCtx.Bool("allow-empty-schedules")})
+		if err := tfr.Write(ctx); err != nil {
+			return err
+		}
+	case "pulumi":
+		pr, err := pulumirender.New(
+			filepath.Join(cliCtx.String("output-dir"), "Pulumi.yaml"),
+			fmt.Sprintf("%s-to-fh-signals", strings.ToLower(providerName)),
+		)
+		if err != nil {
+			return fmt.Errorf("initializing Pulumi render space: %w", err)
+		}
+		if err := pr.Write(ctx); err != nil {
+			return err
+		}
+	case "apply":
+		if err := applyToFireHydrant(ctx, fh); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unknown output mode '%s'", mode)
 	}
-	console.Infof("Imported users from %s.\n", providerName)
+	return printDiagnostics(ctx, cliCtx.String("diagnostics"), diagnosticsFormat)
+}
 
-	if err := importTeams(ctx, provider, fh); err != nil {
-		return fmt.Errorf("importing teams: %w", err)
+// loadAnswers returns the answers given by --answers. A re-run with the same state file continues
+// with the answers saved by the previous run, over which those given by --answers are merged.
+func loadAnswers(ctx context.Context, path string, providerName string) (*answers.Answers, error) {
+	session, err := store.UseQueries(ctx).GetImportSession(ctx)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("reading import session: %w", err)
+	}
+	resuming := err == nil
+	if resuming && !strings.EqualFold(session.Provider, providerName) {
+		return nil, fmt.Errorf("state file belongs to an import from %s, not %s", session.Provider, providerName)
 	}
-	console.Infof("Imported teams from %s.\n", providerName)
 
-	if err := provider.LoadSchedules(ctx); err != nil {
-		return fmt.Errorf("importing schedules: %w", err)
+	ans := &answers.Answers{}
+	if resuming {
+		if err := json.Unmarshal([]byte(session.Answers), ans); err != nil {
+			return nil, fmt.Errorf("reading answers from import session: %w", err)
+		}
+		console.Infof("Resuming import from %s with the answers given previously.\n", session.Provider)
 	}
-	console.Infof("Imported schedules from %s.\n", providerName)
+	if path != "" {
+		fromFile, err := answers.Load(path)
+		if err != nil {
+			return nil, err
+		}
+		ans.Merge(fromFile)
+	}
+	return ans, nil
+}
 
-	if err := importEscalationPolicies(ctx, provider); err != nil {
-		return fmt.Errorf("importing escalation policies: %w", err)
+func saveAnswers(ctx context.Context, providerName string, ans *answers.Answers) error {
+	b, err := json.Marshal(ans)
+	if err != nil {
+		return fmt.Errorf("encoding answers: %w", err)
 	}
-	console.Infof("Imported escalation policies from %s.\n", providerName)
+	if err := store.UseQueries(ctx).SaveImportSession(ctx, store.SaveImportSessionParams{
+		Provider: providerName,
+		Answers:  string(b),
+	}); err != nil {
+		return fmt.Errorf("saving answers to import session: %w", err)
+	}
+	return nil
+}
 
-	tfr, err := tfrender.New(filepath.Join(
-		cliCtx.String("output-dir"),
-		fmt.Sprintf("%s_to_fh_signals.tf", strings.ToLower(providerName)),
-	))
+func applyToFireHydrant(ctx context.Context, fh *firehydrant.Client) error {
+	results, err := apply.New(fh).Apply(ctx)
 	if err != nil {
-		return fmt.Errorf("initializing Terraform render space: %w", err)
+		return fmt.Errorf("applying to FireHydrant: %w", err)
 	}
-	if err := tfr.Write(ctx); err != nil {
-		return err
+	counts := map[string]int{}
+	for _, res := range results {
+		counts[res.Status]++
 	}
-	return printDiagnostics(ctx, cliCtx.String("diagnostics"))
+	console.Infof("Applied to FireHydrant: %d created, %d existing, %d skipped, %d failed.\n",
+		counts[apply.StatusCreated], counts[apply.StatusExisting], counts[apply.StatusSkipped], counts[apply.StatusFailed])
+	if n := counts[apply.StatusFailed] + counts[apply.StatusSkipped]; n > 0 {
+		return fmt.Errorf("%d resources could not be applied to FireHydrant, fix them and run again with the same --state-file to retry", n)
+	}
+	return nil
 }
 
-func printDiagnostics(ctx context.Context, outputPath string) error {
+func printDiagnostics(ctx context.Context, outputPath string, format diagnostics.Format) error {
 	skips, err := store.UseQueries(ctx).ListRotationMemberSkips(ctx)
 	if err != nil {
 		return fmt.Errorf("querying diagnostics: %w", err)
 	}
+	targetSkips, err := store.UseQueries(ctx).ListEscalationPolicyStepTargetSkips(ctx)
+	if err != nil {
+		return fmt.Errorf("querying diagnostics: %w", err)
+	}
+	report := diagnostics.NewReport(diagnostics.FromContext(ctx).Entries(), skips, targetSkips)
 
 	if outputPath == "" {
-		return diagnostics.Write(console.WarnWriter(), skips)
+		// Only the text report is styled, so that the other formats can be piped as is.
+		if format == diagnostics.FormatText {
+			return report.Write(console.WarnWriter(), format)
+		}
+		return report.Write(os.Stdout, format)
 	}
 
 	f, err := os.Create(outputPath)
@@ -127,7 +369,7 @@ func printDiagnostics(ctx context.Context, outputPath string) error {
 	}
 	defer f.Close()
 
-	if err := diagnostics.Write(f, skips); err != nil {
+	if err := report.Write(f, format); err != nil {
 		return err
 	}
 	console.Warnf("Diagnostic report written to %s\n", outputPath)
@@ -139,7 +381,7 @@ func printDiagnostics(ctx context.Context, outputPath string) error {
 // which rows to import. We mark the selected rows from users in `to_import` field and delete
 // the ones that we will not import to FireHydrant. This is done to simplify the state management
 // between queries and filtering.
-func importEscalationPolicies(ctx context.Context, provider pager.Pager) error {
+func importEscalationPolicies(ctx context.Context, provider pager.Pager, ans *answers.Answers) error {
 	if err := provider.LoadEscalationPolicies(ctx); err != nil {
 		return fmt.Errorf("unable to load escalation policies: %w", err)
 	}
@@ -147,6 +389,35 @@ func importEscalationPolicies(ctx context.Context, provider pager.Pager) error {
 	if err != nil {
 		return fmt.Errorf("unable to list escalation policies: %w", err)
 	}
+
+	if ans.EscalationPolicies.Answered() {
+		selected := 0
+		for _, ep := range allEps {
+			if !ans.EscalationPolicies.Selects(ep.ID, ep.Name) {
+				continue
+			}
+			if err := store.UseQueries(ctx).MarkExtEscalationPolicyToImport(ctx, ep.ID); err != nil {
+				return fmt.Errorf("unable to mark escalation policy '%s' for import: %w", ep.Name, err)
+			}
+			selected++
+		}
+		console.Successf("[+] %d escalation policies selected for migration from answers file.\n", selected)
+	} else {
+		if err := ans.Ask("which escalation policies should be migrated"); err != nil {
+			return err
+		}
+		if err := selectEscalationPolicies(ctx, allEps, ans); err != nil {
+			return err
+		}
+	}
+
+	if err := store.UseQueries(ctx).DeleteExtEscalationPolicyUnimported(ctx); err != nil {
+		return fmt.Errorf("unable to delete unimported escalation policies: %w", err)
+	}
+	return nil
+}
+
+func selectEscalationPolicies(ctx context.Context, allEps []store.ExtEscalationPolicy, ans *answers.Answers) error {
 	options := []store.ExtEscalationPolicy{{ID: "[+] ADD ALL"}, {ID: "[<] SKIP ALL"}}
 	options = append(options, allEps...)
 	console.Warnf("Please select (out of %d) which escalation policies to migrate.\n", len(allEps))
@@ -164,11 +435,13 @@ func importEscalationPolicies(ctx context.Context, provider pager.Pager) error {
 	switch selected[0] {
 	case 0:
 		console.Successf("[+] All escalation policies will be migrated to FireHydrant.\n")
+		ans.EscalationPolicies = answers.Selection{All: true}
 		if err := store.UseQueries(ctx).MarkAllExtEscalationPolicyToImport(ctx); err != nil {
 			return fmt.Errorf("unable to mark all escalation policies for import: %w", err)
 		}
 	case 1:
 		console.Warnf("[<] No escalation policies will be migrated to FireHydrant.\n")
+		ans.EscalationPolicies = answers.Selection{None: true}
 	default:
 		for _, ep := range toImport {
 			if ep.ID == "[+] ADD ALL" || ep.ID == "[<] SKIP ALL" {
@@ -177,16 +450,277 @@ func importEscalationPolicies(ctx context.Context, provider pager.Pager) error {
 			if err := store.UseQueries(ctx).MarkExtEscalationPolicyToImport(ctx, ep.ID); err != nil {
 				return fmt.Errorf("unable to mark escalation policy '%s' for import: %w", ep.Name, err)
 			}
+			ans.EscalationPolicies.Include = append(ans.EscalationPolicies.Include, ep.ID)
 		}
 	}
+	return nil
+}
 
-	if err := store.UseQueries(ctx).DeleteExtEscalationPolicyUnimported(ctx); err != nil {
-		return fmt.Errorf("unable to delete unimported escalation policies: %w", err)
+// selectDefaultEscalationPolicies chooses the default escalation policy of every team with
+// escalation policies to migrate, which is paged when the team itself is. Teams with a single
+// policy default to it, and the others are looked up in the answers or prompted for.
+func selectDefaultEscalationPolicies(ctx context.Context, ans *answers.Answers) error {
+	q := store.UseQueries(ctx)
+	eps, err := q.ListExtEscalationPolicies(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to list escalation policies: %w", err)
+	}
+	byTeam := map[string][]store.ExtEscalationPolicy{}
+	teamIDs := []string{}
+	for _, ep := range eps {
+		if !ep.TeamID.Valid || ep.TeamID.String == "" {
+			continue
+		}
+		if _, ok := byTeam[ep.TeamID.String]; !ok {
+			teamIDs = append(teamIDs, ep.TeamID.String)
+		}
+		byTeam[ep.TeamID.String] = append(byTeam[ep.TeamID.String], ep)
+	}
+
+	for _, teamID := range teamIDs {
+		policies := byTeam[teamID]
+		chosen := policies[0]
+		if len(policies) > 1 {
+			team, err := q.GetExtTeam(ctx, teamID)
+			if err != nil {
+				return fmt.Errorf("unable to get team '%s': %w", teamID, err)
+			}
+			if answer, ok := ans.DefaultEscalationPolicy(team.ID, team.Name); ok {
+				i := slices.IndexFunc(policies, func(ep store.ExtEscalationPolicy) bool {
+					return strings.EqualFold(ep.ID, answer) || strings.EqualFold(ep.Name, answer)
+				})
+				if i < 0 {
+					return fmt.Errorf("default escalation policy '%s' of team '%s' is not one of its escalation policies to migrate", answer, team.Name)
+				}
+				chosen = policies[i]
+			} else {
+				if err := ans.Ask(fmt.Sprintf("which escalation policy is the default of team '%s'", team.Name)); err != nil {
+					return err
+				}
+				_, chosen, err = console.Selectf(policies, func(ep store.ExtEscalationPolicy) string {
+					return fmt.Sprintf("%s %s", ep.ID, ep.Name)
+				}, "Which escalation policy should be the default of team '%s'?", team.Name)
+				if err != nil {
+					return fmt.Errorf("selecting default escalation policy of team '%s': %w", team.Name, err)
+				}
+				ans.SetDefaultEscalationPolicy(team.ID, chosen.ID)
+			}
+			console.Successf("[+] '%s' will be the default escalation policy of team '%s'.\n", chosen.Name, team.Name)
+		}
+		if err := q.InsertExtDefaultEscalationPolicy(ctx, store.InsertExtDefaultEscalationPolicyParams{
+			TeamID:             teamID,
+			EscalationPolicyID: chosen.ID,
+		}); err != nil {
+			return fmt.Errorf("unable to save default escalation policy '%s': %w", chosen.Name, err)
+		}
 	}
 	return nil
 }
 
-func importTeams(ctx context.Context, provider pager.Pager, fh *firehydrant.Client) error {
+// existingResource is an imported schedule or escalation policy which has the same name as a
+// resource of its FireHydrant team.
+type existingResource struct {
+	resourceType string
+	sourceID     string
+	name         string
+	fhTeamName   string
+	fhID         string
+	importID     string
+	// rotations are the rotations of a schedule which match a rotation of the existing schedule.
+	rotations []existingResource
+}
+
+// linkExistingResources matches the schedules and escalation policies of teams linked to an
+// existing FireHydrant team against the resources of that team by name. Matches which the user
+// confirms are imported in the generated configuration, or reused by apply, rather than created
+// again.
+func linkExistingResources(ctx context.Context, fh *firehydrant.Client, ans *answers.Answers) error {
+	q := store.UseQueries(ctx)
+	if err := q.DeleteFhExistingResources(ctx); err != nil {
+		return fmt.Errorf("resetting existing resources: %w", err)
+	}
+
+	teams, err := q.ListTeamsToImport(ctx)
+	if err != nil {
+		return fmt.Errorf("querying teams: %w", err)
+	}
+	fhTeams := map[string]store.LinkedTeam{}
+	for _, t := range teams {
+		if t.FhTeamID.Valid && t.FhTeamID.String != "" {
+			fhTeams[t.ID] = t
+		}
+	}
+	if len(fhTeams) == 0 {
+		return nil
+	}
+
+	fhSchedules := map[string][]firehydrant.OnCallSchedule{}
+	fhPolicies := map[string][]firehydrant.Resource{}
+	console.Spin(func() {
+		for _, t := range fhTeams {
+			id := t.FhTeamID.String
+			if _, ok := fhSchedules[id]; ok {
+				continue
+			}
+			if fhSchedules[id], err = fh.ListOnCallSchedules(ctx, id); err != nil {
+				return
+			}
+			if fhPolicies[id], err = fh.ListEscalationPolicies(ctx, id); err != nil {
+				return
+			}
+		}
+	}, "Fetching existing schedules and escalation policies from FireHydrant...")
+	if err != nil {
+		return fmt.Errorf("unable to fetch existing resources from FireHydrant: %w", err)
+	}
+
+	// Resources created by a previous run of the apply mode with the same state file are not
+	// existing ones: they are neither matched, nor matched to.
+	appliedResources, err := q.ListAppliedResources(ctx)
+	if err != nil {
+		return fmt.Errorf("querying applied resources: %w", err)
+	}
+	applied := map[string]bool{}
+	// Each existing resource is matched at most once, in case imported resources share a name.
+	claimed := map[string]bool{}
+	for _, r := range appliedResources {
+		applied[r.ResourceType+"/"+r.SourceID] = true
+		claimed[r.FhID] = true
+	}
+	match := func(resources []firehydrant.Resource, name string) (firehydrant.Resource, bool) {
+		for _, r := range resources {
+			if !claimed[r.ID] && strings.EqualFold(strings.TrimSpace(r.Name), strings.TrimSpace(name)) {
+				claimed[r.ID] = true
+				return r, true
+			}
+		}
+		return firehydrant.Resource{}, false
+	}
+
+	matches := []existingResource{}
+	schedules, err := q.ListExtSchedulesV2(ctx)
+	if err != nil {
+		return fmt.Errorf("querying schedules: %w", err)
+	}
+	for _, s := range schedules {
+		t, ok := fhTeams[s.TeamID]
+		if !ok || applied[store.EXISTING_RESOURCE_SCHEDULE+"/"+s.ID] {
+			continue
+		}
+		teamID := t.FhTeamID.String
+		candidates := make([]firehydrant.Resource, 0, len(fhSchedules[teamID]))
+		for _, fhSchedule := range fhSchedules[teamID] {
+			candidates = append(candidates, fhSchedule.Resource)
+		}
+		found, ok := match(candidates, s.Name)
+		if !ok {
+			continue
+		}
+		// The import IDs are the ones expected by the FireHydrant Terraform provider.
+		m := existingResource{
+			resourceType: store.EXISTING_RESOURCE_SCHEDULE,
+			sourceID:     s.ID,
+			name:         s.Name,
+			fhTeamName:   t.FhName.String,
+			fhID:         found.ID,
+			importID:     fmt.Sprintf("%s:%s", teamID, found.ID),
+		}
+
+		rotations, err := q.ListExtRotationsByScheduleID(ctx, s.ID)
+		if err != nil {
+			return fmt.Errorf("querying rotations: %w", err)
+		}
+		fhRotations := []firehydrant.Resource{}
+		for _, fhSchedule := range fhSchedules[teamID] {
+			if fhSchedule.ID == found.ID {
+				fhRotations = fhSchedule.Rotations
+			}
+		}
+		for _, r := range rotations {
+			if applied[store.EXISTING_RESOURCE_ROTATION+"/"+r.ID] {
+				continue
+			}
+			if fhRotation, ok := match(fhRotations, r.Name); ok {
+				m.rotations = append(m.rotations, existingResource{
+					resourceType: store.EXISTING_RESOURCE_ROTATION,
+					sourceID:     r.ID,
+					name:         r.Name,
+					fhID:         fhRotation.ID,
+					importID:     fmt.Sprintf("%s:%s:%s", teamID, found.ID, fhRotation.ID),
+				})
+			}
+		}
+		matches = append(matches, m)
+	}
+
+	policies, err := q.ListExtEscalationPolicies(ctx)
+	if err != nil {
+		return fmt.Errorf("querying escalation policies: %w", err)
+	}
+	for _, p := range policies {
+		t, ok := fhTeams[p.TeamID.String]
+		if !p.TeamID.Valid || !ok || applied[store.EXISTING_RESOURCE_ESCALATION_POLICY+"/"+p.ID] {
+			continue
+		}
+		teamID := t.FhTeamID.String
+		found, ok := match(fhPolicies[teamID], p.Name)
+		if !ok {
+			continue
+		}
+		matches = append(matches, existingResource{
+			resourceType: store.EXISTING_RESOURCE_ESCALATION_POLICY,
+			sourceID:     p.ID,
+			name:         p.Name,
+			fhTeamName:   t.FhName.String,
+			fhID:         found.ID,
+			importID:     fmt.Sprintf("%s:%s", teamID, found.ID),
+		})
+	}
+	if len(matches) == 0 {
+		return nil
+	}
+
+	var selected []existingResource
+	if ans.ExistingResources.Answered() {
+		for _, m := range matches {
+			if ans.ExistingResources.Selects(m.sourceID, m.name) {
+				selected = append(selected, m)
+			}
+		}
+	} else {
+		if err := ans.Ask("which existing FireHydrant resources should be imported"); err != nil {
+			return err
+		}
+		console.Warnf("Found %d schedules and escalation policies which already exist in FireHydrant.\n", len(matches))
+		_, selected, err = console.MultiSelectf(matches, func(m existingResource) string {
+			return fmt.Sprintf("%s '%s' of team '%s' (%s)", strings.ReplaceAll(m.resourceType, "_", " "), m.name, m.fhTeamName, m.fhID)
+		}, "Which of them should be imported, rather than created again?")
+		if err != nil {
+			return fmt.Errorf("selecting existing resources: %w", err)
+		}
+		ans.ExistingResources = answers.Selection{None: len(selected) == 0}
+		for _, m := range selected {
+			ans.ExistingResources.Include = append(ans.ExistingResources.Include, m.sourceID)
+		}
+	}
+
+	for _, m := range selected {
+		for _, r := range append([]existingResource{m}, m.rotations...) {
+			if err := q.InsertFhExistingResource(ctx, store.InsertFhExistingResourceParams{
+				ResourceType: r.resourceType,
+				SourceID:     r.sourceID,
+				FhID:         r.fhID,
+				ImportID:     r.importID,
+			}); err != nil {
+				return fmt.Errorf("recording existing %s '%s': %w", r.resourceType, r.name, err)
+			}
+		}
+	}
+	console.Successf("[+] %d existing schedules and escalation policies will be imported.\n", len(selected))
+	return nil
+}
+
+func importTeams(ctx context.Context, provider pager.Pager, fh *firehydrant.Client, ans *answers.Answers) error {
 	// Some providers made their users adopt an alternate concept of teams.
 	//
 	// For example, PagerDuty has "Teams" and "Services". In vacuum, they intuitively refer to
@@ -199,11 +733,19 @@ func importTeams(ctx context.Context, provider pager.Pager, fh *firehydrant.Clie
 	// There may be a case where users may want to import both to FireHydrant. It is not currently
 	// supported, but can be a reasonable future enhancement.
 	if choices := provider.TeamInterfaces(); len(choices) > 1 {
-		_, ti, err := console.Selectf(choices, func(s string) string {
-			return fmt.Sprintf("%s %s", provider.Kind(), s)
-		}, "Let's fill out your teams in FireHydrant. Which team interface would you like to use?")
-		if err != nil {
-			return fmt.Errorf("selecting team interface: %w", err)
+		ti := ans.TeamInterface
+		if ti == "" {
+			if err := ans.Ask("which team interface to use"); err != nil {
+				return err
+			}
+			var err error
+			_, ti, err = console.Selectf(choices, func(s string) string {
+				return fmt.Sprintf("%s %s", provider.Kind(), s)
+			}, "Let's fill out your teams in FireHydrant. Which team interface would you like to use?")
+			if err != nil {
+				return fmt.Errorf("selecting team interface: %w", err)
+			}
+			ans.TeamInterface = ti
 		}
 		if err := provider.UseTeamInterface(ti); err != nil {
 			return fmt.Errorf("setting team interface: %w", err)
@@ -232,7 +774,17 @@ func importTeams(ctx context.Context, provider pager.Pager, fh *firehydrant.Clie
 
 	if err := provider.LoadTeamMembers(ctx); err != nil {
 		console.Errorf("unable to load team members: %s", err.Error())
-		if y, yErr := console.YesNo("Continue without team members?"); yErr != nil || !y {
+		if ans.ContinueWithoutTeamMembers == nil {
+			if askErr := ans.Ask("continue without team members"); askErr != nil {
+				return fmt.Errorf("unable to populate team members: %w", err)
+			}
+			y, yErr := console.YesNo("Continue without team members?")
+			if yErr != nil {
+				return fmt.Errorf("unable to populate team members: %w", err)
+			}
+			ans.ContinueWithoutTeamMembers = &y
+		}
+		if !*ans.ContinueWithoutTeamMembers {
 			return fmt.Errorf("unable to populate team members: %w", err)
 		}
 	}
@@ -243,12 +795,29 @@ func importTeams(ctx context.Context, provider pager.Pager, fh *firehydrant.Clie
 	if err != nil {
 		return fmt.Errorf("unable to list teams: %w", err)
 	}
-	console.Warnf("Please select which teams to migrate to FireHydrant.\n")
-	_, toImport, err := console.MultiSelectf(teams, func(t store.ExtTeam) string {
-		return fmt.Sprintf("%s %s", t.ID, t.Name)
-	}, "Which teams should be migrated to FireHydrant?")
-	if err != nil {
-		return fmt.Errorf("selecting teams: %w", err)
+	var toImport []store.ExtTeam
+	if ans.Teams.Answered() {
+		for _, t := range teams {
+			if ans.Teams.Selects(t.ID, t.Name) {
+				toImport = append(toImport, t)
+			}
+		}
+		console.Successf("Selected %d teams to migrate from answers file.\n", len(toImport))
+	} else {
+		if err := ans.Ask("which teams should be migrated"); err != nil {
+			return err
+		}
+		console.Warnf("Please select which teams to migrate to FireHydrant.\n")
+		_, toImport, err = console.MultiSelectf(teams, func(t store.ExtTeam) string {
+			return fmt.Sprintf("%s %s", t.ID, t.Name)
+		}, "Which teams should be migrated to FireHydrant?")
+		if err != nil {
+			return fmt.Errorf("selecting teams: %w", err)
+		}
+		ans.Teams = answers.Selection{None: len(toImport) == 0}
+		for _, t := range toImport {
+			ans.Teams.Include = append(ans.Teams.Include, t.ID)
+		}
 	}
 	for _, t := range toImport {
 		if err := store.UseQueries(ctx).MarkExtTeamToImport(ctx, t.ID); err != nil {
@@ -260,13 +829,35 @@ func importTeams(ctx context.Context, provider pager.Pager, fh *firehydrant.Clie
 	options := []store.FhTeam{{ID: "[+] CREATE NEW"}}
 	options = append(options, fhTeams...)
 	for _, t := range toImport {
-		selected, fhTeam, err := console.Selectf(options, func(t store.FhTeam) string {
-			return fmt.Sprintf("%s %s", t.ID, t.Name)
-		}, "%s", fmt.Sprintf("Which FireHydrant team should '%s' be imported to?", t.Name)) //nolint:govet
-		if err != nil {
-			return fmt.Errorf("selecting FireHydrant team for '%s': %w", t.Name, err)
+		var fhTeam store.FhTeam
+		if link, ok := ans.TeamLink(t.ID, t.Name); ok {
+			if !strings.EqualFold(link, answers.NewTeam) {
+				i := slices.IndexFunc(fhTeams, func(fhTeam store.FhTeam) bool {
+					return link == fhTeam.ID || strings.EqualFold(link, fhTeam.Slug) || strings.EqualFold(link, fhTeam.Name)
+				})
+				if i < 0 {
+					return fmt.Errorf("FireHydrant team '%s' for '%s' not found", link, t.Name)
+				}
+				fhTeam = fhTeams[i]
+			}
+		} else {
+			if err := ans.Ask(fmt.Sprintf("which FireHydrant team should '%s' be imported to", t.Name)); err != nil {
+				return err
+			}
+			selected, selectedTeam, err := console.Selectf(options, func(t store.FhTeam) string {
+				return fmt.Sprintf("%s %s", t.ID, t.Name)
+			}, "%s", fmt.Sprintf("Which FireHydrant team should '%s' be imported to?", t.Name)) //nolint:govet
+			if err != nil {
+				return fmt.Errorf("selecting FireHydrant team for '%s': %w", t.Name, err)
+			}
+			if selected == 0 {
+				ans.LinkTeam(t.ID, answers.NewTeam)
+			} else {
+				ans.LinkTeam(t.ID, selectedTeam.ID)
+				fhTeam = selectedTeam
+			}
 		}
-		if selected == 0 {
+		if fhTeam.ID == "" {
 			console.Infof("[+] Team '%s' will be created as new team in FireHydrant.\n", t.Name)
 			continue
 		}
@@ -284,23 +875,10 @@ func importTeams(ctx context.Context, provider pager.Pager, fh *firehydrant.Clie
 	return nil
 }
 
-func importUsers(ctx context.Context, provider pager.Pager, fh *firehydrant.Client) error {
-	// Get all of the users registered from Pager Provider (e.g. PagerDuty)
-	var err error
-	console.Spin(func() {
-		err = provider.LoadUsers(ctx)
-	}, "Fetching all users from provider...")
-	if err != nil {
-		return fmt.Errorf("unable to fetch users from provider: %w", err)
-	}
-	console.Successf("Loaded all users from %s.\n", provider.Kind())
-
+func importUsers(ctx context.Context, provider pager.Pager, fh *firehydrant.Client, ans *answers.Answers, matcher firehydrant.UserMatcher, userMap []firehydrant.UserMapping) error {
 	// Find out which users do not already have a FireHydrant account
-	console.Spin(func() {
-		err = fh.MatchUsers(ctx)
-	}, "Matching users with existing FireHydrant users by email...")
-	if err != nil {
-		return fmt.Errorf("unable to match users to FireHydrant: %w", err)
+	if err := matchUsers(ctx, provider, fh, matcher, userMap); err != nil {
+		return err
 	}
 
 	// Find out which users should be pre-created in FireHydrant via SCIM / matched to existing user.
@@ -313,6 +891,18 @@ func importUsers(ctx context.Context, provider pager.Pager, fh *firehydrant.Clie
 		return nil
 	}
 
+	// Users with a decision in the answers file are handled first, the rest are prompted for below.
+	unmatched, err = importAnsweredUsers(ctx, fh, unmatched, ans)
+	if err != nil {
+		return err
+	}
+	if len(unmatched) == 0 {
+		return nil
+	}
+	if err := ans.Ask(fmt.Sprintf("how %d unmatched users should be imported", len(unmatched))); err != nil {
+		return err
+	}
+
 	// Get padding number to pretty print the information in table-like view.
 	idPad := console.PadStrings(unmatched, func(u store.ExtUser) int { return len(u.ID) })
 	emailPad := console.PadStrings(unmatched, func(u store.ExtUser) int { return len(u.Email) })
@@ -332,28 +922,26 @@ func importUsers(ctx context.Context, provider pager.Pager, fh *firehydrant.Clie
 	case 0:
 		console.Successf("[+] All users will be created in FireHydrant.\n")
 		for _, u := range unmatched {
-			fhUser, err := fh.CreateUser(ctx, &u)
-			if err != nil {
-				console.Warnf("unable to create user '%s': %s\n", u.Email, err.Error())
-				continue
-			}
-			if err := store.UseQueries(ctx).LinkExtUser(ctx, store.LinkExtUserParams{
-				ID:       u.ID,
-				FhUserID: sql.NullString{String: fhUser.ID, Valid: true},
-			}); err != nil {
-				console.Warnf("unable to link user '%s': %s\n", u.Email, err.Error())
-				continue
-			}
+			ans.Users.Create = append(ans.Users.Create, u.ID)
+			createUser(ctx, fh, u)
 		}
 		return nil
 	case 1:
 		console.Warnf("[<] No users will be created in FireHydrant.\n")
+		for _, u := range unmatched {
+			ans.Users.Skip = append(ans.Users.Skip, u.ID)
+		}
 		if err := store.UseQueries(ctx).DeleteUnmatchedExtUsers(ctx); err != nil {
 			return fmt.Errorf("unable to delete unmatched users: %w", err)
 		}
 		return nil
 	default:
 		console.Warnf("Selected %d users to be imported to FireHydrant.\n", len(toImport))
+		for _, u := range unmatched {
+			if !slices.ContainsFunc(toImport, func(t store.ExtUser) bool { return t.ID == u.ID }) {
+				ans.Users.Skip = append(ans.Users.Skip, u.ID)
+			}
+		}
 	}
 
 	// We now ask if all of them are to be created as new users, or if they should be matched to existing FireHydrant users.
@@ -364,55 +952,132 @@ func importUsers(ctx context.Context, provider pager.Pager, fh *firehydrant.Clie
 	}
 
 	namePad := console.PadStrings(fhUsers, func(u store.FhUser) int { return len(u.Name) })
+	fhEmailPad := console.PadStrings(fhUsers, func(u store.FhUser) int { return len(u.Email) })
 
-	matchOpts := []store.FhUser{
-		{Name: "[+] CREATE THE REST OF USERS AS NEW"},
-		{Name: "[+] CREATE USER AS NEW             "}, // Extra spaces for padding alignment of icons
-	}
-	matchOpts = append(matchOpts, fhUsers...)
 	for i, u := range toImport {
+		// Suggested users are listed first, most likely first, followed by the options to create the
+		// user and the rest of the FireHydrant users.
+		suggestions := matcher.Suggest(u, fhUsers)
+		labels := map[string]string{}
+		matchOpts := []store.FhUser{}
+		for _, s := range suggestions {
+			matchOpts = append(matchOpts, s.User)
+			labels[s.User.ID] = fmt.Sprintf("%.0f%% match: %s", s.Confidence*100, strings.Join(s.Reasons, ", "))
+		}
+		createRest, createOne := len(matchOpts), len(matchOpts)+1
+		matchOpts = append(matchOpts,
+			store.FhUser{Name: "[+] CREATE THE REST OF USERS AS NEW"},
+			store.FhUser{Name: "[+] CREATE USER AS NEW             "}, // Extra spaces for padding alignment of icons
+		)
+		for _, fhUser := range fhUsers {
+			if _, ok := labels[fhUser.ID]; !ok {
+				matchOpts = append(matchOpts, fhUser)
+			}
+		}
+
 		selected, fhUser, err := console.Selectf(matchOpts, func(u store.FhUser) string {
+			if label, ok := labels[u.ID]; ok && u.ID != "" {
+				return fmt.Sprintf("%*s  %-*s  (%s)", namePad, u.Name, fhEmailPad, u.Email, label)
+			}
 			return fmt.Sprintf("%*s  %s", namePad, u.Name, u.Email)
 		}, "%s", fmt.Sprintf("[%03d/%03d] Which FireHydrant user should '%s' be imported to?", i+1, len(toImport), u.Name)) //nolint:govet
 		if err != nil {
 			return fmt.Errorf("selecting FireHydrant user for '%s': %w", u.Name, err)
 		}
+		method := store.USER_MATCH_MANUAL
 		switch selected {
-		case 0:
+		case createRest:
 			console.Infof("[+] All users will be created in FireHydrant.\n")
 			for _, u := range toImport[i:] {
-				fhUser, err := fh.CreateUser(ctx, &u)
-				if err != nil {
-					console.Warnf("unable to create user '%s': %s\n", u.Email, err.Error())
-					continue
-				}
-				if err := store.UseQueries(ctx).LinkExtUser(ctx, store.LinkExtUserParams{
-					ID:       u.ID,
-					FhUserID: sql.NullString{String: fhUser.ID, Valid: true},
-				}); err != nil {
-					console.Warnf("unable to link user '%s': %s\n", u.Email, err.Error())
-					continue
-				}
+				ans.Users.Create = append(ans.Users.Create, u.ID)
+				createUser(ctx, fh, u)
 			}
 			return nil
-		case 1:
+		case createOne:
 			console.Infof("[+] User '%s (%s)' will be created in FireHydrant.\n", u.Name, u.Email)
 			scimUser, err := fh.CreateUser(ctx, &u)
 			if err != nil {
 				return fmt.Errorf("creating user '%s': %w", u.Name, err)
 			}
+			ans.Users.Create = append(ans.Users.Create, u.ID)
 			fhUser = *scimUser
-			fallthrough
+			method = store.USER_MATCH_CREATED
 		default:
-			if err := store.UseQueries(ctx).LinkExtUser(ctx, store.LinkExtUserParams{
-				ID:       u.ID,
-				FhUserID: sql.NullString{String: fhUser.ID, Valid: true},
-			}); err != nil {
-				console.Warnf("unable to link user '%s': %s\n", u.Email, err.Error())
-				continue
+			ans.LinkUser(u.ID, fhUser.ID)
+		}
+		if err := linkUser(ctx, fh, u, fhUser, method); err != nil {
+			console.Warnf("%s\n", err.Error())
+		}
+	}
+	return nil
+}
+
+// importAnsweredUsers creates, links or skips the unmatched users which have a decision in the
+// answers file, and returns the users which are still left to be decided.
+func importAnsweredUsers(ctx context.Context, fh *firehydrant.Client, unmatched []store.ExtUser, ans *answers.Answers) ([]store.ExtUser, error) {
+	var fhUsers []store.FhUser
+	remaining := []store.ExtUser{}
+	skipped := 0
+	for _, u := range unmatched {
+		decision, target, ok := ans.Users.Decide(u.ID, u.Email)
+		if !ok {
+			remaining = append(remaining, u)
+			continue
+		}
+		switch decision {
+		case answers.UserCreate:
+			createUser(ctx, fh, u)
+		case answers.UserSkip:
+			skipped++
+		case answers.UserLink:
+			if fhUsers == nil {
+				var err error
+				if fhUsers, err = fh.ListUsers(ctx); err != nil {
+					return nil, fmt.Errorf("unable to list FireHydrant users: %w", err)
+				}
+			}
+			i := slices.IndexFunc(fhUsers, func(fhUser store.FhUser) bool {
+				return target == fhUser.ID || strings.EqualFold(target, fhUser.Email)
+			})
+			if i < 0 {
+				return nil, fmt.Errorf("FireHydrant user '%s' for '%s' not found", target, u.Email)
 			}
-			console.Successf("[=] User '%s' linked to FireHydrant user '%s'.\n", u.Email, fhUser.Email)
+			if err := linkUser(ctx, fh, u, fhUsers[i], store.USER_MATCH_ANSWERS); err != nil {
+				console.Warnf("%s\n", err.Error())
+			}
+		}
+	}
+	if len(unmatched) > len(remaining) {
+		console.Successf("Handled %d unmatched users from answers file.\n", len(unmatched)-len(remaining))
+	}
+
+	// Skipping every user is the same as choosing "SKIP ALL" interactively.
+	if skipped == len(unmatched) {
+		console.Warnf("[<] No users will be created in FireHydrant.\n")
+		if err := store.UseQueries(ctx).DeleteUnmatchedExtUsers(ctx); err != nil {
+			return nil, fmt.Errorf("unable to delete unmatched users: %w", err)
 		}
 	}
+	return remaining, nil
+}
+
+// createUser creates the user in FireHydrant and links it. Failures are reported as warnings
+// so that the rest of the users can still be imported.
+func createUser(ctx context.Context, fh *firehydrant.Client, u store.ExtUser) {
+	fhUser, err := fh.CreateUser(ctx, &u)
+	if err != nil {
+		console.Warnf("unable to create user '%s': %s\n", u.Email, err.Error())
+		return
+	}
+	if err := linkUser(ctx, fh, u, *fhUser, store.USER_MATCH_CREATED); err != nil {
+		console.Warnf("%s\n", err.Error())
+	}
+}
+
+func linkUser(ctx context.Context, fh *firehydrant.Client, u store.ExtUser, fhUser store.FhUser, method string) error {
+	if err := fh.PairUsers(ctx, fhUser.ID, u.ID, method); err != nil {
+		return fmt.Errorf("unable to link user '%s': %w", u.Email, err)
+	}
+	console.Successf("[=] User '%s' linked to FireHydrant user '%s'.\n", u.Email, fhUser.Email)
 	return nil
 }
@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/firehydrant/signals-migrator/answers"
+	"github.com/firehydrant/signals-migrator/console"
+	"github.com/firehydrant/signals-migrator/internal/firehydrant"
+	"github.com/firehydrant/signals-migrator/internal/testkit"
+	"github.com/firehydrant/signals-migrator/store"
+)
+
+func TestLoadAnswersResumesWithAnswersFile(t *testing.T) {
+	dir := t.TempDir()
+	ctx := store.WithContextAndDSN(context.Background(), store.FileDSN(filepath.Join(dir, "state.db")))
+	defer store.FromContext(ctx).Close()
+
+	if err := store.UseQueries(ctx).SaveImportSession(ctx, store.SaveImportSessionParams{
+		Provider: "PagerDuty",
+		Answers:  `{"team_interface":"service","team_links":{"P1TEAM":"platform"},"users":{"create":["PUSER01"]}}`,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "answers.yaml")
+	if err := os.WriteFile(path, []byte("team_links:\n  P2TEAM: payments\nusers:\n  skip: [PUSER01]\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	ans, err := loadAnswers(ctx, path, "PagerDuty")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ans.TeamInterface != "service" {
+		t.Errorf("expected the team interface answered previously to be kept, got %q", ans.TeamInterface)
+	}
+	if ans.TeamLinks["P1TEAM"] != "platform" || ans.TeamLinks["P2TEAM"] != "payments" {
+		t.Errorf("expected team links from both the session and the file, got %v", ans.TeamLinks)
+	}
+	if len(ans.Users.Create) != 0 || len(ans.Users.Skip) != 1 {
+		t.Errorf("expected the file to replace the decision for PUSER01, got %+v", ans.Users)
+	}
+
+	if _, err := loadAnswers(ctx, path, "Opsgenie"); err == nil {
+		t.Errorf("expected the state file of another provider to be rejected")
+	}
+}
+
+func TestLinkExistingResources(t *testing.T) {
+	// Same as a non-interactive import, as there is no terminal to animate the spinner in.
+	console.StaticSpinner = true
+	t.Cleanup(func() { console.StaticSpinner = false })
+
+	ctx := testkit.NewStore(t, context.Background())
+	ts := testkit.NewHTTPServer(t)
+	fh, err := firehydrant.NewClient("testing-only", ts.URL)
+	if err != nil {
+		t.Fatalf("error creating FireHydrant client: %s", err)
+	}
+
+	if _, err := store.FromContext(ctx).ExecContext(ctx, `
+INSERT INTO fh_teams VALUES('47016143-6547-483a-b68a-5220b21681fd','Platform','platform');
+INSERT INTO ext_teams VALUES('PPLATFORM','Platform','platform','47016143-6547-483a-b68a-5220b21681fd',0,1,'');
+INSERT INTO ext_schedules_v2 VALUES('PSCHED','Primary','','UTC','PPLATFORM','pagerduty','PSCHED');
+INSERT INTO ext_rotations VALUES('PDAY','PSCHED','Day','','weekly','','','09:00:00','monday',0);
+INSERT INTO ext_escalation_policies VALUES('PPOLICY','Default escalation','','PPLATFORM',0,NULL,'','','',1);
+`); err != nil {
+		t.Fatal(err)
+	}
+
+	unanswered := &answers.Answers{NonInteractive: true}
+	if err := linkExistingResources(ctx, fh, unanswered); !errors.Is(err, answers.ErrUnanswered) {
+		t.Fatalf("expected the existing resources to be asked about, got %v", err)
+	}
+
+	// The answer given during the import is saved, and replaying it does not ask again.
+	path := filepath.Join(t.TempDir(), "answers.yaml")
+	given := &answers.Answers{ExistingResources: answers.Selection{Include: []string{"PSCHED"}}}
+	if err := given.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	replayed, err := answers.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	replayed.NonInteractive = true
+	if err := linkExistingResources(ctx, fh, replayed); err != nil {
+		t.Fatalf("expected the saved answers to be replayed, got %v", err)
+	}
+
+	for _, want := range []struct {
+		resourceType string
+		sourceID     string
+		importID     string
+	}{
+		{store.EXISTING_RESOURCE_SCHEDULE, "PSCHED", "47016143-6547-483a-b68a-5220b21681fd:5c8f2a3e-1d4b-4f6a-9e7c-2b3d4e5f6a7b"},
+		{store.EXISTING_RESOURCE_ROTATION, "PDAY", "47016143-6547-483a-b68a-5220b21681fd:5c8f2a3e-1d4b-4f6a-9e7c-2b3d4e5f6a7b:8a1b2c3d-4e5f-4a6b-8c7d-9e0f1a2b3c4d"},
+		{store.EXISTING_RESOURCE_ESCALATION_POLICY, "PPOLICY", ""},
+	} {
+		importID, err := store.ExistingImportID(ctx, want.resourceType, want.sourceID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if importID != want.importID {
+			t.Errorf("expected %s '%s' to be imported as %q, got %q", want.resourceType, want.sourceID, want.importID, importID)
+		}
+	}
+}
+
+func TestLinkExistingResourcesSkipsApplied(t *testing.T) {
+	console.StaticSpinner = true
+	t.Cleanup(func() { console.StaticSpinner = false })
+
+	ctx := testkit.NewStore(t, context.Background())
+	ts := testkit.NewHTTPServer(t)
+	fh, err := firehydrant.NewClient("testing-only", ts.URL)
+	if err != nil {
+		t.Fatalf("error creating FireHydrant client: %s", err)
+	}
+
+	// The schedule and policy were created by a previous run of the apply mode, and are now
+	// listed in the FireHydrant team under the same names.
+	if _, err := store.FromContext(ctx).ExecContext(ctx, `
+INSERT INTO fh_teams VALUES('47016143-6547-483a-b68a-5220b21681fd','Platform','platform');
+INSERT INTO ext_teams VALUES('PPLATFORM','Platform','platform','47016143-6547-483a-b68a-5220b21681fd',0,1,'');
+INSERT INTO ext_schedules_v2 VALUES('PSCHED','Primary','','UTC','PPLATFORM','pagerduty','PSCHED');
+INSERT INTO ext_rotations VALUES('PDAY','PSCHED','Day','','weekly','','','09:00:00','monday',0);
+INSERT INTO ext_escalation_policies VALUES('PPOLICY','Default escalation','','PPLATFORM',0,NULL,'','','',1);
+INSERT INTO ext_escalation_policies VALUES('POTHER','Other escalation','','PPLATFORM',0,NULL,'','','',1);
+INSERT INTO fh_applied_resources VALUES('on_call_schedule','PSCHED','5c8f2a3e-1d4b-4f6a-9e7c-2b3d4e5f6a7b');
+INSERT INTO fh_applied_resources VALUES('rotation','PDAY','8a1b2c3d-4e5f-4a6b-8c7d-9e0f1a2b3c4d');
+INSERT INTO fh_applied_resources VALUES('escalation_policy','PPOLICY','d4e5f6a7-b8c9-4d0e-8f1a-2b3c4d5e6f7a');
+`); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := linkExistingResources(ctx, fh, &answers.Answers{NonInteractive: true}); err != nil {
+		t.Fatalf("expected nothing to be asked about, got %v", err)
+	}
+	for _, existing := range [][2]string{
+		{store.EXISTING_RESOURCE_SCHEDULE, "PSCHED"},
+		{store.EXISTING_RESOURCE_ROTATION, "PDAY"},
+		{store.EXISTING_RESOURCE_ESCALATION_POLICY, "PPOLICY"},
+	} {
+		if importID, err := store.ExistingImportID(ctx, existing[0], existing[1]); err != nil || importID != "" {
+			t.Errorf("expected %s '%s' not to be matched, got %q (%v)", existing[0], existing[1], importID, err)
+		}
+	}
+}
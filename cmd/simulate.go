@@ -0,0 +1,172 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/firehydrant/signals-migrator/console"
+	"github.com/firehydrant/signals-migrator/pager"
+	"github.com/firehydrant/signals-migrator/simulate"
+	"github.com/firehydrant/signals-migrator/store"
+	"github.com/urfave/cli/v2"
+)
+
+var simulateFlags = []cli.Flag{
+	&cli.IntFlag{
+		Name:    "weeks",
+		Usage:   "Number of weeks from now to compare on-call coverage for",
+		EnvVars: []string{"SIMULATE_WEEKS"},
+		Value:   4,
+	},
+	&cli.StringFlag{
+		Name:    "state-file",
+		Usage:   "Simulate the schedules saved in this SQLite file by a previous import, instead of loading them from the provider",
+		EnvVars: []string{"STATE_FILE"},
+	},
+}
+
+var SimulateCommand = &cli.Command{
+	Name:   "simulate",
+	Usage:  "Compares who is on call in the provider's schedules with the rotations generated for FireHydrant",
+	Action: simulateAction,
+	Flags:  ConcatFlags([][]cli.Flag{simulateFlags, providerFlags}),
+}
+
+func simulateAction(cliCtx *cli.Context) error {
+	ctx, cancel := signal.NotifyContext(cliCtx.Context, os.Interrupt)
+	defer cancel()
+
+	providerName := cliCtx.String("provider")
+	provider, err := pager.NewPager(
+		ctx, providerName,
+		cliCtx.String("provider-api-key"),
+		cliCtx.String("provider-app-id"),
+		cliCtx.String("provider-api-url"),
+	)
+	if err != nil {
+		return fmt.Errorf("initializing pager provider: %w", err)
+	}
+	if err := preflight(ctx, provider); err != nil {
+		return err
+	}
+	if p, ok := provider.(pager.ConcurrentLoader); ok {
+		p.SetConcurrency(cliCtx.Int("concurrency"))
+	}
+	renderer, ok := provider.(pager.OnCallRenderer)
+	if !ok {
+		return fmt.Errorf("%s does not support rendering on-call schedules", provider.Kind())
+	}
+
+	weeks := cliCtx.Int("weeks")
+	if weeks < 1 {
+		return fmt.Errorf("--weeks must be at least 1, got %d", weeks)
+	}
+	since := time.Now().UTC().Truncate(time.Hour)
+	until := since.AddDate(0, 0, 7*weeks)
+
+	if stateFile := cliCtx.String("state-file"); stateFile != "" {
+		ctx = store.WithContextAndDSN(ctx, store.FileDSN(stateFile))
+		defer store.FromContext(ctx).Close()
+		completed, err := store.UseQueries(ctx).ListCompletedImportPhases(ctx)
+		if err != nil {
+			return fmt.Errorf("reading import progress: %w", err)
+		}
+		if !slices.Contains(completed, store.IMPORT_PHASE_SCHEDULES) {
+			return fmt.Errorf("state file %s has no imported schedules, run the import with it first", stateFile)
+		}
+	} else {
+		ctx = store.WithContext(ctx)
+		defer store.FromContext(ctx).Close()
+		if err := loadSchedules(ctx, provider, until.Sub(since)); err != nil {
+			return err
+		}
+	}
+
+	schedules, err := store.UseQueries(ctx).ListExtSchedulesV2(ctx)
+	if err != nil {
+		return fmt.Errorf("listing schedules: %w", err)
+	}
+	diverged := 0
+	for _, s := range schedules {
+		source, err := renderer.RenderOnCall(ctx, s.SourceScheduleID, since, until)
+		if err != nil {
+			return fmt.Errorf("rendering schedule '%s' from %s: %w", s.Name, provider.Kind(), err)
+		}
+		migrated, err := simulate.Schedule(ctx, s.ID, since, until)
+		if err != nil {
+			return fmt.Errorf("simulating schedule '%s': %w", s.Name, err)
+		}
+		divergences := simulate.Compare(source, migrated, since, until)
+		if len(divergences) == 0 {
+			console.Successf("[=] Schedule '%s' matches %s for the next %d weeks.\n", s.Name, provider.Kind(), weeks)
+			continue
+		}
+		diverged++
+		printDivergences(ctx, s, provider.Kind(), divergences)
+	}
+
+	if diverged > 0 {
+		return fmt.Errorf("%d of %d schedules diverge from %s", diverged, len(schedules), provider.Kind())
+	}
+	return nil
+}
+
+// loadSchedules loads everything the schedules depend on from the provider, without asking which
+// teams or users to import.
+func loadSchedules(ctx context.Context, provider pager.Pager, overrideWindow time.Duration) error {
+	if interfaces := provider.TeamInterfaces(); len(interfaces) > 0 {
+		if err := provider.UseTeamInterface(interfaces[0]); err != nil {
+			return fmt.Errorf("setting team interface: %w", err)
+		}
+	}
+	if p, ok := provider.(pager.OverrideImporter); ok {
+		p.SetOverrideWindow(overrideWindow)
+	}
+	for _, load := range []struct {
+		name string
+		run  func(context.Context) error
+	}{
+		{store.IMPORT_PHASE_USERS, provider.LoadUsers},
+		{store.IMPORT_PHASE_TEAMS, provider.LoadTeams},
+		{store.IMPORT_PHASE_SCHEDULES, provider.LoadSchedules},
+	} {
+		if err := load.run(ctx); err != nil {
+			return fmt.Errorf("loading %s from %s: %w", load.name, provider.Kind(), err)
+		}
+	}
+	return nil
+}
+
+func printDivergences(ctx context.Context, s store.ExtSchedulesV2, providerName string, divergences []simulate.Divergence) {
+	loc, err := time.LoadLocation(s.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	console.Warnf("[!] Schedule '%s' diverges from %s in %d periods (%s):\n", s.Name, providerName, len(divergences), loc)
+	for _, d := range divergences {
+		console.Warnf("  %s to %s: %s has %s, FireHydrant would have %s\n",
+			d.Start.In(loc).Format(time.DateTime), d.End.In(loc).Format(time.DateTime), providerName, userEmails(ctx, d.Source), userEmails(ctx, d.Migrated))
+	}
+}
+
+// userEmails describes the users by email, or by their provider ID for users who were not imported.
+func userEmails(ctx context.Context, userIDs []string) string {
+	if len(userIDs) == 0 {
+		return "nobody"
+	}
+	emails := make([]string, 0, len(userIDs))
+	for _, id := range userIDs {
+		u, err := store.UseQueries(ctx).GetUserByExtID(ctx, id)
+		if err != nil {
+			emails = append(emails, id)
+			continue
+		}
+		emails = append(emails, u.Email)
+	}
+	return strings.Join(emails, ", ")
+}
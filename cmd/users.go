@@ -0,0 +1,221 @@
+package cmd
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+
+	"github.com/firehydrant/signals-migrator/console"
+	"github.com/firehydrant/signals-migrator/diagnostics"
+	"github.com/firehydrant/signals-migrator/internal/firehydrant"
+	"github.com/firehydrant/signals-migrator/pager"
+	"github.com/firehydrant/signals-migrator/store"
+	"github.com/urfave/cli/v2"
+)
+
+// userMatchFlags configure how provider users are matched to FireHydrant users, by both import and
+// export-users.
+var userMatchFlags = []cli.Flag{
+	&cli.StringSliceFlag{
+		Name:    "user-match-strategies",
+		Usage:   "How to match users whose email has no exact match in FireHydrant: 'domain-alias', 'local-part' and 'name'",
+		EnvVars: []string{"USER_MATCH_STRATEGIES"},
+		Value:   cli.NewStringSlice(firehydrant.MatchStrategies...),
+	},
+	&cli.StringSliceFlag{
+		Name:    "user-domain-alias",
+		Usage:   "Email domain of the provider which is another in FireHydrant, written as 'old-domain.com=new-domain.com'",
+		EnvVars: []string{"USER_DOMAIN_ALIASES"},
+	},
+	&cli.Float64Flag{
+		Name:    "user-match-threshold",
+		Usage:   "Confidence between 0 and 1 from which a matched user is linked without asking, or above 1 to always ask",
+		EnvVars: []string{"USER_MATCH_THRESHOLD"},
+		Value:   0.9,
+	},
+	&cli.StringFlag{
+		Name:    "user-map",
+		Usage:   "CSV file linking provider users to FireHydrant users, by ID or email, which takes precedence over any other match",
+		EnvVars: []string{"USER_MAP_FILE"},
+	},
+}
+
+var exportUsersFlags = []cli.Flag{
+	&cli.StringFlag{
+		Name:    "output",
+		Usage:   "Write the matched users to this CSV file",
+		EnvVars: []string{"EXPORT_USERS_FILE"},
+		Value:   "users.csv",
+	},
+	&cli.StringFlag{
+		Name:    "state-file",
+		Usage:   "Export the users saved in this SQLite file by a previous import, instead of matching them again",
+		EnvVars: []string{"STATE_FILE"},
+	},
+}
+
+var ExportUsersCommand = &cli.Command{
+	Name:   "export-users",
+	Usage:  "Writes how provider users are matched to FireHydrant users to a CSV file, for review before importing",
+	Action: exportUsersAction,
+	// Exporting the users of a state file needs neither the provider nor FireHydrant.
+	Flags: ConcatFlags([][]cli.Flag{exportUsersFlags, userMatchFlags, optionalFlags(providerFlags), optionalFlags(flags)}),
+}
+
+func exportUsersAction(cliCtx *cli.Context) error {
+	ctx, cancel := signal.NotifyContext(cliCtx.Context, os.Interrupt)
+	defer cancel()
+	ctx = diagnostics.WithCollector(ctx)
+
+	if stateFile := cliCtx.String("state-file"); stateFile != "" {
+		ctx = store.WithContextAndDSN(ctx, store.FileDSN(stateFile))
+		defer store.FromContext(ctx).Close()
+		completed, err := store.UseQueries(ctx).ListCompletedImportPhases(ctx)
+		if err != nil {
+			return fmt.Errorf("reading import progress: %w", err)
+		}
+		if !slices.Contains(completed, store.IMPORT_PHASE_USERS) {
+			return fmt.Errorf("state file %s has no imported users, run the import with it first", stateFile)
+		}
+		return exportUsers(ctx, cliCtx.String("output"))
+	}
+
+	if err := requireFlags(cliCtx, "provider", "provider-api-key", "firehydrant-api-key"); err != nil {
+		return err
+	}
+	matcher, err := userMatcher(cliCtx)
+	if err != nil {
+		return err
+	}
+	userMap, err := loadUserMap(cliCtx.String("user-map"))
+	if err != nil {
+		return err
+	}
+	provider, err := pager.NewPager(
+		ctx, cliCtx.String("provider"),
+		cliCtx.String("provider-api-key"),
+		cliCtx.String("provider-app-id"),
+		cliCtx.String("provider-api-url"),
+	)
+	if err != nil {
+		return fmt.Errorf("initializing pager provider: %w", err)
+	}
+	if err := preflight(ctx, provider); err != nil {
+		return err
+	}
+	fh, err := firehydrant.NewClient(cliCtx.String("firehydrant-api-key"), cliCtx.String("firehydrant-api-endpoint"))
+	if err != nil {
+		return fmt.Errorf("initializing FireHydrant client: %w", err)
+	}
+
+	ctx = store.WithContext(ctx)
+	defer store.FromContext(ctx).Close()
+	if err := matchUsers(ctx, provider, fh, matcher, userMap); err != nil {
+		return err
+	}
+	if err := exportUsers(ctx, cliCtx.String("output")); err != nil {
+		return err
+	}
+	return printDiagnostics(ctx, "", diagnostics.FormatText)
+}
+
+// matchUsers loads the users from the provider and links those it can to FireHydrant users without
+// asking: by email, by the matcher's confident suggestions, and by the user map, in that order.
+func matchUsers(ctx context.Context, provider pager.Pager, fh *firehydrant.Client, matcher firehydrant.UserMatcher, userMap []firehydrant.UserMapping) error {
+	// Get all of the users registered from Pager Provider (e.g. PagerDuty)
+	var err error
+	console.Spin(func() {
+		err = provider.LoadUsers(ctx)
+	}, "Fetching all users from provider...")
+	if err != nil {
+		return fmt.Errorf("unable to fetch users from provider: %w", err)
+	}
+	console.Successf("Loaded all users from %s.\n", provider.Kind())
+
+	console.Spin(func() {
+		err = fh.MatchUsers(ctx, matcher)
+	}, "Matching users with existing FireHydrant users...")
+	if err != nil {
+		return fmt.Errorf("unable to match users to FireHydrant: %w", err)
+	}
+
+	if len(userMap) > 0 {
+		linked, err := fh.ApplyUserMap(ctx, userMap)
+		if err != nil {
+			return fmt.Errorf("unable to apply user map: %w", err)
+		}
+		console.Successf("Linked %d users from user map.\n", linked)
+	}
+	return nil
+}
+
+// userMatcher returns the matcher for users without an exact match, as configured by the flags.
+func userMatcher(cliCtx *cli.Context) (firehydrant.UserMatcher, error) {
+	strategies := cliCtx.StringSlice("user-match-strategies")
+	for _, s := range strategies {
+		if !slices.Contains(firehydrant.MatchStrategies, s) {
+			return firehydrant.UserMatcher{}, fmt.Errorf("unknown user match strategy '%s', expected one of: %s", s, strings.Join(firehydrant.MatchStrategies, ", "))
+		}
+	}
+	aliases, err := firehydrant.ParseDomainAliases(cliCtx.StringSlice("user-domain-alias"))
+	if err != nil {
+		return firehydrant.UserMatcher{}, err
+	}
+	return firehydrant.UserMatcher{
+		Strategies:    strategies,
+		DomainAliases: aliases,
+		Threshold:     cliCtx.Float64("user-match-threshold"),
+	}, nil
+}
+
+// loadUserMap reads the user map given by --user-map, if any. It is read before anything is
+// imported, so that a malformed file fails right away.
+func loadUserMap(path string) ([]firehydrant.UserMapping, error) {
+	if path == "" {
+		return nil, nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening user map: %w", err)
+	}
+	defer f.Close()
+	return firehydrant.ReadUserMap(f)
+}
+
+// exportUsers writes every provider user, with the FireHydrant user they are linked to and how,
+// to a CSV file which may be edited and given back to the import as --user-map.
+func exportUsers(ctx context.Context, path string) error {
+	users, err := store.UseQueries(ctx).ListUserMatches(ctx)
+	if err != nil {
+		return fmt.Errorf("listing users: %w", err)
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("creating %s: %w", path, err)
+	}
+	defer f.Close()
+
+	w := csv.NewWriter(f)
+	_ = w.Write([]string{"ext_user_id", "ext_email", "fh_user_id", "fh_email", "match_method"})
+	matched := 0
+	for _, u := range users {
+		if u.FhUserID.Valid {
+			matched++
+		}
+		_ = w.Write([]string{u.ID, u.Email, u.FhUserID.String, u.FhEmail.String, u.Method.String})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return fmt.Errorf("writing %s: %w", path, err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("writing %s: %w", path, err)
+	}
+	console.Successf("Exported %d users, %d of them matched to FireHydrant, to %s.\n", len(users), matched, path)
+	return nil
+}
@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/firehydrant/signals-migrator/store"
+	"github.com/urfave/cli/v2"
+)
+
+func TestExportUsersFromStateFile(t *testing.T) {
+	for _, env := range []string{"PROVIDER", "PROVIDER_API_KEY", "FIREHYDRANT_API_KEY"} {
+		t.Setenv(env, "")
+		os.Unsetenv(env)
+	}
+
+	dir := t.TempDir()
+	stateFile := filepath.Join(dir, "state.db")
+	ctx := store.WithContextAndDSN(context.Background(), store.FileDSN(stateFile))
+	q := store.UseQueries(ctx)
+	if err := q.InsertExtUser(ctx, store.InsertExtUserParams{ID: "PJANE", Name: "Jane Doe", Email: "jane@example.com"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := q.CompleteImportPhase(ctx, store.CompleteImportPhaseParams{Name: store.IMPORT_PHASE_USERS, CompletedAt: "2024-04-01T00:00:00Z"}); err != nil {
+		t.Fatal(err)
+	}
+	store.FromContext(ctx).Close()
+
+	app := &cli.App{Commands: []*cli.Command{ExportUsersCommand}}
+	output := filepath.Join(dir, "users.csv")
+	if err := app.Run([]string{"signals-migrator", "export-users", "--state-file", stateFile, "--output", output}); err != nil {
+		t.Fatalf("expected no API key to be needed with a state file, got %v", err)
+	}
+	b, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(b), "PJANE,jane@example.com") {
+		t.Errorf("expected the user of the state file to be exported, got %q", b)
+	}
+
+	err = app.Run([]string{"signals-migrator", "export-users", "--output", output})
+	if err == nil || !strings.Contains(err.Error(), `"provider-api-key"`) {
+		t.Errorf("expected the API keys to be required without a state file, got %v", err)
+	}
+}
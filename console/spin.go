@@ -6,9 +6,14 @@ import (
 	"github.com/charmbracelet/huh/spinner"
 )
 
+// StaticSpinner prints a static line instead of an animated spinner, which requires a terminal.
+// It is meant for unattended runs, e.g. from CI.
+var StaticSpinner bool
+
 func Spin(action func(), title string, args ...any) {
 	if err := spinner.New().
 		Title(fmt.Sprintf(title, args...)).
+		Accessible(StaticSpinner).
 		Action(action).Run(); err != nil {
 		panic(err)
 	}
@@ -0,0 +1,111 @@
+package diagnostics
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/firehydrant/signals-migrator/console"
+	"github.com/firehydrant/signals-migrator/store"
+)
+
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+	SeverityInfo    Severity = "info"
+)
+
+// Resource kinds which diagnostics are recorded against.
+const (
+	ResourceUser             = "user"
+	ResourceTeam             = "team"
+	ResourceSchedule         = "schedule"
+	ResourceRotation         = "rotation"
+	ResourceOverride         = "override"
+	ResourceEscalationPolicy = "escalation_policy"
+)
+
+// Entry is a single diagnostic about a resource, identified by its ID in the source provider.
+type Entry struct {
+	Severity Severity `json:"severity"`
+	Resource string   `json:"resource"`
+	SourceID string   `json:"source_id"`
+	Message  string   `json:"message"`
+}
+
+// Collector accumulates the diagnostics recorded during a migration.
+type Collector struct {
+	mu      sync.Mutex
+	entries []Entry
+}
+
+func (c *Collector) Record(e Entry) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = append(c.entries, e)
+}
+
+// Entries returns the diagnostics in the order they were recorded.
+func (c *Collector) Entries() []Entry {
+	if c == nil {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]Entry(nil), c.entries...)
+}
+
+type ctxKey struct{}
+
+// WithCollector returns a context which diagnostics are recorded into.
+func WithCollector(ctx context.Context) context.Context {
+	return context.WithValue(ctx, ctxKey{}, &Collector{})
+}
+
+// FromContext returns the collector of the context, or nil when there is none. Recording into a
+// nil collector does nothing, so diagnostics are only printed.
+func FromContext(ctx context.Context) *Collector {
+	c, _ := ctx.Value(ctxKey{}).(*Collector)
+	return c
+}
+
+// Errorf prints an error about a resource and records it into the context's collector.
+func Errorf(ctx context.Context, resource string, sourceID string, format string, args ...any) {
+	record(ctx, SeverityError, console.Errorf, resource, sourceID, format, args...)
+}
+
+// Warnf prints a warning about a resource and records it into the context's collector.
+func Warnf(ctx context.Context, resource string, sourceID string, format string, args ...any) {
+	record(ctx, SeverityWarning, console.Warnf, resource, sourceID, format, args...)
+}
+
+// Infof prints a notice about a resource and records it into the context's collector.
+func Infof(ctx context.Context, resource string, sourceID string, format string, args ...any) {
+	record(ctx, SeverityInfo, console.Infof, resource, sourceID, format, args...)
+}
+
+func record(ctx context.Context, severity Severity, print func(string, ...any), resource string, sourceID string, format string, args ...any) {
+	msg := strings.TrimSpace(fmt.Sprintf(format, args...))
+	print("%s\n", msg)
+	FromContext(ctx).Record(Entry{
+		Severity: severity,
+		Resource: resource,
+		SourceID: sourceID,
+		Message:  msg,
+	})
+}
+
+// SkipStepTarget warns that a target of an escalation policy step is dropped, and persists the
+// drop so that the report can flag steps which are left without anyone to notify.
+func SkipStepTarget(ctx context.Context, skip store.InsertExtEscalationPolicyStepTargetSkipParams, format string, args ...any) {
+	Warnf(ctx, ResourceEscalationPolicy, skip.EscalationPolicyID, format, args...)
+	if err := store.UseQueries(ctx).InsertExtEscalationPolicyStepTargetSkip(ctx, skip); err != nil {
+		console.Errorf("recording skipped target '%s' of escalation policy '%s': %s\n", skip.TargetID, skip.EscalationPolicyID, err.Error())
+	}
+}
@@ -7,7 +7,7 @@ import (
 	"github.com/firehydrant/signals-migrator/store"
 )
 
-// Write renders the migration diagnostics report to w.
+// Write renders the schedule coverage section of the diagnostics report to w.
 // It reports schedules that will have incomplete member coverage due to missing
 // FireHydrant users, grouped by schedule and rotation.
 // Returns without writing if there are no skips.
@@ -79,3 +79,77 @@ func Write(w io.Writer, skips []store.ListRotationMemberSkipsRow) error {
 	_, err := fmt.Fprintln(w, "To fix: ensure these users exist in FireHydrant and re-run the migration.")
 	return err
 }
+
+// WriteEscalationTargetSkips renders the escalation policy section of the diagnostics report to w.
+// It reports targets which were dropped from escalation policy steps, grouped by policy and step,
+// and flags steps which were left without any target.
+// Returns without writing if there are no skips.
+func WriteEscalationTargetSkips(w io.Writer, skips []store.ListEscalationPolicyStepTargetSkipsRow) error {
+	if len(skips) == 0 {
+		return nil
+	}
+
+	type stepKey struct {
+		policy   string
+		position int64
+	}
+	grouped := make(map[stepKey][]store.ListEscalationPolicyStepTargetSkipsRow)
+	var order []stepKey
+	seenPolicies := make(map[string]bool)
+	emptySteps := 0
+
+	for _, s := range skips {
+		k := stepKey{s.EscalationPolicyID, s.StepPosition}
+		if _, ok := grouped[k]; !ok {
+			order = append(order, k)
+			if s.RemainingTargets == 0 {
+				emptySteps++
+			}
+		}
+		grouped[k] = append(grouped[k], s)
+		seenPolicies[s.EscalationPolicyID] = true
+	}
+
+	lines := []string{
+		"DIAGNOSTICS: Escalation Policies Missing Targets",
+		"================================================",
+		"",
+		"The following escalation policy steps lost one or more targets which",
+		"could not be migrated. Steps marked EMPTY will notify nobody.",
+		"",
+	}
+	for _, line := range lines {
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+
+	var lastPolicy string
+	for _, k := range order {
+		rows := grouped[k]
+		if k.policy != lastPolicy {
+			if _, err := fmt.Fprintf(w, "  Escalation policy: %q (%s)\n", rows[0].EscalationPolicyName, k.policy); err != nil {
+				return err
+			}
+			lastPolicy = k.policy
+		}
+		flag := ""
+		if rows[0].RemainingTargets == 0 {
+			flag = " — EMPTY"
+		}
+		if _, err := fmt.Fprintf(w, "    Step %d%s\n", k.position+1, flag); err != nil {
+			return err
+		}
+		for _, s := range rows {
+			if _, err := fmt.Fprintf(w, "      - %s %s — %s\n", s.TargetType, s.TargetID, s.Reason); err != nil {
+				return err
+			}
+		}
+		if _, err := fmt.Fprintln(w); err != nil {
+			return err
+		}
+	}
+
+	_, err := fmt.Fprintf(w, "%d escalation policy(s) affected, %d step(s) left empty.\n", len(seenPolicies), emptySteps)
+	return err
+}
@@ -183,3 +183,48 @@ func assertContains(t *testing.T, output, substr string) {
 		t.Errorf("expected output to contain %q\nfull output:\n%s", substr, output)
 	}
 }
+
+func TestWriteEscalationTargetSkips_NoSkips(t *testing.T) {
+	var b strings.Builder
+	if err := diagnostics.WriteEscalationTargetSkips(&b, nil); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if b.Len() != 0 {
+		t.Errorf("expected no output for empty skips, got: %q", b.String())
+	}
+}
+
+func TestWriteEscalationTargetSkips_FlagsEmptySteps(t *testing.T) {
+	skips := []store.ListEscalationPolicyStepTargetSkipsRow{
+		{EscalationPolicyID: "P1", EscalationPolicyName: "Ops", StepPosition: 0, TargetType: "team", TargetID: "T1", Reason: "unsupported_target_type", RemainingTargets: 2},
+		{EscalationPolicyID: "P1", EscalationPolicyName: "Ops", StepPosition: 1, TargetType: "user", TargetID: "U1", Reason: "missing_fh_user", RemainingTargets: 0},
+		{EscalationPolicyID: "P1", EscalationPolicyName: "Ops", StepPosition: 1, TargetType: "user", TargetID: "U2", Reason: "missing_fh_user", RemainingTargets: 0},
+		{EscalationPolicyID: "P2", EscalationPolicyName: "Web", StepPosition: 0, TargetType: "schedule", TargetID: "S1", Reason: "target_not_imported", RemainingTargets: 0},
+	}
+
+	var b strings.Builder
+	if err := diagnostics.WriteEscalationTargetSkips(&b, skips); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	out := b.String()
+	assertContains(t, out, `Escalation policy: "Ops" (P1)`)
+	assertContains(t, out, "    Step 1\n")
+	assertContains(t, out, "Step 2 — EMPTY")
+	assertContains(t, out, "user U2 — missing_fh_user")
+	assertContains(t, out, `Escalation policy: "Web" (P2)`)
+	assertContains(t, out, "2 escalation policy(s) affected, 2 step(s) left empty.")
+	if n := strings.Count(out, "Escalation policy: \"Ops\""); n != 1 {
+		t.Errorf("expected policy header once, got %d", n)
+	}
+}
+
+func TestWriteEscalationTargetSkips_WriteError(t *testing.T) {
+	skips := []store.ListEscalationPolicyStepTargetSkipsRow{
+		{EscalationPolicyID: "P1", EscalationPolicyName: "Ops", TargetType: "team", TargetID: "T1", Reason: "unsupported_target_type"},
+	}
+	want := errors.New("disk full")
+	if err := diagnostics.WriteEscalationTargetSkips(errWriter{want}, skips); !errors.Is(err, want) {
+		t.Errorf("expected %v, got %v", want, err)
+	}
+}
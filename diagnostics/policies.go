@@ -0,0 +1,27 @@
+package diagnostics
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/firehydrant/signals-migrator/store"
+)
+
+// RepeatInterval warns when an escalation policy repeated after an interval other than the timeout
+// of its last step, which is when FireHydrant repeats it, as there is no separate repeat interval
+// in FireHydrant. It returns a comment to explain the difference on the migrated policy, or an
+// empty string when the policy repeats the same way in FireHydrant.
+func RepeatInterval(ctx context.Context, p store.ExtEscalationPolicy) (string, error) {
+	if p.RepeatLimit == 0 || !p.RepeatInterval.Valid || p.RepeatInterval.String == "" {
+		return "", nil
+	}
+	steps, err := store.UseQueries(ctx).ListExtEscalationPolicySteps(ctx, p.ID)
+	if err != nil {
+		return "", fmt.Errorf("querying steps for policy '%s': %w", p.Name, err)
+	}
+	if n := len(steps); n > 0 && steps[n-1].Timeout == p.RepeatInterval.String {
+		return "", nil
+	}
+	Warnf(ctx, ResourceEscalationPolicy, p.ID, "%s repeated %s after its last step, which FireHydrant does not support. It repeats once the last step times out instead.\n", p.Name, p.RepeatInterval.String)
+	return fmt.Sprintf("Originally repeated %s after the last step. FireHydrant repeats once the last step times out.", p.RepeatInterval.String), nil
+}
@@ -0,0 +1,199 @@
+package diagnostics
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/firehydrant/signals-migrator/store"
+)
+
+type Format string
+
+const (
+	FormatText     Format = "text"
+	FormatJSON     Format = "json"
+	FormatMarkdown Format = "markdown"
+)
+
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(strings.ToLower(s)); f {
+	case FormatText, FormatJSON, FormatMarkdown:
+		return f, nil
+	case "md":
+		return FormatMarkdown, nil
+	default:
+		return "", fmt.Errorf("unknown diagnostics format '%s', expected one of text, json or markdown", s)
+	}
+}
+
+// Report is the full diagnostics of a migration: everything recorded while loading and rendering
+// resources, and the rotation members and escalation policy targets which were skipped.
+type Report struct {
+	Entries               []Entry                                        `json:"entries"`
+	RotationMemberSkips   []store.ListRotationMemberSkipsRow             `json:"rotation_member_skips"`
+	EscalationTargetSkips []store.ListEscalationPolicyStepTargetSkipsRow `json:"escalation_target_skips"`
+}
+
+var severityOrder = map[Severity]int{SeverityError: 0, SeverityWarning: 1, SeverityInfo: 2}
+
+// NewReport sorts entries by severity, resource and source ID, so that reports of separate runs
+// can be diffed. Entries about the same resource keep the order they were recorded in.
+func NewReport(entries []Entry, skips []store.ListRotationMemberSkipsRow, targetSkips []store.ListEscalationPolicyStepTargetSkipsRow) Report {
+	entries = append([]Entry{}, entries...)
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if a.Severity != b.Severity {
+			return severityOrder[a.Severity] < severityOrder[b.Severity]
+		}
+		if a.Resource != b.Resource {
+			return a.Resource < b.Resource
+		}
+		return a.SourceID < b.SourceID
+	})
+	if skips == nil {
+		skips = []store.ListRotationMemberSkipsRow{}
+	}
+	if targetSkips == nil {
+		targetSkips = []store.ListEscalationPolicyStepTargetSkipsRow{}
+	}
+	return Report{Entries: entries, RotationMemberSkips: skips, EscalationTargetSkips: targetSkips}
+}
+
+// emptySteps counts the escalation policy steps which lost all of their targets.
+func (r Report) emptySteps() int {
+	type stepKey struct {
+		policy   string
+		position int64
+	}
+	seen := make(map[stepKey]bool)
+	for _, s := range r.EscalationTargetSkips {
+		if s.RemainingTargets == 0 {
+			seen[stepKey{s.EscalationPolicyID, s.StepPosition}] = true
+		}
+	}
+	return len(seen)
+}
+
+func (r Report) count(severity Severity) int {
+	n := 0
+	for _, e := range r.Entries {
+		if e.Severity == severity {
+			n++
+		}
+	}
+	return n
+}
+
+// Write renders the report to w in the given format. The text format writes nothing when there is
+// nothing to report, while the other formats always write a complete document.
+func (r Report) Write(w io.Writer, format Format) error {
+	switch format {
+	case FormatJSON:
+		return r.writeJSON(w)
+	case FormatMarkdown:
+		return r.writeMarkdown(w)
+	default:
+		return r.writeText(w)
+	}
+}
+
+func (r Report) writeJSON(w io.Writer) error {
+	doc := struct {
+		Summary map[string]int `json:"summary"`
+		Report
+	}{
+		Summary: map[string]int{
+			string(SeverityError):     r.count(SeverityError),
+			string(SeverityWarning):   r.count(SeverityWarning),
+			string(SeverityInfo):      r.count(SeverityInfo),
+			"rotation_member_skips":   len(r.RotationMemberSkips),
+			"escalation_target_skips": len(r.EscalationTargetSkips),
+			"empty_escalation_steps":  r.emptySteps(),
+		},
+		Report: r,
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(doc)
+}
+
+func (r Report) writeText(w io.Writer) error {
+	if len(r.Entries) > 0 {
+		lines := []string{
+			"DIAGNOSTICS: Migration Warnings",
+			"===============================",
+			"",
+		}
+		for _, e := range r.Entries {
+			lines = append(lines, fmt.Sprintf("  [%s] %s %s: %s", e.Severity, e.Resource, e.SourceID, e.Message))
+		}
+		lines = append(lines, "",
+			fmt.Sprintf("%d error(s), %d warning(s), %d notice(s).", r.count(SeverityError), r.count(SeverityWarning), r.count(SeverityInfo)),
+			"",
+		)
+		for _, line := range lines {
+			if _, err := fmt.Fprintln(w, line); err != nil {
+				return err
+			}
+		}
+	}
+	if err := Write(w, r.RotationMemberSkips); err != nil {
+		return err
+	}
+	if len(r.RotationMemberSkips) > 0 && len(r.EscalationTargetSkips) > 0 {
+		if _, err := fmt.Fprintln(w); err != nil {
+			return err
+		}
+	}
+	return WriteEscalationTargetSkips(w, r.EscalationTargetSkips)
+}
+
+func (r Report) writeMarkdown(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("# Migration diagnostics\n\n")
+	fmt.Fprintf(&b, "| Errors | Warnings | Notices | Skipped rotation members | Skipped escalation targets | Empty escalation steps |\n| --- | --- | --- | --- | --- | --- |\n| %d | %d | %d | %d | %d | %d |\n",
+		r.count(SeverityError), r.count(SeverityWarning), r.count(SeverityInfo), len(r.RotationMemberSkips), len(r.EscalationTargetSkips), r.emptySteps())
+
+	if len(r.Entries) > 0 {
+		b.WriteString("\n## Warnings\n\n| Severity | Resource | Source ID | Message |\n| --- | --- | --- | --- |\n")
+		for _, e := range r.Entries {
+			fmt.Fprintf(&b, "| %s | %s | %s | %s |\n", e.Severity, e.Resource, markdownCell(e.SourceID), markdownCell(e.Message))
+		}
+	}
+
+	if len(r.RotationMemberSkips) > 0 {
+		b.WriteString("\n## Incomplete schedule coverage\n\n")
+		b.WriteString("The following rotations are missing users who are not matched to a FireHydrant user.\n\n")
+		b.WriteString("| Schedule | Rotation | User | User ID | Reason |\n| --- | --- | --- | --- | --- |\n")
+		for _, s := range r.RotationMemberSkips {
+			fmt.Fprintf(&b, "| %s | %s | %s | %s | %s |\n",
+				markdownCell(s.ScheduleName), markdownCell(s.RotationName), markdownCell(s.UserEmail), markdownCell(s.UserID), s.Reason)
+		}
+	}
+
+	if len(r.EscalationTargetSkips) > 0 {
+		b.WriteString("\n## Escalation policies missing targets\n\n")
+		b.WriteString("The following escalation policy steps lost targets which could not be migrated. Steps left without any target will notify nobody.\n\n")
+		b.WriteString("| Escalation policy | Step | Target type | Target ID | Reason | Step empty |\n| --- | --- | --- | --- | --- | --- |\n")
+		for _, s := range r.EscalationTargetSkips {
+			empty := "no"
+			if s.RemainingTargets == 0 {
+				empty = "**yes**"
+			}
+			fmt.Fprintf(&b, "| %s | %d | %s | %s | %s | %s |\n",
+				markdownCell(s.EscalationPolicyName), s.StepPosition+1, s.TargetType, markdownCell(s.TargetID), s.Reason, empty)
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// markdownCell escapes a value so it stays within its table cell.
+func markdownCell(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.Join(strings.Fields(s), " ")
+}
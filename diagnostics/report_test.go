@@ -0,0 +1,152 @@
+package diagnostics_test
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/firehydrant/signals-migrator/diagnostics"
+	"github.com/firehydrant/signals-migrator/store"
+)
+
+func TestParseFormat(t *testing.T) {
+	for in, want := range map[string]diagnostics.Format{
+		"text":     diagnostics.FormatText,
+		"JSON":     diagnostics.FormatJSON,
+		"markdown": diagnostics.FormatMarkdown,
+		"md":       diagnostics.FormatMarkdown,
+	} {
+		got, err := diagnostics.ParseFormat(in)
+		if err != nil {
+			t.Errorf("unexpected error parsing %q: %s", in, err)
+		}
+		if got != want {
+			t.Errorf("expected %q to parse as %q, got %q", in, want, got)
+		}
+	}
+	if _, err := diagnostics.ParseFormat("yaml"); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
+
+func TestCollector(t *testing.T) {
+	// Without a collector, diagnostics are only printed.
+	diagnostics.Warnf(context.Background(), diagnostics.ResourceSchedule, "P1", "not recorded")
+
+	ctx := diagnostics.WithCollector(context.Background())
+	diagnostics.Warnf(ctx, diagnostics.ResourceSchedule, "PSCHED", "Schedule %q is skipped\n", "Infra")
+	diagnostics.Infof(ctx, diagnostics.ResourceRotation, "PROT", "Rotation ended")
+
+	got := diagnostics.FromContext(ctx).Entries()
+	want := []diagnostics.Entry{
+		{Severity: diagnostics.SeverityWarning, Resource: diagnostics.ResourceSchedule, SourceID: "PSCHED", Message: `Schedule "Infra" is skipped`},
+		{Severity: diagnostics.SeverityInfo, Resource: diagnostics.ResourceRotation, SourceID: "PROT", Message: "Rotation ended"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d entries, got %d: %+v", len(want), len(got), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("entry %d: expected %+v, got %+v", i, want[i], got[i])
+		}
+	}
+}
+
+func reportFixture() diagnostics.Report {
+	return diagnostics.NewReport([]diagnostics.Entry{
+		{Severity: diagnostics.SeverityInfo, Resource: diagnostics.ResourceRotation, SourceID: "PR1", Message: "Layer ended"},
+		{Severity: diagnostics.SeverityWarning, Resource: diagnostics.ResourceSchedule, SourceID: "PS2", Message: "Team | not imported"},
+		{Severity: diagnostics.SeverityError, Resource: diagnostics.ResourceEscalationPolicy, SourceID: "PE1", Message: "Unknown target type"},
+		{Severity: diagnostics.SeverityWarning, Resource: diagnostics.ResourceSchedule, SourceID: "PS1", Message: "No teams found"},
+	}, []store.ListRotationMemberSkipsRow{
+		{ScheduleName: "Infra", RotationName: "Primary", UserID: "PA", UserEmail: "a@example.com", Reason: "missing_fh_user"},
+	}, []store.ListEscalationPolicyStepTargetSkipsRow{
+		{EscalationPolicyID: "PE1", EscalationPolicyName: "Infra", StepPosition: 0, TargetType: "webhook", TargetID: "hook", Reason: "unsupported_target_type", RemainingTargets: 1},
+		{EscalationPolicyID: "PE1", EscalationPolicyName: "Infra", StepPosition: 1, TargetType: "schedule", TargetID: "PS9", Reason: "target_not_imported", RemainingTargets: 0},
+	})
+}
+
+func TestReport_JSON(t *testing.T) {
+	var b strings.Builder
+	if err := reportFixture().Write(&b, diagnostics.FormatJSON); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	var doc struct {
+		Summary               map[string]int                                 `json:"summary"`
+		Entries               []diagnostics.Entry                            `json:"entries"`
+		RotationMemberSkips   []store.ListRotationMemberSkipsRow             `json:"rotation_member_skips"`
+		EscalationTargetSkips []store.ListEscalationPolicyStepTargetSkipsRow `json:"escalation_target_skips"`
+	}
+	if err := json.Unmarshal([]byte(b.String()), &doc); err != nil {
+		t.Fatalf("report is not valid JSON: %s\n%s", err, b.String())
+	}
+	if doc.Summary["error"] != 1 || doc.Summary["warning"] != 2 || doc.Summary["info"] != 1 || doc.Summary["rotation_member_skips"] != 1 {
+		t.Errorf("unexpected summary: %+v", doc.Summary)
+	}
+	// Entries are sorted by severity, resource and source ID so that runs can be diffed.
+	ids := []string{}
+	for _, e := range doc.Entries {
+		ids = append(ids, e.SourceID)
+	}
+	if got := strings.Join(ids, ","); got != "PE1,PS1,PS2,PR1" {
+		t.Errorf("expected entries ordered PE1,PS1,PS2,PR1, got %s", got)
+	}
+	if len(doc.RotationMemberSkips) != 1 || doc.RotationMemberSkips[0].UserID != "PA" {
+		t.Errorf("unexpected rotation member skips: %+v", doc.RotationMemberSkips)
+	}
+	if doc.Summary["escalation_target_skips"] != 2 || doc.Summary["empty_escalation_steps"] != 1 {
+		t.Errorf("unexpected escalation target summary: %+v", doc.Summary)
+	}
+	if len(doc.EscalationTargetSkips) != 2 || doc.EscalationTargetSkips[1].TargetID != "PS9" {
+		t.Errorf("unexpected escalation target skips: %+v", doc.EscalationTargetSkips)
+	}
+}
+
+func TestReport_JSONEmpty(t *testing.T) {
+	var b strings.Builder
+	if err := diagnostics.NewReport(nil, nil, nil).Write(&b, diagnostics.FormatJSON); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	assertContains(t, b.String(), `"entries": []`)
+	assertContains(t, b.String(), `"rotation_member_skips": []`)
+	assertContains(t, b.String(), `"escalation_target_skips": []`)
+}
+
+func TestReport_Markdown(t *testing.T) {
+	var b strings.Builder
+	if err := reportFixture().Write(&b, diagnostics.FormatMarkdown); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	out := b.String()
+	assertContains(t, out, "| 1 | 2 | 1 | 1 | 2 | 1 |")
+	assertContains(t, out, "| error | escalation_policy | PE1 | Unknown target type |")
+	// Pipes in messages are escaped so they don't split the table cell.
+	assertContains(t, out, `| warning | schedule | PS2 | Team \| not imported |`)
+	assertContains(t, out, "| Infra | Primary | a@example.com | PA | missing_fh_user |")
+	assertContains(t, out, "| Infra | 2 | schedule | PS9 | target_not_imported | **yes** |")
+}
+
+func TestReport_Text(t *testing.T) {
+	var b strings.Builder
+	if err := reportFixture().Write(&b, diagnostics.FormatText); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	out := b.String()
+	assertContains(t, out, "[warning] schedule PS1: No teams found")
+	assertContains(t, out, "1 error(s), 2 warning(s), 1 notice(s).")
+	assertContains(t, out, `Schedule: "Infra"`)
+	assertContains(t, out, `Escalation policy: "Infra" (PE1)`)
+	assertContains(t, out, "Step 2 — EMPTY")
+
+	b.Reset()
+	if err := diagnostics.NewReport(nil, nil, nil).Write(&b, diagnostics.FormatText); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if b.Len() != 0 {
+		t.Errorf("expected no text output for an empty report, got: %q", b.String())
+	}
+}
@@ -19,6 +19,7 @@ require (
 
 require (
 	github.com/firehydrant/firehydrant-go-sdk v1.7.1
+	gopkg.in/yaml.v3 v3.0.1
 	gotest.tools/v3 v3.5.2
 )
 
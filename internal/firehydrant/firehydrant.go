@@ -14,6 +14,7 @@ import (
 	"github.com/firehydrant/firehydrant-go-sdk/models/components"
 	"github.com/firehydrant/firehydrant-go-sdk/models/operations"
 	"github.com/firehydrant/signals-migrator/console"
+	"github.com/firehydrant/signals-migrator/diagnostics"
 	"github.com/firehydrant/signals-migrator/store"
 	"github.com/firehydrant/terraform-provider-firehydrant/firehydrant"
 )
@@ -165,12 +166,12 @@ func (c *Client) fetchUser(ctx context.Context, email string) (*store.FhUser, er
 }
 
 // MatchUsers attempts to pair users in the parameter with its FireHydrant User counterpart.
-// Returns: a list of users which were not successfully matched.
-func (c *Client) MatchUsers(ctx context.Context) error {
+// Users are paired by email first. The rest are paired with the best suggestion of the matcher,
+// when it is confident enough and that FireHydrant user is not paired yet.
+func (c *Client) MatchUsers(ctx context.Context, matcher UserMatcher) error {
 	q := store.UseQueries(ctx)
 
-	// Calling ListUsers just to make sure DB store exists.
-	_, err := c.ListUsers(ctx)
+	fhUsers, err := c.ListUsers(ctx)
 	if err != nil {
 		return fmt.Errorf("fetching FireHydrant users: %w", err)
 	}
@@ -182,20 +183,61 @@ func (c *Client) MatchUsers(ctx context.Context) error {
 
 	for _, user := range users {
 		if user.FhUser.ID != "" {
-			if err := c.PairUsers(ctx, user.FhUser.ID, user.ExtUser.ID); err != nil {
+			if err := c.PairUsers(ctx, user.FhUser.ID, user.ExtUser.ID, store.USER_MATCH_EMAIL); err != nil {
 				return fmt.Errorf("pairing users: %w", err)
 			}
 		}
 	}
 
+	extUsers, err := q.ListExtUsers(ctx)
+	if err != nil {
+		return fmt.Errorf("listing external users: %w", err)
+	}
+	paired := map[string]bool{}
+	for _, u := range extUsers {
+		if u.FhUserID.Valid {
+			paired[u.FhUserID.String] = true
+		}
+	}
+	for _, u := range extUsers {
+		if u.FhUserID.Valid {
+			continue
+		}
+		best, ok := matcher.Best(matcher.Suggest(u, fhUsers))
+		if !ok || paired[best.User.ID] {
+			continue
+		}
+		if err := c.PairUsers(ctx, best.User.ID, u.ID, store.USER_MATCH_SUGGESTED); err != nil {
+			return fmt.Errorf("pairing users: %w", err)
+		}
+		paired[best.User.ID] = true
+		diagnostics.Infof(ctx, diagnostics.ResourceUser, u.ID, "Matched %s (%s) to FireHydrant user %s (%s), %.0f%% confident: %s.\n",
+			u.Name, u.Email, best.User.Name, best.User.Email, best.Confidence*100, strings.Join(best.Reasons, ", "))
+	}
+
 	return nil
 }
 
-func (c *Client) PairUsers(ctx context.Context, fhUserID string, extUserID string) error {
-	return store.UseQueries(ctx).LinkExtUser(ctx, store.LinkExtUserParams{
+// PairUsers links the provider user to the FireHydrant user, and records how they were matched as
+// one of the store.USER_MATCH_* methods.
+func (c *Client) PairUsers(ctx context.Context, fhUserID string, extUserID string, method string) error {
+	q := store.UseQueries(ctx)
+	if err := q.LinkExtUser(ctx, store.LinkExtUserParams{
 		FhUserID: sql.NullString{Valid: true, String: fhUserID},
 		ID:       extUserID,
-	})
+	}); err != nil {
+		return err
+	}
+	return q.InsertExtUserMatch(ctx, store.InsertExtUserMatchParams{UserID: extUserID, Method: method})
+}
+
+// UnpairUser unlinks the provider user from their FireHydrant user, leaving them unmatched.
+func (c *Client) UnpairUser(ctx context.Context, extUserID string) error {
+	q := store.UseQueries(ctx)
+	if err := q.LinkExtUser(ctx, store.LinkExtUserParams{ID: extUserID}); err != nil {
+		return err
+	}
+	return q.DeleteExtUserMatch(ctx, extUserID)
 }
 
 type SCIMUser interface {
@@ -24,4 +24,20 @@ func TestFireHydrantClient(t *testing.T) {
 		}
 		testkit.GoldenJSON(t, teams)
 	})
+
+	t.Run("ListOnCallSchedules", func(t *testing.T) {
+		schedules, err := client.ListOnCallSchedules(ctx, "47016143-6547-483a-b68a-5220b21681fd")
+		if err != nil {
+			t.Fatalf("error listing on-call schedules: %s", err)
+		}
+		testkit.GoldenJSON(t, schedules)
+	})
+
+	t.Run("ListEscalationPolicies", func(t *testing.T) {
+		policies, err := client.ListEscalationPolicies(ctx, "47016143-6547-483a-b68a-5220b21681fd")
+		if err != nil {
+			t.Fatalf("error listing escalation policies: %s", err)
+		}
+		testkit.GoldenJSON(t, policies)
+	})
 }
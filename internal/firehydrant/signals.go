@@ -0,0 +1,141 @@
+package firehydrant
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/firehydrant/firehydrant-go-sdk/models/components"
+	"github.com/firehydrant/firehydrant-go-sdk/models/operations"
+)
+
+// Resource is an existing resource of a FireHydrant team, which imported resources are matched
+// against by name.
+type Resource struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// OnCallSchedule is an existing on-call schedule of a FireHydrant team.
+type OnCallSchedule struct {
+	Resource
+	Rotations []Resource `json:"rotations"`
+}
+
+// CreateTeam creates a team in FireHydrant and returns its ID.
+func (c *Client) CreateTeam(ctx context.Context, team components.CreateTeam) (string, error) {
+	resp, err := c.sdk.Teams.CreateTeam(ctx, team)
+	if err != nil {
+		return "", fmt.Errorf("creating team '%s': %w", team.Name, err)
+	}
+	return responseID(resp.GetID())
+}
+
+// CreateOnCallSchedule creates an on-call schedule, along with its rotations, in a FireHydrant team
+// and returns the IDs of the schedule and of its rotations.
+func (c *Client) CreateOnCallSchedule(ctx context.Context, teamID string, schedule components.CreateTeamOnCallSchedule) (string, []string, error) {
+	resp, err := c.sdk.Signals.CreateTeamOnCallSchedule(ctx, teamID, schedule)
+	if err != nil {
+		return "", nil, fmt.Errorf("creating on-call schedule '%s': %w", schedule.Name, err)
+	}
+	id, err := responseID(resp.GetID())
+	if err != nil {
+		return "", nil, err
+	}
+	rotationIDs := []string{}
+	for _, r := range resp.GetRotations() {
+		rotationID, err := responseID(r.GetID())
+		if err != nil {
+			return "", nil, fmt.Errorf("reading rotations of on-call schedule '%s': %w", schedule.Name, err)
+		}
+		rotationIDs = append(rotationIDs, rotationID)
+	}
+	return id, rotationIDs, nil
+}
+
+// CreateRotation adds a rotation to an existing on-call schedule and returns its ID.
+func (c *Client) CreateRotation(ctx context.Context, teamID string, scheduleID string, rotation components.CreateOnCallScheduleRotation) (string, error) {
+	resp, err := c.sdk.Signals.CreateOnCallScheduleRotation(ctx, teamID, scheduleID, rotation)
+	if err != nil {
+		return "", fmt.Errorf("creating rotation '%s': %w", rotation.Name, err)
+	}
+	return responseID(resp.GetID())
+}
+
+// CreateOverride assigns a period of an on-call rotation to a user and returns the ID of the
+// resulting shift.
+func (c *Client) CreateOverride(ctx context.Context, teamID string, scheduleID string, rotationID string, override components.OverrideOnCallScheduleRotationShifts) (string, error) {
+	resp, err := c.sdk.Signals.OverrideOnCallScheduleRotationShifts(ctx, rotationID, teamID, scheduleID, override)
+	if err != nil {
+		return "", fmt.Errorf("creating override from %s to %s: %w", override.StartTime, override.EndTime, err)
+	}
+	return responseID(resp.GetID())
+}
+
+// CreateEscalationPolicy creates an escalation policy in a FireHydrant team and returns its ID.
+func (c *Client) CreateEscalationPolicy(ctx context.Context, teamID string, policy components.CreateTeamEscalationPolicy) (string, error) {
+	resp, err := c.sdk.Signals.CreateTeamEscalationPolicy(ctx, teamID, policy)
+	if err != nil {
+		return "", fmt.Errorf("creating escalation policy '%s': %w", policy.Name, err)
+	}
+	return responseID(resp.GetID())
+}
+
+// ListOnCallSchedules returns the on-call schedules of a FireHydrant team, along with their rotations.
+func (c *Client) ListOnCallSchedules(ctx context.Context, teamID string) ([]OnCallSchedule, error) {
+	schedules := []OnCallSchedule{}
+	page := 1
+	for {
+		resp, err := c.sdk.Signals.ListTeamOnCallSchedules(ctx, operations.ListTeamOnCallSchedulesRequest{TeamID: teamID, Page: &page})
+		if err != nil {
+			return nil, fmt.Errorf("fetching on-call schedules of team '%s': %w", teamID, err)
+		}
+		for _, s := range resp.GetData() {
+			schedule := OnCallSchedule{Resource: Resource{ID: valueOf(s.GetID()), Name: valueOf(s.GetName())}}
+			for _, r := range s.GetRotations() {
+				schedule.Rotations = append(schedule.Rotations, Resource{ID: valueOf(r.GetID()), Name: valueOf(r.GetName())})
+			}
+			schedules = append(schedules, schedule)
+		}
+		pg := resp.GetPagination()
+		if pg == nil || pg.GetNext() == nil || *pg.GetNext() == 0 {
+			break
+		}
+		page = *pg.GetNext()
+	}
+	return schedules, nil
+}
+
+// ListEscalationPolicies returns the escalation policies of a FireHydrant team.
+func (c *Client) ListEscalationPolicies(ctx context.Context, teamID string) ([]Resource, error) {
+	policies := []Resource{}
+	page := 1
+	for {
+		resp, err := c.sdk.Signals.ListTeamEscalationPolicies(ctx, teamID, nil, &page, nil)
+		if err != nil {
+			return nil, fmt.Errorf("fetching escalation policies of team '%s': %w", teamID, err)
+		}
+		for _, p := range resp.GetData() {
+			policies = append(policies, Resource{ID: valueOf(p.GetID()), Name: valueOf(p.GetName())})
+		}
+		pg := resp.GetPagination()
+		if pg == nil || pg.GetNext() == nil || *pg.GetNext() == 0 {
+			break
+		}
+		page = *pg.GetNext()
+	}
+	return policies, nil
+}
+
+func valueOf(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func responseID(id *string) (string, error) {
+	if id == nil || *id == "" {
+		return "", fmt.Errorf("response does not include an ID")
+	}
+	return *id, nil
+}
@@ -0,0 +1,182 @@
+package firehydrant
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/firehydrant/signals-migrator/diagnostics"
+	"github.com/firehydrant/signals-migrator/store"
+)
+
+// UserMapping links a provider user to a FireHydrant user, each given by either their ID or email.
+type UserMapping struct {
+	ExtUser string
+	FhUser  string
+}
+
+// Columns of a user map with a header row, which are those written by the export-users command.
+// IDs are used over emails when both are given.
+var (
+	userMapExtColumns = []string{"ext_user_id", "ext_email"}
+	userMapFhColumns  = []string{"fh_user_id", "fh_email"}
+)
+
+// ReadUserMap reads user mappings from CSV. Without a header row naming the columns, the first
+// column is the provider user and the second the FireHydrant user. Rows without a FireHydrant user
+// are left out, so that users left unmatched in an export stay unmatched.
+func ReadUserMap(r io.Reader) ([]UserMapping, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	reader.Comment = '#'
+
+	mappings := []UserMapping{}
+	extColumns, fhColumns := []int{0}, []int{1}
+	for first := true; ; first = false {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return mappings, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading user map: %w", err)
+		}
+		line, _ := reader.FieldPos(0)
+
+		if first {
+			header := make([]string, len(record))
+			for i, name := range record {
+				header[i] = strings.ToLower(strings.TrimSpace(name))
+			}
+			if named := namedColumns(header, userMapExtColumns); len(named) > 0 {
+				extColumns, fhColumns = named, namedColumns(header, userMapFhColumns)
+				if len(fhColumns) == 0 {
+					return nil, fmt.Errorf("reading user map: header on line %d has no %s column", line, strings.Join(userMapFhColumns, " or "))
+				}
+				continue
+			}
+		}
+
+		m := UserMapping{ExtUser: firstValue(record, extColumns), FhUser: firstValue(record, fhColumns)}
+		if m.ExtUser == "" {
+			return nil, fmt.Errorf("reading user map: line %d has no provider user", line)
+		}
+		if m.FhUser != "" {
+			mappings = append(mappings, m)
+		}
+	}
+}
+
+func namedColumns(header []string, names []string) []int {
+	columns := []int{}
+	for _, name := range names {
+		if i := slices.Index(header, name); i >= 0 {
+			columns = append(columns, i)
+		}
+	}
+	return columns
+}
+
+func firstValue(record []string, columns []int) string {
+	for _, i := range columns {
+		if i < len(record) {
+			if v := strings.TrimSpace(record[i]); v != "" {
+				return v
+			}
+		}
+	}
+	return ""
+}
+
+// ApplyUserMap links the provider users to the FireHydrant users given by the mappings, replacing
+// whatever they were matched to before. It returns how many users were linked. Mappings of users
+// who are not in the provider are noted in diagnostics, as a user map commonly covers the whole
+// organization, and those to FireHydrant users who do not exist are warned about. A FireHydrant
+// user is linked to one provider user only: a mapping takes the FireHydrant user from a provider
+// user who was only suggested for them, while mappings to a FireHydrant user who is already linked
+// to another provider user by email or by an earlier row are warned about and left out.
+func (c *Client) ApplyUserMap(ctx context.Context, mappings []UserMapping) (int, error) {
+	if len(mappings) == 0 {
+		return 0, nil
+	}
+	fhUsers, err := c.ListUsers(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("fetching FireHydrant users: %w", err)
+	}
+	extUsers, err := store.UseQueries(ctx).ListExtUsers(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("listing external users: %w", err)
+	}
+	matches, err := store.UseQueries(ctx).ListUserMatches(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("listing user matches: %w", err)
+	}
+	methods := map[string]string{}
+	for _, m := range matches {
+		methods[m.ID] = m.Method.String
+	}
+
+	// paired maps each linked FireHydrant user to the provider user they are linked to.
+	paired := map[string]string{}
+	for _, u := range extUsers {
+		if u.FhUserID.Valid {
+			paired[u.FhUserID.String] = u.ID
+		}
+	}
+
+	linked := 0
+	for _, m := range mappings {
+		i := slices.IndexFunc(fhUsers, func(u store.FhUser) bool {
+			return u.ID == m.FhUser || strings.EqualFold(u.Email, m.FhUser)
+		})
+		if i < 0 {
+			diagnostics.Warnf(ctx, diagnostics.ResourceUser, m.ExtUser, "User map links %s to FireHydrant user %s, who does not exist.\n", m.ExtUser, m.FhUser)
+			continue
+		}
+		fhUser := fhUsers[i]
+
+		found := false
+		for _, u := range extUsers {
+			if u.ID != m.ExtUser && !strings.EqualFold(u.Email, m.ExtUser) {
+				continue
+			}
+			found = true
+			if other, ok := paired[fhUser.ID]; ok && other != u.ID {
+				if methods[other] != store.USER_MATCH_SUGGESTED {
+					diagnostics.Warnf(ctx, diagnostics.ResourceUser, u.ID, "User map links %s to FireHydrant user %s, who is already linked to %s. The mapping is left out.\n", u.Email, fhUser.Email, other)
+					continue
+				}
+				if err := c.UnpairUser(ctx, other); err != nil {
+					return linked, fmt.Errorf("unpairing users: %w", err)
+				}
+				delete(paired, fhUser.ID)
+				delete(methods, other)
+				diagnostics.Infof(ctx, diagnostics.ResourceUser, other, "User map links %s to FireHydrant user %s, who is no longer suggested for %s.\n", u.Email, fhUser.Email, other)
+			}
+			current := ""
+			for fhID, extID := range paired {
+				if extID == u.ID {
+					current = fhID
+				}
+			}
+			if current != "" && current != fhUser.ID {
+				diagnostics.Infof(ctx, diagnostics.ResourceUser, u.ID, "User map links %s to FireHydrant user %s instead of the one they were matched to.\n", u.Email, fhUser.Email)
+				delete(paired, current)
+			}
+			if err := c.PairUsers(ctx, fhUser.ID, u.ID, store.USER_MATCH_USER_MAP); err != nil {
+				return linked, fmt.Errorf("pairing users: %w", err)
+			}
+			paired[fhUser.ID] = u.ID
+			methods[u.ID] = store.USER_MATCH_USER_MAP
+			linked++
+		}
+		if !found {
+			diagnostics.Infof(ctx, diagnostics.ResourceUser, m.ExtUser, "User map links %s, who is not a user in the provider.\n", m.ExtUser)
+		}
+	}
+	return linked, nil
+}
@@ -0,0 +1,186 @@
+package firehydrant_test
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/firehydrant/signals-migrator/diagnostics"
+	"github.com/firehydrant/signals-migrator/internal/firehydrant"
+	"github.com/firehydrant/signals-migrator/internal/testkit"
+	"github.com/firehydrant/signals-migrator/store"
+)
+
+func TestReadUserMap(t *testing.T) {
+	t.Run("Positional", func(t *testing.T) {
+		mappings, err := firehydrant.ReadUserMap(strings.NewReader("# HR export\nPABC123, jane@example.com\nold@example.com,fh-john\nunknown@example.com,\n"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := []firehydrant.UserMapping{
+			{ExtUser: "PABC123", FhUser: "jane@example.com"},
+			{ExtUser: "old@example.com", FhUser: "fh-john"},
+		}
+		if !reflect.DeepEqual(mappings, expected) {
+			t.Errorf("expected %+v, got %+v", expected, mappings)
+		}
+	})
+
+	t.Run("Exported", func(t *testing.T) {
+		csv := "ext_user_id,ext_email,fh_user_id,fh_email,match_method\n" +
+			"PABC123,jane@example.com,,jane@example.com,\n" +
+			",john@example.com,fh-john,john@example.com,email\n" +
+			"PDEF456,unmatched@example.com,,,\n"
+		mappings, err := firehydrant.ReadUserMap(strings.NewReader(csv))
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := []firehydrant.UserMapping{
+			{ExtUser: "PABC123", FhUser: "jane@example.com"},
+			{ExtUser: "john@example.com", FhUser: "fh-john"},
+		}
+		if !reflect.DeepEqual(mappings, expected) {
+			t.Errorf("expected %+v, got %+v", expected, mappings)
+		}
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		for _, csv := range []string{
+			"ext_email,email\njane@example.com,jane@example.com\n",
+			"PABC123,jane@example.com\n,john@example.com\n",
+			"PABC123,\"jane@example.com\n",
+		} {
+			if _, err := firehydrant.ReadUserMap(strings.NewReader(csv)); err == nil {
+				t.Errorf("expected an error for %q", csv)
+			}
+		}
+	})
+}
+
+func TestApplyUserMap(t *testing.T) {
+	ctx := diagnostics.WithCollector(testkit.NewStore(t, context.Background()))
+	ts := testkit.NewHTTPServer(t)
+	client, err := firehydrant.NewClient("testing-only", ts.URL)
+	if err != nil {
+		t.Fatalf("error creating FireHydrant client: %s", err)
+	}
+
+	q := store.UseQueries(ctx)
+	for _, u := range []store.FhUser{
+		{ID: "fh-jane", Name: "Jane Doe", Email: "jane.doe@example.com"},
+		{ID: "fh-john", Name: "John Smith", Email: "john.smith@example.com"},
+	} {
+		if err := q.InsertFhUser(ctx, store.InsertFhUserParams(u)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, u := range []store.ExtUser{
+		{ID: "PJANE", Name: "Jane Doe", Email: "jdoe@legacy.example.com"},
+		{ID: "PJOHN", Name: "John Smith", Email: "john.smith@example.com", FhUserID: sql.NullString{Valid: true, String: "fh-jane"}},
+		{ID: "PJANE2", Name: "Jane D", Email: "jane@legacy.example.com"},
+	} {
+		if err := q.InsertExtUser(ctx, store.InsertExtUserParams{ID: u.ID, Name: u.Name, Email: u.Email, FhUserID: u.FhUserID}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// John is linked to Jane's FireHydrant user until the first row frees it for her.
+	linked, err := client.ApplyUserMap(ctx, []firehydrant.UserMapping{
+		{ExtUser: "john.smith@example.com", FhUser: "fh-john"},
+		{ExtUser: "PJANE", FhUser: "Jane.Doe@example.com"},
+		{ExtUser: "PNOBODY", FhUser: "fh-john"},
+		{ExtUser: "PJANE", FhUser: "missing@example.com"},
+		{ExtUser: "PJANE2", FhUser: "fh-jane"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if linked != 2 {
+		t.Errorf("expected 2 users to be linked, got %d", linked)
+	}
+
+	matches, err := q.ListUserMatches(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[string][2]string{}
+	for _, m := range matches {
+		got[m.ID] = [2]string{m.FhUserID.String, m.Method.String}
+	}
+	expected := map[string][2]string{
+		"PJANE":  {"fh-jane", store.USER_MATCH_USER_MAP},
+		"PJOHN":  {"fh-john", store.USER_MATCH_USER_MAP},
+		"PJANE2": {"", ""},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+
+	entries := diagnostics.FromContext(ctx).Entries()
+	if len(entries) != 4 {
+		t.Fatalf("expected 4 diagnostics, got %+v", entries)
+	}
+	if entries[2].Severity != diagnostics.SeverityWarning || !strings.Contains(entries[2].Message, "missing@example.com") {
+		t.Errorf("expected a warning about the missing FireHydrant user, got %+v", entries[2])
+	}
+	if entries[3].Severity != diagnostics.SeverityWarning || entries[3].SourceID != "PJANE2" || !strings.Contains(entries[3].Message, "already linked to PJANE") {
+		t.Errorf("expected a warning about Jane's FireHydrant user being linked twice, got %+v", entries[3])
+	}
+}
+
+func TestApplyUserMapReplacesSuggestedMatch(t *testing.T) {
+	ctx := diagnostics.WithCollector(testkit.NewStore(t, context.Background()))
+	ts := testkit.NewHTTPServer(t)
+	client, err := firehydrant.NewClient("testing-only", ts.URL)
+	if err != nil {
+		t.Fatalf("error creating FireHydrant client: %s", err)
+	}
+
+	q := store.UseQueries(ctx)
+	if err := q.InsertFhUser(ctx, store.InsertFhUserParams{ID: "fh-jane", Name: "Jane Doe", Email: "jane.doe@example.com"}); err != nil {
+		t.Fatal(err)
+	}
+	for _, u := range []store.ExtUser{
+		{ID: "PJANE", Name: "Jane Doe", Email: "jdoe@legacy.example.com"},
+		{ID: "PJDOE", Name: "Jane Doe", Email: "jane.doe@old.example.com"},
+	} {
+		if err := q.InsertExtUser(ctx, store.InsertExtUserParams{ID: u.ID, Name: u.Name, Email: u.Email}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// The matcher was confident enough about the wrong Jane, by name and email.
+	if err := client.PairUsers(ctx, "fh-jane", "PJDOE", store.USER_MATCH_SUGGESTED); err != nil {
+		t.Fatal(err)
+	}
+
+	linked, err := client.ApplyUserMap(ctx, []firehydrant.UserMapping{{ExtUser: "PJANE", FhUser: "fh-jane"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if linked != 1 {
+		t.Errorf("expected 1 user to be linked, got %d", linked)
+	}
+
+	matches, err := q.ListUserMatches(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[string][2]string{}
+	for _, m := range matches {
+		got[m.ID] = [2]string{m.FhUserID.String, m.Method.String}
+	}
+	expected := map[string][2]string{
+		"PJANE": {"fh-jane", store.USER_MATCH_USER_MAP},
+		"PJDOE": {"", ""},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+
+	entries := diagnostics.FromContext(ctx).Entries()
+	if len(entries) != 1 || entries[0].Severity != diagnostics.SeverityInfo || entries[0].SourceID != "PJDOE" {
+		t.Errorf("expected a note about the suggestion being replaced, got %+v", entries)
+	}
+}
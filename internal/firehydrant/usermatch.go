@@ -0,0 +1,229 @@
+package firehydrant
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/firehydrant/signals-migrator/store"
+)
+
+// Strategies to match provider users whose email has no exact counterpart in FireHydrant.
+const (
+	// MatchDomainAlias replaces the domain of the email with its alias, e.g. after the company moved
+	// to a new domain.
+	MatchDomainAlias = "domain-alias"
+	// MatchLocalPart compares the part of the emails before the domain, regardless of case, dots,
+	// dashes, underscores and "+" tags, and matches abbreviations such as "flast" for "first.last".
+	MatchLocalPart = "local-part"
+	// MatchName compares the display names.
+	MatchName = "name"
+)
+
+// MatchStrategies are all the strategies to match users, which are used by default.
+var MatchStrategies = []string{MatchDomainAlias, MatchLocalPart, MatchName}
+
+// UserMatcher suggests FireHydrant users for the provider users whose email has no exact match.
+type UserMatcher struct {
+	Strategies []string
+	// DomainAliases maps the email domains used in the provider to those used in FireHydrant, e.g.
+	// "old-domain.com" to "new-domain.com".
+	DomainAliases map[string]string
+	// Threshold is the confidence from which the best suggestion is linked without asking.
+	Threshold float64
+}
+
+// UserSuggestion is a FireHydrant user who may be the same person as a provider user.
+type UserSuggestion struct {
+	User store.FhUser
+	// Confidence is between 0 and 1, where 1 is certain.
+	Confidence float64
+	Reasons    []string
+}
+
+// ParseDomainAliases parses domain aliases written as "old-domain.com=new-domain.com".
+func ParseDomainAliases(aliases []string) (map[string]string, error) {
+	parsed := map[string]string{}
+	for _, a := range aliases {
+		from, to, ok := strings.Cut(a, "=")
+		from, to = strings.ToLower(strings.TrimSpace(from)), strings.ToLower(strings.TrimSpace(to))
+		if !ok || from == "" || to == "" {
+			return nil, fmt.Errorf("invalid domain alias '%s', expected 'old-domain.com=new-domain.com'", a)
+		}
+		parsed[from] = to
+	}
+	return parsed, nil
+}
+
+// Suggest ranks the candidates who may be the same person as u, most likely first. Each strategy
+// which matches is taken as independent evidence, so a candidate matching several is more likely
+// than one matching any of them alone. Candidates which match none are left out.
+func (m UserMatcher) Suggest(u store.ExtUser, candidates []store.FhUser) []UserSuggestion {
+	suggestions := []UserSuggestion{}
+	for _, c := range candidates {
+		s := UserSuggestion{User: c}
+		unlikely := 1.0
+		add := func(confidence float64, reason string) {
+			unlikely *= 1 - confidence
+			s.Reasons = append(s.Reasons, reason)
+		}
+		if slices.Contains(m.Strategies, MatchDomainAlias) {
+			if confidence, reason := m.matchDomainAlias(u.Email, c.Email); confidence > 0 {
+				add(confidence, reason)
+			}
+		}
+		if slices.Contains(m.Strategies, MatchLocalPart) {
+			if confidence, reason := m.matchLocalPart(u, c); confidence > 0 {
+				add(confidence, reason)
+			}
+		}
+		if slices.Contains(m.Strategies, MatchName) {
+			if confidence, reason := matchName(u.Name, c.Name); confidence > 0 {
+				add(confidence, reason)
+			}
+		}
+		if len(s.Reasons) > 0 {
+			s.Confidence = 1 - unlikely
+			suggestions = append(suggestions, s)
+		}
+	}
+	slices.SortStableFunc(suggestions, func(a, b UserSuggestion) int {
+		switch {
+		case a.Confidence > b.Confidence:
+			return -1
+		case a.Confidence < b.Confidence:
+			return 1
+		}
+		return 0
+	})
+	return suggestions
+}
+
+// Best returns the suggestion to link without asking: the most likely one, as long as it reaches
+// the threshold and no other suggestion is as likely.
+func (m UserMatcher) Best(suggestions []UserSuggestion) (UserSuggestion, bool) {
+	if len(suggestions) == 0 || suggestions[0].Confidence < m.Threshold {
+		return UserSuggestion{}, false
+	}
+	if len(suggestions) > 1 && suggestions[1].Confidence == suggestions[0].Confidence {
+		return UserSuggestion{}, false
+	}
+	return suggestions[0], true
+}
+
+func (m UserMatcher) matchDomainAlias(email string, candidate string) (float64, string) {
+	local, domain, ok := strings.Cut(strings.ToLower(email), "@")
+	if !ok {
+		return 0, ""
+	}
+	alias, ok := m.DomainAliases[domain]
+	if ok && strings.EqualFold(local+"@"+alias, candidate) {
+		return 1, "same email at " + alias
+	}
+	return 0, ""
+}
+
+func (m UserMatcher) matchLocalPart(u store.ExtUser, c store.FhUser) (float64, string) {
+	local, domain, ok := strings.Cut(strings.ToLower(u.Email), "@")
+	cLocal, cDomain, cOk := strings.Cut(strings.ToLower(c.Email), "@")
+	if !ok || !cOk {
+		return 0, ""
+	}
+	sameDomain := domain == cDomain || m.DomainAliases[domain] == cDomain
+
+	tokens, cTokens := localPartTokens(local), localPartTokens(cLocal)
+	if len(tokens) == 0 || len(cTokens) == 0 {
+		return 0, ""
+	}
+	joined, cJoined := strings.Join(tokens, ""), strings.Join(cTokens, "")
+	if joined == cJoined {
+		if sameDomain {
+			return 0.9, "same email apart from punctuation"
+		}
+		return 0.7, "same email name at another domain"
+	}
+
+	// Either email may be the abbreviation of the other, or of the person's name.
+	abbreviated := slices.Contains(abbreviations(cTokens), joined) ||
+		slices.Contains(abbreviations(nameTokens(c.Name)), joined) ||
+		slices.Contains(abbreviations(tokens), cJoined) ||
+		slices.Contains(abbreviations(nameTokens(u.Name)), cJoined)
+	if abbreviated {
+		if sameDomain {
+			return 0.75, "abbreviated email name"
+		}
+		return 0.6, "abbreviated email name at another domain"
+	}
+	return 0, ""
+}
+
+// matchName compares names regardless of case, punctuation and the order of their parts, so that
+// "Doe, Jane" is the same as "Jane Doe". Names which are only slightly alike are not suggested.
+func matchName(name string, candidate string) (float64, string) {
+	a, b := nameTokens(name), nameTokens(candidate)
+	if len(a) == 0 || len(b) == 0 {
+		return 0, ""
+	}
+	slices.Sort(a)
+	slices.Sort(b)
+	similarity := stringSimilarity(strings.Join(a, " "), strings.Join(b, " "))
+	switch {
+	case similarity == 1:
+		return 0.8, "same name"
+	case similarity >= 0.75:
+		return 0.8 * similarity, "similar name"
+	}
+	return 0, ""
+}
+
+// localPartTokens splits the local part of an email into its words, without any "+" tag.
+func localPartTokens(local string) []string {
+	local, _, _ = strings.Cut(local, "+")
+	return strings.FieldsFunc(local, func(r rune) bool { return r == '.' || r == '_' || r == '-' })
+}
+
+func nameTokens(name string) []string {
+	return strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// abbreviations returns the common ways to abbreviate a name in an email, e.g. "flast", "firstl"
+// and "lastf" for "first last".
+func abbreviations(tokens []string) []string {
+	if len(tokens) < 2 {
+		return nil
+	}
+	first, last := []rune(tokens[0]), []rune(tokens[len(tokens)-1])
+	return []string{
+		string(first[:1]) + string(last),
+		string(first) + string(last[:1]),
+		string(last) + string(first[:1]),
+	}
+}
+
+// stringSimilarity is one minus the edit distance between the strings, relative to the longest.
+func stringSimilarity(a string, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	if len(ra) == 0 && len(rb) == 0 {
+		return 1
+	}
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return 1 - float64(prev[len(rb)])/float64(max(len(ra), len(rb)))
+}
@@ -0,0 +1,105 @@
+package firehydrant_test
+
+import (
+	"testing"
+
+	"github.com/firehydrant/signals-migrator/internal/firehydrant"
+	"github.com/firehydrant/signals-migrator/store"
+)
+
+func TestUserMatcher(t *testing.T) {
+	candidates := []store.FhUser{
+		{ID: "fh-jane", Name: "Jane Doe", Email: "jane.doe@new.example.com"},
+		{ID: "fh-jdoe", Name: "Jane Doe", Email: "jdoe@other.example.com"},
+		{ID: "fh-john", Name: "John Smith", Email: "john.smith@example.com"},
+		{ID: "fh-jon", Name: "Jon Smith", Email: "jon@elsewhere.example.com"},
+	}
+	matcher := firehydrant.UserMatcher{
+		Strategies:    firehydrant.MatchStrategies,
+		DomainAliases: map[string]string{"old.example.com": "new.example.com"},
+		Threshold:     0.9,
+	}
+
+	t.Run("DomainAlias", func(t *testing.T) {
+		u := store.ExtUser{ID: "ext-jane", Name: "Jane Doe", Email: "Jane.Doe@old.example.com"}
+		suggestions := matcher.Suggest(u, candidates)
+		if len(suggestions) != 2 {
+			t.Fatalf("expected 2 suggestions, got %+v", suggestions)
+		}
+		if suggestions[0].User.ID != "fh-jane" || suggestions[0].Confidence != 1 {
+			t.Errorf("expected fh-jane to be certain, got %+v", suggestions[0])
+		}
+		if suggestions[1].User.ID != "fh-jdoe" {
+			t.Errorf("expected fh-jdoe to be suggested next, got %+v", suggestions[1])
+		}
+		best, ok := matcher.Best(suggestions)
+		if !ok || best.User.ID != "fh-jane" {
+			t.Errorf("expected fh-jane to be linked, got %+v", best)
+		}
+	})
+
+	t.Run("AbbreviatedEmailAtAnotherDomain", func(t *testing.T) {
+		u := store.ExtUser{ID: "ext-jsmith", Name: "John Smith", Email: "jsmith@example.org"}
+		suggestions := matcher.Suggest(u, candidates)
+		if len(suggestions) == 0 || suggestions[0].User.ID != "fh-john" {
+			t.Fatalf("expected fh-john to be suggested first, got %+v", suggestions)
+		}
+		if suggestions[0].Confidence < matcher.Threshold {
+			t.Errorf("expected abbreviated email and same name to reach the threshold, got %+v", suggestions[0])
+		}
+	})
+
+	t.Run("NameOnly", func(t *testing.T) {
+		u := store.ExtUser{ID: "ext-smith", Name: "Smith, John", Email: "oncall-7@example.net"}
+		suggestions := matcher.Suggest(u, candidates)
+		if len(suggestions) != 2 || suggestions[0].User.ID != "fh-john" || suggestions[1].User.ID != "fh-jon" {
+			t.Fatalf("expected fh-john then fh-jon, got %+v", suggestions)
+		}
+		if _, ok := matcher.Best(suggestions); ok {
+			t.Errorf("expected a name alone not to be linked, got %+v", suggestions[0])
+		}
+	})
+
+	t.Run("Ambiguous", func(t *testing.T) {
+		u := store.ExtUser{ID: "ext-smith", Name: "John Smith", Email: "oncall-7@example.net"}
+		twins := []store.FhUser{
+			{ID: "fh-john-1", Name: "John Smith", Email: "john@one.example.com"},
+			{ID: "fh-john-2", Name: "John Smith", Email: "john@two.example.com"},
+		}
+		lenient := matcher
+		lenient.Threshold = 0.5
+		suggestions := lenient.Suggest(u, twins)
+		if len(suggestions) < 2 || suggestions[0].Confidence != suggestions[1].Confidence {
+			t.Fatalf("expected two equally likely suggestions, got %+v", suggestions)
+		}
+		if best, ok := lenient.Best(suggestions); ok {
+			t.Errorf("expected no user to be linked, got %+v", best)
+		}
+	})
+
+	t.Run("Strategies", func(t *testing.T) {
+		u := store.ExtUser{ID: "ext-jane", Name: "Jane Doe", Email: "jane.doe@old.example.com"}
+		nameOnly := firehydrant.UserMatcher{Strategies: []string{firehydrant.MatchName}}
+		for _, s := range nameOnly.Suggest(u, candidates) {
+			if len(s.Reasons) != 1 || s.Reasons[0] != "same name" {
+				t.Errorf("expected only names to be compared, got %+v", s)
+			}
+		}
+	})
+}
+
+func TestParseDomainAliases(t *testing.T) {
+	aliases, err := firehydrant.ParseDomainAliases([]string{"Old.example.com = new.example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if aliases["old.example.com"] != "new.example.com" {
+		t.Errorf("unexpected aliases: %v", aliases)
+	}
+
+	for _, invalid := range []string{"old.example.com", "=new.example.com", "old.example.com="} {
+		if _, err := firehydrant.ParseDomainAliases([]string{invalid}); err == nil {
+			t.Errorf("expected '%s' to be invalid", invalid)
+		}
+	}
+}
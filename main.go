@@ -18,6 +18,8 @@ func main() {
 
 	app.Commands = []*cli.Command{
 		cmd.ImportCommand,
+		cmd.SimulateCommand,
+		cmd.ExportUsersCommand,
 		{
 			Name:  "version",
 			Usage: "Print the version",
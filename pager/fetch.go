@@ -0,0 +1,241 @@
+package pager
+
+import (
+	"context"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// DefaultConcurrency is how many requests a provider has in flight at once while loading, unless
+// configured otherwise with SetConcurrency.
+const DefaultConcurrency = 8
+
+// ConcurrentLoader is implemented by providers which fetch the details of teams and schedules
+// concurrently, with at most the given number of requests in flight at once.
+type ConcurrentLoader interface {
+	SetConcurrency(n int)
+}
+
+const (
+	maxRetries = 5
+	minBackoff = time.Second
+	maxBackoff = time.Minute
+)
+
+// rateLimitedTransport is the shared fetch layer of the providers. It keeps the requests of a
+// provider within its rate limit, holds them all back when the provider reports that the limit is
+// reached, and retries those which are rate limited or fail on the server side with exponential
+// backoff.
+type rateLimitedTransport struct {
+	transport http.RoundTripper
+
+	// interval is the time between requests at the sustained rate, and burst is how far ahead of
+	// that rate requests may start after a quiet period.
+	interval time.Duration
+	burst    time.Duration
+
+	mu sync.Mutex
+	// tat is the theoretical arrival time of the next request at the sustained rate.
+	tat time.Time
+	// pausedUntil is when the provider accepts requests again, after reporting that its limit is
+	// reached.
+	pausedUntil time.Time
+}
+
+// newRateLimitedTransport returns a transport which starts at most limit requests per second.
+func newRateLimitedTransport(limit int) *rateLimitedTransport {
+	interval := time.Second / time.Duration(limit)
+	return &rateLimitedTransport{
+		transport: http.DefaultTransport,
+		interval:  interval,
+		burst:     interval * time.Duration(limit-1),
+	}
+}
+
+func (t *rateLimitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	for attempt := 0; ; attempt++ {
+		if err := sleep(ctx, t.reserve(time.Now())); err != nil {
+			return nil, err
+		}
+		r := req
+		if attempt > 0 {
+			r = req.Clone(ctx)
+			if req.Body != nil && req.Body != http.NoBody {
+				body, err := req.GetBody()
+				if err != nil {
+					return nil, err
+				}
+				r.Body = body
+			}
+		}
+		resp, err := t.transport.RoundTrip(r)
+		if err != nil {
+			return nil, err
+		}
+
+		wait, limited := rateLimitWait(resp.Header, time.Now())
+		if limited {
+			t.pause(time.Now().Add(wait))
+		}
+		retryable := resp.StatusCode == http.StatusTooManyRequests ||
+			(resp.StatusCode >= 500 && resp.StatusCode != http.StatusNotImplemented)
+		rewindable := req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+		if !retryable || !rewindable || attempt >= maxRetries {
+			return resp, nil
+		}
+		if !limited {
+			wait = backoff(attempt)
+		}
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+		if err := sleep(ctx, wait); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// reserve returns how long to wait before a request may start.
+func (t *rateLimitedTransport) reserve(now time.Time) time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	start := now
+	if ahead := t.tat.Add(-t.burst); ahead.After(start) {
+		start = ahead
+	}
+	if t.pausedUntil.After(start) {
+		start = t.pausedUntil
+	}
+	if t.tat.Before(start) {
+		t.tat = start
+	}
+	t.tat = t.tat.Add(t.interval)
+	return start.Sub(now)
+}
+
+// pause holds back every request until the given time.
+func (t *rateLimitedTransport) pause(until time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if until.After(t.pausedUntil) {
+		t.pausedUntil = until
+	}
+}
+
+// rateLimitWait returns how long to wait before the next request, when the response headers report
+// that the rate limit is reached. Retry-After is sent along with 429 and 503 responses, while the
+// X-RateLimit-* headers and their unprefixed RateLimit-* counterparts report the remaining
+// requests, and when the limit resets. Opsgenie reports its own X-RateLimit-State instead.
+func rateLimitWait(h http.Header, now time.Time) (time.Duration, bool) {
+	if v := h.Get("Retry-After"); v != "" {
+		if seconds, err := strconv.Atoi(v); err == nil {
+			return max(0, time.Duration(seconds)*time.Second), true
+		}
+		if at, err := http.ParseTime(v); err == nil {
+			return max(0, at.Sub(now)), true
+		}
+	}
+	if h.Get("X-RateLimit-State") == "THROTTLED" {
+		if seconds, err := strconv.Atoi(h.Get("X-RateLimit-Period-In-Sec")); err == nil {
+			return time.Duration(seconds) * time.Second, true
+		}
+		return minBackoff, true
+	}
+	for _, prefix := range []string{"X-RateLimit-", "RateLimit-"} {
+		if h.Get(prefix+"Remaining") != "0" {
+			continue
+		}
+		reset, err := strconv.ParseInt(h.Get(prefix+"Reset"), 10, 64)
+		if err != nil {
+			return minBackoff, true
+		}
+		// Some providers send the number of seconds until the reset, and others the Unix time of it.
+		if reset > now.Unix()/2 {
+			return max(0, time.Unix(reset, 0).Sub(now)), true
+		}
+		return time.Duration(reset) * time.Second, true
+	}
+	return 0, false
+}
+
+// backoff returns the time to wait before the given retry, doubling with each attempt, with jitter
+// so that concurrent requests do not retry in lockstep.
+func backoff(attempt int) time.Duration {
+	d := min(maxBackoff, minBackoff<<attempt)
+	return d/2 + rand.N(d/2+1)
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// fetchEach fetches every item with at most concurrency fetches running at once, and saves each
+// of them in order, once it and every item before it are fetched. Saves run one at a time on the
+// calling goroutine, so that store writes are never concurrent and what is imported does not
+// depend on the order responses arrive in. It stops at the first error.
+func fetchEach[T, R any](ctx context.Context, concurrency int, items []T, fetch func(context.Context, T) (R, error), save func(T, R) error) error {
+	type result struct {
+		value R
+		err   error
+	}
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([]chan result, len(items))
+	for i := range results {
+		results[i] = make(chan result, 1)
+	}
+	slots := make(chan struct{}, max(1, concurrency))
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i, item := range items {
+			select {
+			case slots <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer func() { <-slots }()
+				value, err := fetch(ctx, item)
+				results[i] <- result{value: value, err: err}
+			}()
+		}
+	}()
+
+	for i, item := range items {
+		var r result
+		select {
+		case r = <-results[i]:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		if r.err != nil {
+			return r.err
+		}
+		if err := save(item, r.value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
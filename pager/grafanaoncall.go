@@ -0,0 +1,943 @@
+package pager
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/firehydrant/signals-migrator/diagnostics"
+	"github.com/firehydrant/signals-migrator/store"
+	"github.com/gosimple/slug"
+)
+
+// GrafanaOnCall imports from the Grafana OnCall HTTP API. There is no Go SDK for it, so every
+// endpoint is requested directly.
+type GrafanaOnCall struct {
+	apiKey string
+	url    string
+	now    func() time.Time
+}
+
+// NewGrafanaOnCall returns a provider for the Grafana OnCall stack at apiURL, which is shown
+// along with the API tokens in the OnCall settings, e.g. https://oncall-prod-us-central-0.grafana.net/oncall.
+func NewGrafanaOnCall(apiKey string, apiURL string) *GrafanaOnCall {
+	return &GrafanaOnCall{
+		apiKey: apiKey,
+		url:    strings.TrimSuffix(apiURL, "/"),
+		now:    time.Now,
+	}
+}
+
+// SetNow overrides the clock used to skip ended shifts and past overrides, so tests can pin it.
+func (g *GrafanaOnCall) SetNow(now func() time.Time) {
+	g.now = now
+}
+
+func (g *GrafanaOnCall) Kind() string {
+	return "Grafana OnCall"
+}
+
+func (g *GrafanaOnCall) TeamInterfaces() []string {
+	return []string{"team"}
+}
+
+func (g *GrafanaOnCall) UseTeamInterface(string) error {
+	return nil
+}
+
+// Preflight requests the first page of users.
+func (g *GrafanaOnCall) Preflight(ctx context.Context) error {
+	body, err := g.do(ctx, g.url+"/api/v1/users/")
+	if err != nil {
+		return fmt.Errorf("querying grafana oncall at %s: %w", g.url, err)
+	}
+	return body.Close()
+}
+
+func (g *GrafanaOnCall) Teams(ctx context.Context) ([]store.ExtTeam, error) {
+	return store.UseQueries(ctx).ListExtTeams(ctx)
+}
+
+// do requests rawURL with the API token and returns the response body.
+func (g *GrafanaOnCall) do(ctx context.Context, rawURL string) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("composing request: %w", err)
+	}
+	req.Header.Set("Authorization", g.apiKey)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return nil, statusError(resp.StatusCode, body)
+	}
+	return resp.Body, nil
+}
+
+// grafanaList requests every page of a list endpoint of the Grafana OnCall API. Pages are requested
+// by number rather than by following the absolute "next" URL.
+func grafanaList[T any](ctx context.Context, g *GrafanaOnCall, endpoint string, query url.Values) ([]T, error) {
+	var results []T
+	for page := 1; ; page++ {
+		q := url.Values{}
+		for k, v := range query {
+			q[k] = v
+		}
+		if page > 1 {
+			q.Set("page", strconv.Itoa(page))
+		}
+		u := g.url + "/api/v1/" + endpoint + "/"
+		if len(q) > 0 {
+			u += "?" + q.Encode()
+		}
+
+		body, err := g.do(ctx, u)
+		if err != nil {
+			return nil, fmt.Errorf("querying grafana oncall %s: %w", endpoint, err)
+		}
+		var resp struct {
+			Next    *string `json:"next"`
+			Results []T     `json:"results"`
+		}
+		err = json.NewDecoder(body).Decode(&resp)
+		body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("decoding grafana oncall %s: %w", endpoint, err)
+		}
+		results = append(results, resp.Results...)
+		if resp.Next == nil || *resp.Next == "" {
+			return results, nil
+		}
+	}
+}
+
+type grafanaUser struct {
+	ID       string   `json:"id"`
+	Email    string   `json:"email"`
+	Username string   `json:"username"`
+	Role     string   `json:"role"`
+	Teams    []string `json:"teams"`
+}
+
+func (g *GrafanaOnCall) LoadUsers(ctx context.Context) error {
+	users, err := grafanaList[grafanaUser](ctx, g, "users", nil)
+	if err != nil {
+		return err
+	}
+	for _, user := range users {
+		annotations := fmt.Sprintf("[Grafana OnCall] %s", user.Username)
+		if user.Role != "" {
+			annotations += fmt.Sprintf(" [%s]", user.Role)
+		}
+		if err := store.UseQueries(ctx).InsertExtUser(ctx, store.InsertExtUserParams{
+			ID:          user.ID,
+			Name:        user.Username,
+			Email:       user.Email,
+			Annotations: annotations,
+		}); err != nil {
+			return fmt.Errorf("saving user to db: %w", err)
+		}
+	}
+	return nil
+}
+
+func (g *GrafanaOnCall) LoadTeams(ctx context.Context) error {
+	type team struct {
+		ID    string `json:"id"`
+		Name  string `json:"name"`
+		Email string `json:"email"`
+	}
+	teams, err := grafanaList[team](ctx, g, "teams", nil)
+	if err != nil {
+		return err
+	}
+	for _, t := range teams {
+		annotations := fmt.Sprintf("[Grafana OnCall] %s", t.Name)
+		if t.Email != "" {
+			annotations += fmt.Sprintf(" <%s>", t.Email)
+		}
+		if err := store.UseQueries(ctx).InsertExtTeam(ctx, store.InsertExtTeamParams{
+			ID:          t.ID,
+			Name:        t.Name,
+			Slug:        slug.Make(t.Name),
+			Annotations: annotations,
+		}); err != nil {
+			return fmt.Errorf("saving team to db: %w", err)
+		}
+	}
+	return nil
+}
+
+// LoadTeamMembers saves the memberships of the selected teams. Grafana OnCall lists the teams of
+// each user rather than the members of each team.
+func (g *GrafanaOnCall) LoadTeamMembers(ctx context.Context) error {
+	q := store.UseQueries(ctx)
+	teams, err := q.ListTeams(ctx)
+	if err != nil {
+		return fmt.Errorf("loading teams: %w", err)
+	}
+	teamIDs := map[string]bool{}
+	for _, t := range teams {
+		teamIDs[t.ID] = true
+	}
+
+	users, err := grafanaList[grafanaUser](ctx, g, "users", nil)
+	if err != nil {
+		return err
+	}
+	for _, user := range users {
+		for _, teamID := range user.Teams {
+			if !teamIDs[teamID] {
+				continue
+			}
+			if err := q.InsertExtMembership(ctx, store.InsertExtMembershipParams{
+				TeamID: teamID,
+				UserID: user.ID,
+			}); err != nil {
+				return fmt.Errorf("saving team member to db: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+type grafanaSchedule struct {
+	ID       string `json:"id"`
+	TeamID   string `json:"team_id"`
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	TimeZone string `json:"time_zone"`
+
+	ICalURLPrimary   string `json:"ical_url_primary"`
+	ICalURLOverrides string `json:"ical_url_overrides"`
+}
+
+// grafanaShift is a Grafana OnCall on-call shift. Events of iCal schedules are converted to shifts
+// as well, so that both are imported the same way.
+type grafanaShift struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	TimeZone string `json:"time_zone"`
+	// Start is the local time of the first event in TimeZone, or in UTC when it is empty.
+	Start string `json:"start"`
+	// Duration is the length of each event in seconds.
+	Duration  int64    `json:"duration"`
+	Frequency string   `json:"frequency"`
+	Interval  int      `json:"interval"`
+	ByDay     []string `json:"by_day"`
+	Until     string   `json:"until"`
+
+	// Users are on call together in every event of a recurrent event, while the groups of
+	// RollingUsers take turns.
+	Users                      []string   `json:"users"`
+	RollingUsers               [][]string `json:"rolling_users"`
+	StartRotationFromUserIndex int        `json:"start_rotation_from_user_index"`
+}
+
+const grafanaTimeLayout = "2006-01-02T15:04:05"
+
+var grafanaWeekdays = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+func (s grafanaShift) start() (time.Time, error) {
+	loc := time.UTC
+	if s.TimeZone != "" {
+		l, err := time.LoadLocation(s.TimeZone)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("loading time zone '%s': %w", s.TimeZone, err)
+		}
+		loc = l
+	}
+	return time.ParseInLocation(grafanaTimeLayout, s.Start, loc)
+}
+
+// turnLength is how long each group of users is on call before handing off to the next one.
+func (s grafanaShift) turnLength() (time.Duration, error) {
+	interval := max(s.Interval, 1)
+	switch s.Frequency {
+	case "hourly":
+		return time.Duration(interval) * time.Hour, nil
+	case "daily":
+		return time.Duration(interval) * 24 * time.Hour, nil
+	case "weekly":
+		return time.Duration(interval) * 7 * 24 * time.Hour, nil
+	default:
+		return 0, fmt.Errorf("unsupported shift frequency '%s'", s.Frequency)
+	}
+}
+
+func (g *GrafanaOnCall) LoadSchedules(ctx context.Context) error {
+	schedules, err := grafanaList[grafanaSchedule](ctx, g, "schedules", nil)
+	if err != nil {
+		return err
+	}
+	for _, s := range schedules {
+		if err := g.saveScheduleToDB(ctx, s); err != nil {
+			return fmt.Errorf("saving schedule to db: %w", err)
+		}
+	}
+	return nil
+}
+
+func (g *GrafanaOnCall) saveScheduleToDB(ctx context.Context, s grafanaSchedule) error {
+	q := store.UseQueries(ctx)
+	if s.TeamID == "" {
+		diagnostics.Warnf(ctx, diagnostics.ResourceSchedule, s.ID, "No owning team found for schedule %s, skipping...\n", s.ID)
+		return nil
+	}
+	if _, err := q.GetExtTeam(ctx, s.TeamID); err != nil {
+		diagnostics.Warnf(ctx, diagnostics.ResourceSchedule, s.ID, "Schedule %q (%s) belongs to a team that isn't imported.  Skipping...\n", s.Name, s.ID)
+		return nil
+	}
+
+	timezone := s.TimeZone
+	if timezone == "" {
+		timezone = "UTC"
+	}
+	if err := q.InsertExtScheduleV2(ctx, store.InsertExtScheduleV2Params{
+		ID:               s.ID,
+		Name:             s.Name,
+		Description:      "",
+		Timezone:         timezone,
+		TeamID:           s.TeamID,
+		SourceSystem:     "grafanaoncall",
+		SourceScheduleID: s.ID,
+	}); err != nil {
+		return fmt.Errorf("saving schedule: %w", err)
+	}
+
+	// Web and calendar schedules are made of on-call shifts, while iCal schedules are imported from
+	// their calendar's recurring events. Calendar schedules may also have an iCal feed of overrides.
+	var shifts []grafanaShift
+	var err error
+	if s.Type == "ical" {
+		shifts, err = g.icalShifts(ctx, s.ICalURLPrimary, false)
+		if err != nil {
+			return fmt.Errorf("importing iCal of schedule '%s': %w", s.Name, err)
+		}
+	} else {
+		shifts, err = grafanaList[grafanaShift](ctx, g, "on_call_shifts", url.Values{"schedule_id": {s.ID}})
+		if err != nil {
+			return err
+		}
+	}
+	if s.ICalURLOverrides != "" {
+		overrides, err := g.icalShifts(ctx, s.ICalURLOverrides, true)
+		if err != nil {
+			return fmt.Errorf("importing iCal overrides of schedule '%s': %w", s.Name, err)
+		}
+		shifts = append(shifts, overrides...)
+	}
+
+	rotationOrder := 0
+	for _, shift := range shifts {
+		saved, err := g.saveShiftToDB(ctx, s.ID, shift, rotationOrder)
+		if err != nil {
+			return fmt.Errorf("saving shift '%s': %w", shift.ID, err)
+		}
+		rotationOrder += saved
+	}
+	return nil
+}
+
+// saveShiftToDB saves a shift as one or more rotations of the schedule, or as overrides, and returns
+// the number of rotations saved.
+func (g *GrafanaOnCall) saveShiftToDB(ctx context.Context, scheduleID string, shift grafanaShift, rotationOrder int) (int, error) {
+	q := store.UseQueries(ctx)
+	schedule, err := q.GetExtScheduleV2(ctx, scheduleID)
+	if err != nil {
+		return 0, fmt.Errorf("getting schedule info: %w", err)
+	}
+	loc, err := time.LoadLocation(schedule.Timezone)
+	if err != nil {
+		diagnostics.Warnf(ctx, diagnostics.ResourceSchedule, scheduleID, "unable to parse location %s.  using UTC instead", schedule.Timezone)
+		loc = time.UTC
+	}
+
+	start, err := shift.start()
+	if err != nil {
+		return 0, fmt.Errorf("parsing start '%s': %w", shift.Start, err)
+	}
+	start = start.In(loc)
+
+	switch shift.Type {
+	case "override":
+		return 0, g.saveOverrideToDB(ctx, scheduleID, shift, start)
+	case "rolling_users", "recurrent_event":
+	default:
+		diagnostics.Warnf(ctx, diagnostics.ResourceRotation, shift.ID, "Shift %q of schedule %q is a one-off '%s' shift which can't be imported as a rotation, skipping...\n", shift.Name, schedule.Name, shift.Type)
+		return 0, nil
+	}
+
+	if shift.Until != "" {
+		until, err := parseGrafanaTime(shift.Until, start.Location())
+		if err != nil {
+			return 0, fmt.Errorf("parsing until '%s': %w", shift.Until, err)
+		}
+		if until.Before(g.now()) {
+			diagnostics.Infof(ctx, diagnostics.ResourceRotation, shift.ID, "Shift %q of schedule %q ended on %s, skipping...\n", shift.Name, schedule.Name, shift.Until)
+			return 0, nil
+		}
+	}
+
+	turnLength, err := shift.turnLength()
+	if err != nil {
+		diagnostics.Warnf(ctx, diagnostics.ResourceRotation, shift.ID, "Shift %q of schedule %q can't be imported: %s. Skipping...\n", shift.Name, schedule.Name, err.Error())
+		return 0, nil
+	}
+
+	// Every group of users takes a turn, starting with the group at the configured index.
+	groups := shift.RollingUsers
+	if shift.Type == "recurrent_event" {
+		groups = [][]string{shift.Users}
+	}
+	if n := len(groups); n > 0 {
+		i := shift.StartRotationFromUserIndex % n
+		groups = append(slices.Clone(groups[i:]), groups[:i]...)
+	}
+
+	// FireHydrant rotations have a single user on call at a time, so each position within the
+	// groups becomes its own rotation, all of which are on call at once.
+	positions := 0
+	for _, group := range groups {
+		positions = max(positions, len(group))
+	}
+	if positions == 0 {
+		diagnostics.Warnf(ctx, diagnostics.ResourceRotation, shift.ID, "Shift %q of schedule %q has nobody on call, skipping...\n", shift.Name, schedule.Name)
+		return 0, nil
+	}
+	for _, group := range groups {
+		if len(group) != positions {
+			diagnostics.Warnf(ctx, diagnostics.ResourceRotation, shift.ID, "Shift %q of schedule %q takes turns between groups of different sizes, which will take turns at a different pace in FireHydrant.\n", shift.Name, schedule.Name)
+			break
+		}
+	}
+	if shift.Frequency == "daily" && len(shift.ByDay) > 0 && len(shift.ByDay) < 7 {
+		diagnostics.Warnf(ctx, diagnostics.ResourceRotation, shift.ID, "Shift %q of schedule %q only takes turns on some days of the week, while FireHydrant takes turns every day.\n", shift.Name, schedule.Name)
+	}
+
+	restrictions, err := grafanaShiftRestrictions(ctx, shift, start, turnLength)
+	if err != nil {
+		return 0, err
+	}
+
+	for position := range positions {
+		rotationID := shift.ID
+		rotationName := shift.Name
+		if rotationName == "" {
+			rotationName = fmt.Sprintf("%srotation%d", schedule.Name, rotationOrder+position+1)
+		}
+		if positions > 1 {
+			rotationID = fmt.Sprintf("%s-%d", shift.ID, position)
+			rotationName = fmt.Sprintf("%s (%d)", rotationName, position+1)
+		}
+
+		rotationParams := store.InsertExtRotationParams{
+			ID:            rotationID,
+			ScheduleID:    scheduleID,
+			Name:          rotationName,
+			Description:   rotationName,
+			StartTime:     start.Format(time.RFC3339),
+			HandoffTime:   start.Format(time.TimeOnly),
+			HandoffDay:    strings.ToLower(start.Weekday().String()),
+			RotationOrder: int64(rotationOrder + position),
+		}
+		rotationParams.Strategy, rotationParams.ShiftDuration = rotationStrategy(turnLength)
+		if err := q.InsertExtRotation(ctx, rotationParams); err != nil {
+			return 0, fmt.Errorf("saving rotation: %w", err)
+		}
+
+		members := []string{}
+		for _, group := range groups {
+			if position < len(group) {
+				members = append(members, group[position])
+			}
+		}
+		for i, userID := range members {
+			if err := q.InsertExtRotationMember(ctx, store.InsertExtRotationMemberParams{
+				RotationID:  rotationID,
+				UserID:      userID,
+				MemberOrder: int64(i),
+			}); err != nil {
+				if strings.Contains(err.Error(), "FOREIGN KEY constraint") {
+					diagnostics.Warnf(ctx, diagnostics.ResourceRotation, rotationID, "User %s not found for rotation %s, skipping...\n", userID, rotationID)
+					_ = q.InsertExtRotationMemberSkip(ctx, store.InsertExtRotationMemberSkipParams{
+						RotationID: rotationID,
+						UserID:     userID,
+						UserEmail:  "",
+						Reason:     "missing_fh_user",
+					})
+				} else if strings.Contains(err.Error(), "UNIQUE constraint") {
+					diagnostics.Warnf(ctx, diagnostics.ResourceRotation, rotationID, "User %s already exists for rotation %s, skipping duplicate...\n", userID, rotationID)
+				} else {
+					return 0, fmt.Errorf("saving rotation user: %w", err)
+				}
+			}
+		}
+
+		for i, r := range restrictions {
+			r.RotationID = rotationID
+			r.RestrictionIndex = strconv.Itoa(i)
+			if err := q.InsertExtRotationRestriction(ctx, r); err != nil {
+				return 0, fmt.Errorf("saving shift restriction: %w", err)
+			}
+		}
+	}
+	return positions, nil
+}
+
+// grafanaShiftRestrictions returns the weekly restrictions of a shift which is only on call for
+// part of each turn, e.g. a weekly rotation from 09:00 to 17:00 on weekdays. Restrictions are in
+// the schedule's time zone, which start is in.
+func grafanaShiftRestrictions(ctx context.Context, shift grafanaShift, start time.Time, turnLength time.Duration) ([]store.InsertExtRotationRestrictionParams, error) {
+	duration := time.Duration(shift.Duration) * time.Second
+	if duration <= 0 || (duration >= turnLength && len(shift.ByDay) == 0) {
+		return nil, nil
+	}
+
+	days := []time.Weekday{}
+	for _, d := range shift.ByDay {
+		day, ok := grafanaWeekdays[strings.ToUpper(d)]
+		if !ok {
+			return nil, fmt.Errorf("unknown day of week '%s'", d)
+		}
+		days = append(days, day)
+	}
+	if len(days) == 0 {
+		switch shift.Frequency {
+		case "daily":
+			days = []time.Weekday{time.Sunday, time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday}
+		case "weekly":
+			days = []time.Weekday{start.Weekday()}
+		default:
+			diagnostics.Warnf(ctx, diagnostics.ResourceRotation, shift.ID, "Shift %q is only on call for part of each %s turn, which can't be imported. Importing the full turn instead.\n", shift.Name, shift.Frequency)
+			return nil, nil
+		}
+	}
+	if len(days) == 7 && duration >= 24*time.Hour {
+		return nil, nil
+	}
+	if duration >= 7*24*time.Hour {
+		return nil, nil
+	}
+
+	shiftStart, err := shift.start()
+	if err != nil {
+		return nil, err
+	}
+	return weeklyRestrictions(shiftStart, duration, days, start.Location()), nil
+}
+
+func (g *GrafanaOnCall) saveOverrideToDB(ctx context.Context, scheduleID string, shift grafanaShift, start time.Time) error {
+	end := start.Add(time.Duration(shift.Duration) * time.Second)
+	if !end.After(g.now()) {
+		return nil
+	}
+
+	userIDs := slices.Clone(shift.Users)
+	for _, group := range shift.RollingUsers {
+		userIDs = append(userIDs, group...)
+	}
+	q := store.UseQueries(ctx)
+	for i, userID := range userIDs {
+		// Overrides are matched to FireHydrant users by email, same as the users themselves. Users
+		// which were not imported keep their Grafana OnCall ID so they can still be identified.
+		username := userID
+		if u, err := q.GetUserByExtID(ctx, userID); err == nil {
+			username = u.Email
+		} else {
+			diagnostics.Warnf(ctx, diagnostics.ResourceOverride, shift.ID, "Override %s of schedule %s is assigned to user %s who is not imported.\n", shift.ID, scheduleID, userID)
+		}
+		id := shift.ID
+		if i > 0 {
+			id = fmt.Sprintf("%s-%d", shift.ID, i)
+		}
+		if err := q.InsertExtScheduleOverride(ctx, store.InsertExtScheduleOverrideParams{
+			ID:         id,
+			ScheduleID: scheduleID,
+			Username:   username,
+			StartTime:  start.Format(time.RFC3339),
+			EndTime:    end.Format(time.RFC3339),
+		}); err != nil {
+			return fmt.Errorf("saving override %s: %w", id, err)
+		}
+	}
+	return nil
+}
+
+func parseGrafanaTime(s string, loc *time.Location) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	return time.ParseInLocation(grafanaTimeLayout, s, loc)
+}
+
+// icalShifts converts the events of an iCal schedule into shifts. Grafana OnCall puts the users on
+// call for an event in its summary, by username or email. Recurring events become recurrent
+// shifts, while one-off events are only imported from the overrides calendar.
+func (g *GrafanaOnCall) icalShifts(ctx context.Context, icalURL string, overrides bool) ([]grafanaShift, error) {
+	if icalURL == "" {
+		return nil, nil
+	}
+	base, err := url.Parse(g.url)
+	if err != nil {
+		return nil, fmt.Errorf("parsing API URL: %w", err)
+	}
+	ref, err := url.Parse(icalURL)
+	if err != nil {
+		return nil, fmt.Errorf("parsing iCal URL: %w", err)
+	}
+	body, err := g.do(ctx, base.ResolveReference(ref).String())
+	if err != nil {
+		return nil, fmt.Errorf("fetching iCal: %w", err)
+	}
+	defer body.Close()
+	events, err := parseICalEvents(body)
+	if err != nil {
+		return nil, err
+	}
+
+	users, err := store.UseQueries(ctx).ListExtUsers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing users: %w", err)
+	}
+
+	shifts := []grafanaShift{}
+	for _, e := range events {
+		shift, err := e.shift()
+		if err != nil {
+			diagnostics.Warnf(ctx, diagnostics.ResourceRotation, e.props["UID"], "iCal event %q can't be imported: %s. Skipping...\n", e.props["SUMMARY"], err.Error())
+			continue
+		}
+		for _, name := range strings.FieldsFunc(e.props["SUMMARY"], func(r rune) bool { return r == ' ' || r == ',' }) {
+			i := slices.IndexFunc(users, func(u store.ExtUser) bool {
+				return strings.EqualFold(u.Name, name) || strings.EqualFold(u.Email, name)
+			})
+			if i == -1 {
+				diagnostics.Warnf(ctx, diagnostics.ResourceRotation, shift.ID, "iCal event %q is assigned to %s who is not a Grafana OnCall user, skipping...\n", shift.Name, name)
+				continue
+			}
+			shift.Users = append(shift.Users, users[i].ID)
+		}
+		switch {
+		case overrides && shift.Frequency == "":
+			shift.Type = "override"
+		case overrides:
+			diagnostics.Warnf(ctx, diagnostics.ResourceOverride, shift.ID, "Recurring override %q can't be imported, skipping...\n", shift.Name)
+			continue
+		case shift.Frequency == "":
+			shift.Type = "single_event"
+		default:
+			shift.Type = "recurrent_event"
+		}
+		shifts = append(shifts, shift)
+	}
+	return shifts, nil
+}
+
+type icalEvent struct {
+	// props holds the value of each property, and params the parameters of each, e.g. TZID.
+	props  map[string]string
+	params map[string]map[string]string
+}
+
+// parseICalEvents reads the events of an iCal feed. Only the properties needed to import shifts
+// are interpreted, and modified occurrences of recurring events are ignored.
+func parseICalEvents(r io.Reader) ([]icalEvent, error) {
+	// Long lines are folded by starting the continuation lines with a space or tab.
+	lines := []string{}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if n := len(lines); n > 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			lines[n-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading iCal: %w", err)
+	}
+
+	events := []icalEvent{}
+	var event *icalEvent
+	for _, line := range lines {
+		nameAndParams, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		parts := strings.Split(nameAndParams, ";")
+		name := strings.ToUpper(parts[0])
+		switch {
+		case name == "BEGIN" && value == "VEVENT":
+			event = &icalEvent{props: map[string]string{}, params: map[string]map[string]string{}}
+		case name == "END" && value == "VEVENT" && event != nil:
+			if _, modified := event.props["RECURRENCE-ID"]; !modified {
+				events = append(events, *event)
+			}
+			event = nil
+		case event != nil:
+			event.props[name] = value
+			event.params[name] = map[string]string{}
+			for _, p := range parts[1:] {
+				k, v, _ := strings.Cut(p, "=")
+				event.params[name][strings.ToUpper(k)] = v
+			}
+		}
+	}
+	return events, nil
+}
+
+func (e icalEvent) time(name string) (time.Time, error) {
+	value := e.props[name]
+	if e.params[name]["VALUE"] == "DATE" {
+		return time.Parse("20060102", value)
+	}
+	if strings.HasSuffix(value, "Z") {
+		return time.Parse("20060102T150405Z", value)
+	}
+	loc := time.UTC
+	if tzid := e.params[name]["TZID"]; tzid != "" {
+		l, err := time.LoadLocation(tzid)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("loading time zone '%s': %w", tzid, err)
+		}
+		loc = l
+	}
+	return time.ParseInLocation("20060102T150405", value, loc)
+}
+
+// shift converts the timing of the event, leaving its users and type to the caller.
+func (e icalEvent) shift() (grafanaShift, error) {
+	shift := grafanaShift{ID: e.props["UID"], Name: e.props["SUMMARY"]}
+	start, err := e.time("DTSTART")
+	if err != nil {
+		return shift, fmt.Errorf("parsing start: %w", err)
+	}
+	if shift.ID == "" {
+		shift.ID = slug.Make(fmt.Sprintf("%s %s", shift.Name, e.props["DTSTART"]))
+	}
+	var end time.Time
+	switch {
+	case e.props["DTEND"] != "":
+		end, err = e.time("DTEND")
+		if err != nil {
+			return shift, fmt.Errorf("parsing end: %w", err)
+		}
+	case e.props["DURATION"] != "":
+		d, err := time.ParseDuration(strings.ToLower(strings.TrimPrefix(e.props["DURATION"], "PT")))
+		if err != nil {
+			return shift, fmt.Errorf("parsing duration '%s': %w", e.props["DURATION"], err)
+		}
+		end = start.Add(d)
+	default:
+		return shift, fmt.Errorf("event has no end")
+	}
+	shift.TimeZone = start.Location().String()
+	shift.Start = start.Format(grafanaTimeLayout)
+	shift.Duration = int64(end.Sub(start).Seconds())
+
+	if rrule := e.props["RRULE"]; rrule != "" {
+		for _, part := range strings.Split(rrule, ";") {
+			k, v, _ := strings.Cut(part, "=")
+			switch strings.ToUpper(k) {
+			case "FREQ":
+				shift.Frequency = strings.ToLower(v)
+			case "INTERVAL":
+				shift.Interval, _ = strconv.Atoi(v)
+			case "BYDAY":
+				shift.ByDay = strings.Split(v, ",")
+			case "UNTIL":
+				until, err := time.Parse("20060102T150405Z", v)
+				if err != nil {
+					until, err = time.Parse("20060102", v)
+				}
+				if err != nil {
+					return shift, fmt.Errorf("parsing until '%s': %w", v, err)
+				}
+				shift.Until = until.Format(time.RFC3339)
+			}
+		}
+	}
+	return shift, nil
+}
+
+type grafanaEscalationStep struct {
+	ID       string `json:"id"`
+	Position int    `json:"position"`
+	Type     string `json:"type"`
+	// Duration is how long a "wait" step waits, in seconds.
+	Duration int `json:"duration"`
+
+	PersonsToNotify             []string `json:"persons_to_notify"`
+	PersonsToNotifyNextEachTime []string `json:"persons_to_notify_next_each_time"`
+	NotifyOnCallFromSchedule    string   `json:"notify_on_call_from_schedule"`
+	NotifyToTeamMembers         string   `json:"notify_to_team_members"`
+}
+
+func (g *GrafanaOnCall) LoadEscalationPolicies(ctx context.Context) error {
+	type chain struct {
+		ID     string `json:"id"`
+		TeamID string `json:"team_id"`
+		Name   string `json:"name"`
+	}
+	chains, err := grafanaList[chain](ctx, g, "escalation_chains", nil)
+	if err != nil {
+		return err
+	}
+	for _, c := range chains {
+		steps, err := grafanaList[grafanaEscalationStep](ctx, g, "escalation_policies", url.Values{"escalation_chain_id": {c.ID}})
+		if err != nil {
+			return err
+		}
+		sort.SliceStable(steps, func(i, j int) bool { return steps[i].Position < steps[j].Position })
+		if err := g.saveEscalationChainToDB(ctx, c.ID, c.Name, c.TeamID, steps); err != nil {
+			return fmt.Errorf("saving escalation policy to db: %w", err)
+		}
+	}
+	return nil
+}
+
+// grafanaStep is a FireHydrant escalation step assembled from Grafana OnCall escalation steps.
+type grafanaStep struct {
+	targets []store.InsertExtEscalationPolicyStepTargetParams
+	wait    int
+}
+
+// saveEscalationChainToDB saves an escalation chain as an escalation policy. Grafana OnCall runs
+// its steps one after the other, and only "wait" steps take time. As such, consecutive notification
+// steps are merged into one FireHydrant step, which times out after the wait that follows them.
+func (g *GrafanaOnCall) saveEscalationChainToDB(ctx context.Context, id string, name string, teamID string, chainSteps []grafanaEscalationStep) error {
+	q := store.UseQueries(ctx)
+	ep := store.InsertExtEscalationPolicyParams{
+		ID:          id,
+		Name:        name,
+		TeamID:      sql.NullString{Valid: teamID != "", String: teamID},
+		Annotations: fmt.Sprintf("[Grafana OnCall] %s", name),
+	}
+
+	// Notifying a team's members as the very last step is imported as a handoff to that team.
+	if n := len(chainSteps); n > 0 && chainSteps[n-1].Type == "notify_team_members" && chainSteps[n-1].NotifyToTeamMembers != "" {
+		ep.HandoffTargetType = store.TARGET_TYPE_TEAM
+		ep.HandoffTargetID = chainSteps[n-1].NotifyToTeamMembers
+		chainSteps = chainSteps[:n-1]
+	}
+
+	steps := []grafanaStep{}
+	waiting := true
+	addTarget := func(targetType string, targetID string) {
+		if waiting {
+			steps = append(steps, grafanaStep{})
+			waiting = false
+		}
+		last := &steps[len(steps)-1]
+		last.targets = append(last.targets, store.InsertExtEscalationPolicyStepTargetParams{TargetType: targetType, TargetID: targetID})
+	}
+	// A skipped target belongs to the step its siblings would have been added to.
+	skipTarget := func(targetType string, targetID string, reason string) store.InsertExtEscalationPolicyStepTargetSkipParams {
+		position := len(steps) - 1
+		if waiting {
+			position = len(steps)
+		}
+		return store.InsertExtEscalationPolicyStepTargetSkipParams{
+			EscalationPolicyID: id,
+			StepPosition:       int64(position),
+			TargetType:         targetType,
+			TargetID:           targetID,
+			Reason:             reason,
+		}
+	}
+	for _, s := range chainSteps {
+		switch s.Type {
+		case "wait":
+			if len(steps) == 0 {
+				diagnostics.Warnf(ctx, diagnostics.ResourceEscalationPolicy, id, "Escalation chain '%s' waits before notifying anyone, which can't be imported. Skipping the wait...\n", name)
+				continue
+			}
+			steps[len(steps)-1].wait += s.Duration
+			waiting = true
+		case "notify_persons", "notify_person_next_each_time":
+			if s.Type == "notify_person_next_each_time" {
+				diagnostics.Warnf(ctx, diagnostics.ResourceEscalationPolicy, id, "Escalation chain '%s' step %d notifies one person at a time, which is imported as notifying all of them.\n", name, s.Position)
+			}
+			for _, userID := range slices.Concat(s.PersonsToNotify, s.PersonsToNotifyNextEachTime) {
+				addTarget(store.TARGET_TYPE_USER, userID)
+			}
+		case "notify_on_call_from_schedule":
+			if _, err := q.GetExtScheduleV2(ctx, s.NotifyOnCallFromSchedule); err != nil {
+				diagnostics.SkipStepTarget(ctx, skipTarget(store.TARGET_TYPE_SCHEDULE, s.NotifyOnCallFromSchedule, store.TARGET_SKIP_NOT_IMPORTED),
+					"Schedule '%s' for escalation chain '%s' step %d isn't imported, skipping...\n", s.NotifyOnCallFromSchedule, name, s.Position)
+				continue
+			}
+			addTarget(store.TARGET_TYPE_SCHEDULE, s.NotifyOnCallFromSchedule)
+		case "repeat_escalation":
+			// Grafana OnCall repeats the chain up to 5 times, right after the wait before this step.
+			ep.RepeatLimit = 5
+			if n := len(steps); n > 0 && steps[n-1].wait > 0 {
+				ep.RepeatInterval = sql.NullString{Valid: true, String: fmt.Sprintf("PT%dM", int(math.Ceil(float64(steps[n-1].wait)/60)))}
+			}
+		default:
+			diagnostics.SkipStepTarget(ctx, skipTarget(s.Type, s.ID, store.TARGET_SKIP_UNSUPPORTED_TYPE),
+				"Escalation chain step is '%s' for chain '%s' step %d, skipping...\n", s.Type, name, s.Position)
+		}
+	}
+
+	if err := q.InsertExtEscalationPolicy(ctx, ep); err != nil {
+		if sqlErr, ok := store.AsSQLError(err); ok && sqlErr.IsForeignKeyConstraint() {
+			diagnostics.Warnf(ctx, diagnostics.ResourceEscalationPolicy, ep.ID, "Escalation policy %q (%s) belongs to a team that isn't imported. Skipping...\n", ep.Name, ep.ID)
+			return nil
+		}
+		return fmt.Errorf("saving escalation policy %q (%s): %w", ep.Name, ep.ID, err)
+	}
+
+	for position, step := range steps {
+		stepID := fmt.Sprintf("%s-%d", id, position)
+		// FireHydrant steps time out after 1 to 60 minutes.
+		minutes := int(math.Ceil(float64(step.wait) / 60))
+		if minutes > 60 {
+			diagnostics.Warnf(ctx, diagnostics.ResourceEscalationPolicy, id, "Actual delay time for step %d is %d minutes.  Locking to a max of 60 minutes.\n", position, minutes)
+		}
+		if err := q.InsertExtEscalationPolicyStep(ctx, store.InsertExtEscalationPolicyStepParams{
+			ID:                 stepID,
+			EscalationPolicyID: id,
+			Position:           int64(position),
+			Timeout:            fmt.Sprintf("PT%dM", max(1, min(minutes, 60))),
+		}); err != nil {
+			return fmt.Errorf("saving escalation policy step: %w", err)
+		}
+		for _, t := range step.targets {
+			t.EscalationPolicyStepID = stepID
+			if err := q.InsertExtEscalationPolicyStepTarget(ctx, t); err != nil {
+				if strings.Contains(err.Error(), "UNIQUE constraint") {
+					diagnostics.Warnf(ctx, diagnostics.ResourceEscalationPolicy, id, "Target %s already exists for step %s, skipping duplicate...\n", t.TargetID, stepID)
+					continue
+				}
+				return fmt.Errorf("saving escalation policy step target: %w", err)
+			}
+		}
+	}
+	return nil
+}
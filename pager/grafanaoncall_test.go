@@ -0,0 +1,204 @@
+package pager_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/firehydrant/signals-migrator/pager"
+	"github.com/firehydrant/signals-migrator/store"
+)
+
+func TestGrafanaOnCall(t *testing.T) {
+	// Shifts which ended and overrides in the past are skipped relative to this date.
+	pinnedNow := time.Date(2024, 4, 12, 0, 0, 0, 0, time.UTC)
+
+	// Avoid sharing setup code between tests to prevent test pollution in parallel execution.
+	setup := func(t *testing.T) (context.Context, pager.Pager) {
+		ctx := withTestDB(t)
+		ts := pagerProviderHttpServer(t)
+		g := pager.NewGrafanaOnCall("api-key-very-secret", ts.URL)
+		g.SetNow(func() time.Time { return pinnedNow })
+		return ctx, g
+	}
+
+	loadSchedules := func(t *testing.T, ctx context.Context, g pager.Pager) {
+		t.Helper()
+		if err := g.LoadUsers(ctx); err != nil {
+			t.Fatalf("error loading users: %s", err)
+		}
+		if err := g.LoadTeams(ctx); err != nil {
+			t.Fatalf("error loading teams: %s", err)
+		}
+		if err := g.LoadSchedules(ctx); err != nil {
+			t.Fatalf("error loading schedules: %s", err)
+		}
+	}
+
+	t.Run("LoadUsers", func(t *testing.T) {
+		t.Parallel()
+		ctx, g := setup(t)
+
+		// Users are listed across two pages.
+		if err := g.LoadUsers(ctx); err != nil {
+			t.Fatalf("error loading users: %s", err)
+		}
+		u, err := store.UseQueries(ctx).ListExtUsers(ctx)
+		if err != nil {
+			t.Fatalf("error loading users: %s", err)
+		}
+		assertJSON(t, u)
+	})
+
+	t.Run("LoadTeams", func(t *testing.T) {
+		t.Parallel()
+		ctx, g := setup(t)
+
+		if err := g.LoadTeams(ctx); err != nil {
+			t.Fatalf("error loading teams: %s", err)
+		}
+		teams, err := store.UseQueries(ctx).ListExtTeams(ctx)
+		if err != nil {
+			t.Fatalf("error loading teams: %s", err)
+		}
+		assertJSON(t, teams)
+	})
+
+	t.Run("LoadTeamMembers", func(t *testing.T) {
+		t.Parallel()
+		ctx, g := setup(t)
+
+		if err := g.LoadUsers(ctx); err != nil {
+			t.Fatalf("error loading users: %s", err)
+		}
+		if err := g.LoadTeams(ctx); err != nil {
+			t.Fatalf("error loading teams: %s", err)
+		}
+		if err := g.LoadTeamMembers(ctx); err != nil {
+			t.Fatalf("error loading team members: %s", err)
+		}
+		members, err := store.UseQueries(ctx).ListExtTeamMemberships(ctx)
+		if err != nil {
+			t.Fatalf("error loading team members: %s", err)
+		}
+		assertJSON(t, members)
+	})
+
+	t.Run("LoadSchedules", func(t *testing.T) {
+		t.Parallel()
+		ctx, g := setup(t)
+		loadSchedules(t, ctx, g)
+
+		// "Legacy" has no team, so it is skipped.
+		schedules, err := store.UseQueries(ctx).ListExtSchedulesV2(ctx)
+		if err != nil {
+			t.Fatalf("error loading schedules: %s", err)
+		}
+		t.Logf("found %d schedules", len(schedules))
+		assertJSON(t, schedules)
+	})
+
+	t.Run("LoadRotations", func(t *testing.T) {
+		t.Parallel()
+		ctx, g := setup(t)
+		loadSchedules(t, ctx, g)
+
+		// Shifts and iCal events map to rotations as follows:
+		// - "Weekly primary" starts with its second user and has no restrictions.
+		// - "Business hours" takes turns between pairs, so each position of the pairs is a rotation
+		//   restricted to weekdays.
+		// - "Old rotation" ended, "Monthly review" can't be expressed, and one-off events are skipped.
+		// - The recurring iCal events are restricted to their own duration, and the modified
+		//   occurrence of "evt-nights" is ignored.
+		type rotation struct {
+			store.ExtRotation
+			Members      []store.ExtRotationMember      `json:"members"`
+			Restrictions []store.ExtRotationRestriction `json:"restrictions"`
+		}
+		q := store.UseQueries(ctx)
+		extRotations, err := q.ListExtRotations(ctx)
+		if err != nil {
+			t.Fatalf("error loading rotations: %s", err)
+		}
+		rotations := []rotation{}
+		for _, r := range extRotations {
+			members, err := q.ListExtRotationMembers(ctx, r.ID)
+			if err != nil {
+				t.Fatalf("error loading rotation members: %s", err)
+			}
+			restrictions, err := q.ListExtRotationRestrictions(ctx, r.ID)
+			if err != nil {
+				t.Fatalf("error loading rotation restrictions: %s", err)
+			}
+			rotations = append(rotations, rotation{r, members, restrictions})
+		}
+		assertJSON(t, rotations)
+	})
+
+	t.Run("LoadOverrides", func(t *testing.T) {
+		t.Parallel()
+		ctx, g := setup(t)
+		loadSchedules(t, ctx, g)
+
+		// Past overrides are skipped, and overrides for unknown users are kept by their ID.
+		q := store.UseQueries(ctx)
+		overrides := []store.ExtScheduleOverride{}
+		for _, id := range []string{"SWEB", "SICAL"} {
+			o, err := q.ListExtScheduleOverridesByExtScheduleID(ctx, id)
+			if err != nil {
+				t.Fatalf("error loading overrides: %s", err)
+			}
+			overrides = append(overrides, o...)
+		}
+		assertJSON(t, overrides)
+	})
+
+	t.Run("LoadEscalationPolicies", func(t *testing.T) {
+		t.Parallel()
+		ctx, g := setup(t)
+		loadSchedules(t, ctx, g)
+		if err := g.LoadEscalationPolicies(ctx); err != nil {
+			t.Fatalf("error loading escalation policies: %s", err)
+		}
+
+		// "Orphaned escalation" belongs to a team which isn't imported, "Platform escalation"
+		// repeats, and "Payments escalation" hands off to the Platform team.
+		policies, err := store.UseQueries(ctx).ListExtEscalationPolicies(ctx)
+		if err != nil {
+			t.Fatalf("error loading escalation policies: %s", err)
+		}
+		t.Logf("found %d escalation policies", len(policies))
+		assertJSON(t, policies)
+	})
+
+	t.Run("LoadEscalationPolicySteps", func(t *testing.T) {
+		t.Parallel()
+		ctx, g := setup(t)
+		loadSchedules(t, ctx, g)
+		if err := g.LoadEscalationPolicies(ctx); err != nil {
+			t.Fatalf("error loading escalation policies: %s", err)
+		}
+
+		// Notifications up to each wait are merged into one step, which times out after the wait.
+		type step struct {
+			store.ExtEscalationPolicyStep
+			Targets []store.ExtEscalationPolicyStepTarget `json:"targets"`
+		}
+		q := store.UseQueries(ctx)
+		steps := []step{}
+		for _, id := range []string{"CPRIMARY", "CPAYMENTS"} {
+			extSteps, err := q.ListExtEscalationPolicySteps(ctx, id)
+			if err != nil {
+				t.Fatalf("error loading escalation policy steps: %s", err)
+			}
+			for _, s := range extSteps {
+				targets, err := q.ListExtEscalationPolicyStepTargets(ctx, s.ID)
+				if err != nil {
+					t.Fatalf("error loading targets for step %s: %s", s.ID, err)
+				}
+				steps = append(steps, step{s, targets})
+			}
+		}
+		assertJSON(t, steps)
+	})
+}
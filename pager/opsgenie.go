@@ -3,13 +3,15 @@ package pager
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"math"
+	"net/http"
 	"strconv"
 	"strings"
 	"time"
 
-	"github.com/firehydrant/signals-migrator/console"
+	"github.com/firehydrant/signals-migrator/diagnostics"
 	"github.com/firehydrant/signals-migrator/store"
 	"github.com/gosimple/slug"
 	"github.com/opsgenie/opsgenie-go-sdk-v2/client"
@@ -21,12 +23,20 @@ import (
 )
 
 type Opsgenie struct {
-	userClient       *user.Client
-	teamClient       *team.Client
-	scheduleClient   *schedule.Client
-	escalationClient *escalation.Client
+	userClient     *user.Client
+	teamClient     *team.Client
+	scheduleClient *schedule.Client
+	concurrency    int
+
+	// listClient requests the lists of teams, schedules and escalations page by page, which the
+	// SDK's own clients don't.
+	listClient *client.OpsGenieClient
 }
 
+// opsgenieRateLimit is how many requests per second Opsgenie accepts for the configuration APIs on
+// the lowest plans.
+const opsgenieRateLimit = 10
+
 func NewOpsgenie(apiKey string) *Opsgenie {
 	conf := &client.Config{
 		ApiKey: apiKey,
@@ -50,7 +60,22 @@ func NewOpsgenieWithURL(apiKey, url string) *Opsgenie {
 	return NewOpsgenieWithConfig(conf)
 }
 
+// NewOpsgenieWithConfig returns an Opsgenie provider with the given configuration. Unless an HTTP
+// client is configured, requests go through the rate limiting transport shared by the providers,
+// which also takes over retries from the SDK.
 func NewOpsgenieWithConfig(conf *client.Config) *Opsgenie {
+	if conf.HttpClient == nil {
+		conf.HttpClient = &http.Client{
+			Transport: newRateLimitedTransport(opsgenieRateLimit),
+			Timeout:   time.Minute,
+		}
+		conf.RetryPolicy = func(ctx context.Context, _ *http.Response, err error) (bool, error) {
+			if ctx.Err() != nil {
+				return false, ctx.Err()
+			}
+			return false, err
+		}
+	}
 	userClient, err := user.NewClient(conf)
 	if err != nil {
 		panic(fmt.Sprintf("creating opsgenie user client: %v", err))
@@ -63,16 +88,34 @@ func NewOpsgenieWithConfig(conf *client.Config) *Opsgenie {
 	if err != nil {
 		panic(fmt.Sprintf("creating opsgenie schedule client: %v", err))
 	}
-	escalationClient, err := escalation.NewClient(conf)
+	listClient, err := client.NewOpsGenieClient(conf)
 	if err != nil {
-		panic(fmt.Sprintf("creating opsgenie escalation client: %v", err))
+		panic(fmt.Sprintf("creating opsgenie client: %v", err))
 	}
 	return &Opsgenie{
-		userClient:       userClient,
-		teamClient:       teamClient,
-		scheduleClient:   scheduleClient,
-		escalationClient: escalationClient,
+		userClient:     userClient,
+		teamClient:     teamClient,
+		scheduleClient: scheduleClient,
+		concurrency:    DefaultConcurrency,
+		listClient:     listClient,
+	}
+}
+
+// SetConcurrency sets how many teams and schedules are fetched at once.
+func (o *Opsgenie) SetConcurrency(n int) {
+	o.concurrency = n
+}
+
+// Preflight requests a single user.
+func (o *Opsgenie) Preflight(ctx context.Context) error {
+	if _, err := o.userClient.List(ctx, &user.ListRequest{Limit: 1}); err != nil {
+		var apiErr *client.ApiError
+		if errors.As(err, &apiErr) && (apiErr.StatusCode == http.StatusUnauthorized || apiErr.StatusCode == http.StatusForbidden) {
+			return fmt.Errorf("querying opsgenie: %w: %w", ErrUnauthorized, err)
+		}
+		return fmt.Errorf("querying opsgenie: %w", err)
 	}
+	return nil
 }
 
 func (p *Opsgenie) Kind() string {
@@ -122,15 +165,74 @@ func (o *Opsgenie) LoadUsers(ctx context.Context) error {
 	return nil
 }
 
-func (o *Opsgenie) LoadTeams(ctx context.Context) error {
-	opts := &team.ListTeamRequest{}
+// ogListRequest requests a page of a list endpoint of the Opsgenie API.
+type ogListRequest struct {
+	client.BaseRequest
+	path   string
+	params map[string]string
+}
+
+func (r *ogListRequest) Validate() error {
+	return nil
+}
+
+func (r *ogListRequest) ResourcePath() string {
+	return r.path
+}
+
+func (r *ogListRequest) Method() string {
+	return http.MethodGet
+}
+
+func (r *ogListRequest) RequestParams() map[string]string {
+	return r.params
+}
+
+type ogListResult[T any] struct {
+	client.ResultMetadata
+	Data   []T `json:"data"`
+	Paging struct {
+		Next string `json:"next"`
+	} `json:"paging"`
+	TotalCount int `json:"totalCount"`
+}
+
+// ogList requests every page of a list endpoint of the Opsgenie API. Like the users, pages are
+// requested by offset for as long as the response links to a next page. When the API reports more
+// results than were listed, a warning about the given resource is recorded.
+func ogList[T any](ctx context.Context, o *Opsgenie, resource string, path string, params map[string]string) ([]T, error) {
+	results := []T{}
+	for {
+		req := &ogListRequest{path: path, params: map[string]string{}}
+		for k, v := range params {
+			req.params[k] = v
+		}
+		if offset := len(results); offset > 0 {
+			req.params["offset"] = strconv.Itoa(offset)
+		}
+		var page ogListResult[T]
+		if err := o.listClient.Exec(ctx, req, &page); err != nil {
+			return nil, err
+		}
+		results = append(results, page.Data...)
+
+		// Results are paginated, so break if we're on the last page.
+		if page.Paging.Next == "" || len(page.Data) == 0 {
+			if page.TotalCount > len(results) {
+				diagnostics.Warnf(ctx, resource, "", "Opsgenie reports %d results for %s, but only %d were listed. The rest are not imported.\n", page.TotalCount, path, len(results))
+			}
+			return results, nil
+		}
+	}
+}
 
-	resp, err := o.teamClient.List(ctx, opts)
+func (o *Opsgenie) LoadTeams(ctx context.Context) error {
+	teams, err := ogList[team.ListedTeams](ctx, o, diagnostics.ResourceTeam, "/v2/teams", nil)
 	if err != nil {
 		return fmt.Errorf("listing teams: %w", err)
 	}
 
-	for _, t := range resp.Teams {
+	for _, t := range teams {
 		if err := store.UseQueries(ctx).InsertExtTeam(ctx, store.InsertExtTeamParams{
 			ID:   t.Id,
 			Name: t.Name,
@@ -149,107 +251,160 @@ func (o *Opsgenie) LoadTeamMembers(ctx context.Context) error {
 	if err != nil {
 		return fmt.Errorf("listing teams: %w", err)
 	}
-	for _, t := range teams {
+	fetch := func(ctx context.Context, t store.LinkedTeam) ([]team.Member, error) {
 		resp, err := o.teamClient.Get(ctx, &team.GetTeamRequest{
 			IdentifierType:  team.Id,
 			IdentifierValue: t.ID,
 		})
 		if err != nil {
-			return fmt.Errorf("getting team members: %w", err)
+			return nil, fmt.Errorf("getting team members: %w", err)
 		}
-
-		for _, m := range resp.Members {
+		return resp.Members, nil
+	}
+	return fetchEach(ctx, o.concurrency, teams, fetch, func(t store.LinkedTeam, members []team.Member) error {
+		for _, m := range members {
 			if err := store.UseQueries(ctx).InsertExtMembership(ctx, store.InsertExtMembershipParams{
 				TeamID: t.ID,
 				UserID: m.User.ID,
 			}); err != nil {
 				if sqlErr, ok := store.AsSQLError(err); ok && sqlErr.IsForeignKeyConstraint() {
-					console.Warnf("User %q (%s) isn't imported. Skipping...\n", m.User.Username, m.User.ID)
-					return nil
+					diagnostics.Warnf(ctx, diagnostics.ResourceTeam, t.ID, "User %q (%s) isn't imported. Skipping...\n", m.User.Username, m.User.ID)
+					continue
 				}
 				return fmt.Errorf("saving user %q (%s) as member of %q (%s) to db: %w", m.User.Username, m.User.ID, t.Name, t.ID, err)
 			}
 		}
-	}
-	return nil
+		return nil
+	})
 }
 
 func (o *Opsgenie) LoadSchedules(ctx context.Context) error {
-	expandListRequest := true
-	resp, err := o.scheduleClient.List(ctx, &schedule.ListRequest{
-		Expand: &expandListRequest,
-	})
-
+	listed, err := ogList[schedule.Schedule](ctx, o, diagnostics.ResourceSchedule, "/v2/schedules", map[string]string{"expand": "rotation"})
 	if err != nil {
 		return err
 	}
 
-	for _, schedule := range resp.Schedule {
+	// Schedules of teams which aren't imported are skipped before fetching their details.
+	schedules := []schedule.Schedule{}
+	for _, s := range listed {
 		// To decide: check enabled field and don't create if false?
-		if err := o.saveScheduleToDB(ctx, schedule); err != nil {
-			return fmt.Errorf("saving schedule to db: %w", err)
+		if o.scheduleTeamImported(ctx, s) {
+			schedules = append(schedules, s)
 		}
 	}
 
-	return nil
+	return fetchEach(ctx, o.concurrency, schedules, o.fetchScheduleDetail, func(s schedule.Schedule, detail ogScheduleDetail) error {
+		if err := o.saveScheduleToDB(ctx, s, detail); err != nil {
+			return fmt.Errorf("saving schedule to db: %w", err)
+		}
+		return nil
+	})
+}
+
+// ogScheduleDetail is what is fetched for each schedule on top of the list of schedules.
+type ogScheduleDetail struct {
+	rotations []og.Rotation
+	overrides []schedule.ScheduleOverride
+}
+
+// scheduleTeamImported returns whether the team which owns a schedule is imported.
+func (o *Opsgenie) scheduleTeamImported(ctx context.Context, s schedule.Schedule) bool {
+	if s.OwnerTeam == nil {
+		diagnostics.Warnf(ctx, diagnostics.ResourceSchedule, s.Id, "No owning team found for schedule %s, skipping...\n", s.Id)
+		return false
+	}
+	if _, err := store.UseQueries(ctx).GetExtTeam(ctx, s.OwnerTeam.Id); err != nil {
+		diagnostics.Warnf(ctx, diagnostics.ResourceSchedule, s.Id, "Schedule %q (%s) belongs to a team that isn't imported.  Skipping...\n", s.Name, s.Id)
+		return false
+	}
+	return true
 }
 
-func (o *Opsgenie) saveScheduleToDB(ctx context.Context, s schedule.Schedule) error {
+// fetchScheduleDetail fetches the rotations and overrides of a schedule.
+func (o *Opsgenie) fetchScheduleDetail(ctx context.Context, s schedule.Schedule) (ogScheduleDetail, error) {
 	resp, err := o.scheduleClient.Get(ctx, &schedule.GetRequest{
 		IdentifierType:  schedule.Id,
 		IdentifierValue: s.Id,
 	})
+	if err != nil {
+		return ogScheduleDetail{}, err
+	}
 
+	overrides, err := o.scheduleClient.ListScheduleOverride(ctx, &schedule.ListScheduleOverrideRequest{
+		ScheduleIdentifierType: schedule.Id,
+		ScheduleIdentifier:     s.Id,
+	})
 	if err != nil {
-		return err
+		return ogScheduleDetail{}, fmt.Errorf("getting schedule overrides: %w", err)
 	}
+	return ogScheduleDetail{rotations: resp.Schedule.Rotations, overrides: overrides.ScheduleOverride}, nil
+}
 
-	// each Opsgenie schedule can have multiple rotations, where each rotation has participants, start and handoff date/times, and restrictions.
-	// this maps directly to our structure with each rotation belonging to a schedule
-	teamID := ""
-	if s.OwnerTeam != nil {
-		teamID = s.OwnerTeam.Id
-	} else {
-		console.Warnf("No owning team found for schedule %s, skipping...\n", s.Id)
-		return nil
+// RenderOnCall returns the periods of the schedule's final timeline, which Opsgenie computes from all
+// of its rotations, restrictions and overrides.
+func (o *Opsgenie) RenderOnCall(ctx context.Context, scheduleID string, since time.Time, until time.Time) ([]OnCallShift, error) {
+	// The timeline can only be requested in whole weeks, so it is clipped to the requested period.
+	weeks := int(math.Ceil(until.Sub(since).Hours() / (7 * 24)))
+	resp, err := o.scheduleClient.GetTimeline(ctx, &schedule.GetTimelineRequest{
+		IdentifierType:  schedule.Id,
+		IdentifierValue: scheduleID,
+		Expands:         []schedule.ExpandType{schedule.Base, schedule.Override},
+		Interval:        weeks,
+		IntervalUnit:    schedule.Weeks,
+		Date:            &since,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("fetching timeline of schedule %s: %w", scheduleID, err)
+	}
+
+	shifts := []OnCallShift{}
+	for _, rotation := range resp.FinalTimeline.Rotations {
+		for _, period := range rotation.Periods {
+			if period.Recipient.Type != og.User {
+				continue
+			}
+			start, end := period.StartDate, period.EndDate
+			if start.Before(since) {
+				start = since
+			}
+			if end.After(until) {
+				end = until
+			}
+			if !start.Before(end) {
+				continue
+			}
+			shifts = append(shifts, OnCallShift{UserID: period.Recipient.Id, Start: start, End: end})
+		}
 	}
+	return shifts, nil
+}
 
+func (o *Opsgenie) saveScheduleToDB(ctx context.Context, s schedule.Schedule, detail ogScheduleDetail) error {
+	// each Opsgenie schedule can have multiple rotations, where each rotation has participants, start and handoff date/times, and restrictions.
+	// this maps directly to our structure with each rotation belonging to a schedule
 	scheduleParams := store.InsertExtScheduleV2Params{
 		ID:               s.Id,
 		Name:             s.Name,
 		Description:      s.Description,
 		Timezone:         s.Timezone,
-		TeamID:           teamID,
+		TeamID:           s.OwnerTeam.Id,
 		SourceSystem:     "opsgenie",
 		SourceScheduleID: s.Id,
 	}
 
 	q := store.UseQueries(ctx)
-
-	if _, err := q.GetExtTeam(ctx, teamID); err != nil {
-		console.Warnf("Schedule %q (%s) belongs to a team that isn't imported.  Skipping...\n", s.Name, s.Id)
-		return nil
-	}
 	if err := q.InsertExtScheduleV2(ctx, scheduleParams); err != nil {
 		return fmt.Errorf("saving schedule: %w", err)
 	}
 
 	// Create rotation records under the parent schedule
-	for i, rotation := range resp.Schedule.Rotations {
+	for i, rotation := range detail.rotations {
 		if err := o.saveRotationToDB(ctx, s.Id, rotation, i); err != nil {
 			return fmt.Errorf("saving rotation to db: %w", err)
 		}
 	}
 
-	overrides, err := o.scheduleClient.ListScheduleOverride(ctx, &schedule.ListScheduleOverrideRequest{
-		ScheduleIdentifierType: schedule.Id,
-		ScheduleIdentifier:     s.Id,
-	})
-	if err != nil {
-		return fmt.Errorf("getting schedule overrides: %w", err)
-	}
-
-	for _, override := range overrides.ScheduleOverride {
+	for _, override := range detail.overrides {
 		if override.EndDate.After(time.Now()) {
 			err := q.InsertExtScheduleOverride(ctx, store.InsertExtScheduleOverrideParams{
 				ID:         override.Alias,
@@ -340,7 +495,7 @@ func (o *Opsgenie) saveRotationToDB(ctx context.Context, scheduleID string, r og
 	q := store.UseQueries(ctx)
 	if err := q.InsertExtRotation(ctx, rotationParams); err != nil {
 		if strings.Contains(err.Error(), "UNIQUE constraint") {
-			console.Warnf("Rotation %s already exists (duplicate rotation ID across schedules), skipping...\n", r.Id)
+			diagnostics.Warnf(ctx, diagnostics.ResourceRotation, r.Id, "Rotation %s already exists (duplicate rotation ID across schedules), skipping...\n", r.Id)
 			return nil
 		}
 		return fmt.Errorf("saving rotation: %w", err)
@@ -356,9 +511,9 @@ func (o *Opsgenie) saveRotationToDB(ctx context.Context, scheduleID string, r og
 			MemberOrder: int64(i),
 		}); err != nil {
 			if strings.Contains(err.Error(), "FOREIGN KEY constraint") {
-				console.Warnf("User %s not found for rotation %s, skipping...\n", p.Id, r.Id)
+				diagnostics.Warnf(ctx, diagnostics.ResourceRotation, r.Id, "User %s not found for rotation %s, skipping...\n", p.Id, r.Id)
 			} else if strings.Contains(err.Error(), "UNIQUE constraint") {
-				console.Warnf("User %s already exists for rotation %s, skipping duplicate...\n", p.Id, r.Id)
+				diagnostics.Warnf(ctx, diagnostics.ResourceRotation, r.Id, "User %s already exists for rotation %s, skipping duplicate...\n", p.Id, r.Id)
 			} else {
 				return fmt.Errorf("saving rotation user: %w", err)
 			}
@@ -374,7 +529,7 @@ func (o *Opsgenie) saveRotationToDB(ctx context.Context, scheduleID string, r og
 	if r.TimeRestriction != nil {
 		loc, err := time.LoadLocation(schedule.Timezone)
 		if err != nil {
-			console.Warnf("unable to parse location %s.  using UTC instead", schedule.Timezone)
+			diagnostics.Warnf(ctx, diagnostics.ResourceRotation, r.Id, "unable to parse location %s.  using UTC instead", schedule.Timezone)
 			loc = time.UTC
 		}
 		switch r.TimeRestriction.Type {
@@ -422,7 +577,7 @@ func (o *Opsgenie) saveRotationToDB(ctx context.Context, scheduleID string, r og
 				}
 			}
 		default:
-			console.Warnf("Unknown schedule restriction type '%s' for rotation '%s', skipping...\n", r.TimeRestriction.Type, r.Id)
+			diagnostics.Warnf(ctx, diagnostics.ResourceRotation, r.Id, "Unknown schedule restriction type '%s' for rotation '%s', skipping...\n", r.TimeRestriction.Type, r.Id)
 		}
 	}
 
@@ -430,12 +585,12 @@ func (o *Opsgenie) saveRotationToDB(ctx context.Context, scheduleID string, r og
 }
 
 func (o *Opsgenie) LoadEscalationPolicies(ctx context.Context) error {
-	resp, err := o.escalationClient.List(ctx)
+	policies, err := ogList[escalation.Escalation](ctx, o, diagnostics.ResourceEscalationPolicy, "/v2/escalations", nil)
 	if err != nil {
 		return err
 	}
 
-	for _, policy := range resp.Escalations {
+	for _, policy := range policies {
 		if err := o.saveEscalationPolicyToDB(ctx, policy); err != nil {
 			return fmt.Errorf("saving escalation policy to db: %w", err)
 		}
@@ -465,17 +620,25 @@ func (o *Opsgenie) saveEscalationPolicyToDB(ctx context.Context, policy escalati
 		RepeatInterval: repeatInterval,
 		RepeatLimit:    repeatLimit,
 	}
+	// Notifying a team by default runs that team's escalation policy, which FireHydrant supports as
+	// a handoff once all steps are exhausted. Only a rule at the end of the policy is imported as such.
+	rules := policy.Rules
+	if n := len(rules); n > 0 && rules[n-1].Recipient.Type == og.Team && rules[n-1].NotifyType == og.Default {
+		ep.HandoffTargetType = store.TARGET_TYPE_TEAM
+		ep.HandoffTargetID = rules[n-1].Recipient.Id
+		rules = rules[:n-1]
+	}
 	if err := store.UseQueries(ctx).InsertExtEscalationPolicy(ctx, ep); err != nil {
 		if sqlErr, ok := store.AsSQLError(err); ok && sqlErr.IsForeignKeyConstraint() {
-			console.Warnf("Escalation policy %q (%s) belongs to a team that isn't imported. Skipping...\n", ep.Name, ep.ID)
+			diagnostics.Warnf(ctx, diagnostics.ResourceEscalationPolicy, ep.ID, "Escalation policy %q (%s) belongs to a team that isn't imported. Skipping...\n", ep.Name, ep.ID)
 			return nil
 		}
 
 		return fmt.Errorf("saving escalation policy %q (%s): %w", ep.Name, ep.ID, err)
 	}
 
-	for i, rule := range policy.Rules {
-		timeout := calculateTimeout(policy, i)
+	for i, rule := range rules {
+		timeout := calculateTimeout(ctx, policy, i)
 		if err := o.saveEscalationPolicyStepToDB(ctx, ep.ID, rule, int64(i), timeout); err != nil {
 			return fmt.Errorf("saving escalation rule to db: %w", err)
 		}
@@ -495,7 +658,7 @@ func (o *Opsgenie) saveEscalationPolicyToDB(ctx context.Context, policy escalati
 // with a special rule for the final step of:
 // Max(1, Min(60, policy.Repeat.WaitInterval minutes))
 
-func calculateTimeout(policy escalation.Escalation, position int) string {
+func calculateTimeout(ctx context.Context, policy escalation.Escalation, position int) string {
 	timeout := "PT1M"
 	if position+1 == len(policy.Rules) {
 		if policy.Repeat != nil {
@@ -514,13 +677,13 @@ func calculateTimeout(policy escalation.Escalation, position int) string {
 		}
 		// Warn the user that we're locking to min/max and give the actual value
 		if policy.Rules[position+1].Delay.TimeUnit != og.Minutes || policy.Rules[position+1].Delay.TimeAmount > 60 {
-			console.Warnf("Actual delay time for step %d is %d %s.  Locking to a max of 60 minutes.\n",
+			diagnostics.Warnf(ctx, diagnostics.ResourceEscalationPolicy, policy.Id, "Actual delay time for step %d is %d %s.  Locking to a max of 60 minutes.\n",
 				position+1,
 				policy.Rules[position+1].Delay.TimeAmount,
 				policy.Rules[position+1].Delay.TimeUnit)
 		}
 		if policy.Rules[position+1].Delay.TimeAmount == 0 {
-			console.Warnf("Actual delay time for step %d is 0.  Locking to a min of 1 minute.\n", position+1)
+			diagnostics.Warnf(ctx, diagnostics.ResourceEscalationPolicy, policy.Id, "Actual delay time for step %d is 0.  Locking to a min of 1 minute.\n", position+1)
 		}
 
 		timeout = fmt.Sprintf("PT%dM", int(math.Max(1, math.Min(float64(nextDelayMin-currentDelayMin), 60))))
@@ -557,7 +720,13 @@ func (o *Opsgenie) saveEscalationPolicyStepToDB(ctx context.Context, policyID st
 	// We only support recepients of User or Schedule and only the 'default' NotifyType.  Anything else we're just logging and skipping.
 
 	if rule.NotifyType != og.Default {
-		console.Errorf("Escalation policy step target is '%s' notify type '%s' for policy '%s' step %d.\nWe currently do not support this notify type, skipping...\n",
+		diagnostics.SkipStepTarget(ctx, store.InsertExtEscalationPolicyStepTargetSkipParams{
+			EscalationPolicyID: policyID,
+			StepPosition:       position,
+			TargetType:         string(rule.Recipient.Type),
+			TargetID:           rule.Recipient.Id,
+			Reason:             store.TARGET_SKIP_UNSUPPORTED_NOTIFY_TYPE,
+		}, "Escalation policy step target is '%s' notify type '%s' for policy '%s' step %d.\nWe currently do not support this notify type, skipping...\n",
 			rule.Recipient.Type,
 			rule.NotifyType,
 			policyID,
@@ -571,7 +740,13 @@ func (o *Opsgenie) saveEscalationPolicyStepToDB(ctx context.Context, policyID st
 	case og.Schedule:
 		t.TargetType = store.TARGET_TYPE_SCHEDULE
 	default:
-		console.Warnf("Escalation policy step target is '%s' notify type '%s' for policy '%s' step %d, skipping...\n",
+		diagnostics.SkipStepTarget(ctx, store.InsertExtEscalationPolicyStepTargetSkipParams{
+			EscalationPolicyID: policyID,
+			StepPosition:       position,
+			TargetType:         string(rule.Recipient.Type),
+			TargetID:           rule.Recipient.Id,
+			Reason:             store.TARGET_SKIP_UNSUPPORTED_TYPE,
+		}, "Escalation policy step target is '%s' notify type '%s' for policy '%s' step %d, skipping...\n",
 			rule.Recipient.Type,
 			rule.NotifyType,
 			policyID,
@@ -591,7 +766,7 @@ func (o *Opsgenie) saveEscalationPolicyStepToDB(ctx context.Context, policyID st
 	if t.TargetType == store.TARGET_TYPE_SCHEDULE {
 		schedule, err := store.UseQueries(ctx).GetExtScheduleV2(ctx, rule.Recipient.Id)
 		if err != nil {
-			console.Errorf("Failed to resolve schedule target '%s' for escalation policy step '%s': %s\n", rule.Recipient.Id, stepID, err.Error())
+			diagnostics.Errorf(ctx, diagnostics.ResourceEscalationPolicy, policyID, "Failed to resolve schedule target '%s' for escalation policy step '%s': %s\n", rule.Recipient.Id, stepID, err.Error())
 			return fmt.Errorf("resolving schedule target '%s': %w", rule.Recipient.Id, err)
 		}
 
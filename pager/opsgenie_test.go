@@ -2,9 +2,13 @@ package pager_test
 
 import (
 	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
 	"strings"
 	"testing"
 
+	"github.com/firehydrant/signals-migrator/diagnostics"
 	"github.com/firehydrant/signals-migrator/pager"
 	"github.com/firehydrant/signals-migrator/store"
 )
@@ -74,6 +78,63 @@ func TestOpsgenie(t *testing.T) {
 		assertJSON(t, u)
 	})
 
+	t.Run("LoadTeamMembersSkipsUsersNotImported", func(t *testing.T) {
+		t.Parallel()
+		// A member who isn't imported is listed before one who is.
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"data": {"id": "b7acbc33-9853-4150-8a4b-10156d9408c8", "name": "Customer Success", "members": [
+				{"user": {"id": "e5b92115-bfe7-43eb-8c2a-e467f2e5ddc4", "username": "unimported.member@donotimport.com"}, "role": "admin"},
+				{"user": {"id": "b5b92115-bfe7-43eb-8c2a-e467f2e5ddc4", "username": "john.doe@opsgenie.com"}, "role": "admin"}
+			]}}`))
+		}))
+		t.Cleanup(ts.Close)
+		ctx := diagnostics.WithCollector(store.WithContextAndDSN(context.Background(), store.FileDSN(filepath.Join(t.TempDir(), "state.db"))))
+		t.Cleanup(func() { _ = store.FromContext(ctx).Close() })
+		if _, err := store.FromContext(ctx).ExecContext(ctx, `
+INSERT INTO ext_users VALUES('b5b92115-bfe7-43eb-8c2a-e467f2e5ddc4','john doe','john.doe@opsgenie.com',NULL,'');
+INSERT INTO ext_teams VALUES('b7acbc33-9853-4150-8a4b-10156d9408c8','Customer Success','customer-success',NULL,0,0,'');
+`); err != nil {
+			t.Fatal(err)
+		}
+		og := pager.NewOpsgenieWithURL("api-key-very-secret", strings.TrimPrefix(ts.URL, "http://"))
+
+		if err := og.LoadTeamMembers(ctx); err != nil {
+			t.Fatalf("error loading team members: %s", err)
+		}
+		memberships, err := store.UseQueries(ctx).ListExtTeamMemberships(ctx)
+		if err != nil {
+			t.Fatalf("error loading team memberships: %s", err)
+		}
+		if len(memberships) != 1 || memberships[0].ExtUser.ID != "b5b92115-bfe7-43eb-8c2a-e467f2e5ddc4" {
+			t.Errorf("expected the imported member to be saved after the one who isn't, got %+v", memberships)
+		}
+		entries := diagnostics.FromContext(ctx).Entries()
+		if len(entries) != 1 || entries[0].Resource != diagnostics.ResourceTeam || !strings.Contains(entries[0].Message, "isn't imported") {
+			t.Errorf("expected a warning about the member who isn't imported, got %+v", entries)
+		}
+	})
+
+	t.Run("LoadTeamsWarnsAboutUnlistedTeams", func(t *testing.T) {
+		t.Parallel()
+		// The API reports more teams than it lists, without linking to a next page.
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"data": [{"id": "b7acbc33-9853-4150-8a4b-10156d9408c8", "name": "Customer Success"}], "totalCount": 3}`))
+		}))
+		t.Cleanup(ts.Close)
+		ctx := diagnostics.WithCollector(withTestDB(t))
+		og := pager.NewOpsgenieWithURL("api-key-very-secret", strings.TrimPrefix(ts.URL, "http://"))
+
+		if err := og.LoadTeams(ctx); err != nil {
+			t.Fatalf("error loading teams: %s", err)
+		}
+		entries := diagnostics.FromContext(ctx).Entries()
+		if len(entries) != 1 || entries[0].Resource != diagnostics.ResourceTeam || !strings.Contains(entries[0].Message, "reports 3 results") {
+			t.Errorf("expected a warning about unlisted teams, got %+v", entries)
+		}
+	})
+
 	t.Run("LoadSchedules", func(t *testing.T) {
 		ctx, og := setup(t)
 
@@ -3,6 +3,12 @@ package pager
 import (
 	"context"
 	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/firehydrant/signals-migrator/store"
 )
@@ -10,6 +16,7 @@ import (
 var (
 	ErrUnknownProvider = errors.New("unknown pager provider")
 	ErrNoResults       = errors.New("no results found")
+	ErrUnauthorized    = errors.New("the provider rejected the API key")
 )
 
 type Pager interface {
@@ -25,3 +32,71 @@ type Pager interface {
 
 	Teams(context.Context) ([]store.ExtTeam, error)
 }
+
+// OverrideImporter is implemented by providers which import schedule overrides for a limited window
+// of time, as only the upcoming overrides are relevant when migrating.
+type OverrideImporter interface {
+	SetOverrideWindow(window time.Duration)
+}
+
+// Preflighter is implemented by providers which can check that their API is reachable and accepts
+// the API key, before anything is asked or imported.
+type Preflighter interface {
+	Preflight(ctx context.Context) error
+}
+
+// statusError returns the error for an unexpected status code of a provider API. Rejected API keys
+// are reported as ErrUnauthorized.
+func statusError(code int, body []byte) error {
+	if code == http.StatusUnauthorized || code == http.StatusForbidden {
+		return fmt.Errorf("%w: unexpected status code %d: %s", ErrUnauthorized, code, body)
+	}
+	return fmt.Errorf("unexpected status code %d: %s", code, body)
+}
+
+// OnCallShift is a period during which a user is on call for a schedule.
+type OnCallShift struct {
+	UserID string    `json:"user_id"`
+	Start  time.Time `json:"start"`
+	End    time.Time `json:"end"`
+}
+
+// OnCallRenderer is implemented by providers which can render who is on call for a schedule over
+// a period of time, with all of their layering, restrictions and overrides applied.
+type OnCallRenderer interface {
+	RenderOnCall(ctx context.Context, scheduleID string, since time.Time, until time.Time) ([]OnCallShift, error)
+}
+
+// rotationStrategy maps the length of a single on-call turn to a FireHydrant rotation strategy.
+// FireHydrant's daily and weekly strategies are always exactly 24 hours and 7 days, so any other
+// turn length becomes a custom strategy with an ISO 8601 shift duration.
+func rotationStrategy(turnLength time.Duration) (strategy string, shiftDuration string) {
+	switch turnLength {
+	case 24 * time.Hour:
+		return "daily", ""
+	case 7 * 24 * time.Hour:
+		return "weekly", ""
+	default:
+		return "custom", fmt.Sprintf("PT%dS", int64(turnLength.Seconds()))
+	}
+}
+
+// weeklyRestrictions returns the restrictions for being on call for duration from the time of day of
+// start, on each of the given days. Days are relative to start's own time zone, and the restrictions
+// are converted to loc, the time zone of the schedule.
+func weeklyRestrictions(start time.Time, duration time.Duration, days []time.Weekday, loc *time.Location) []store.InsertExtRotationRestrictionParams {
+	days = slices.Clone(days)
+	sort.Slice(days, func(i, j int) bool { return days[i] < days[j] })
+	restrictions := []store.InsertExtRotationRestrictionParams{}
+	for _, day := range days {
+		from := start.AddDate(0, 0, int(day-start.Weekday())).In(loc)
+		to := from.Add(duration)
+		restrictions = append(restrictions, store.InsertExtRotationRestrictionParams{
+			StartDay:  strings.ToLower(from.Weekday().String()),
+			StartTime: from.Format(time.TimeOnly),
+			EndDay:    strings.ToLower(to.Weekday().String()),
+			EndTime:   to.Format(time.TimeOnly),
+		})
+	}
+	return restrictions
+}
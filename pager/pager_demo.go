@@ -15,7 +15,12 @@ import (
 	"github.com/gosimple/slug"
 )
 
-func NewPager(ctx context.Context, kind string, apiKey string, appId string) (Pager, error) {
+// NewPager returns a provider serving recorded responses. They cannot be served from another API
+// URL, so an apiURL is rejected rather than ignored.
+func NewPager(ctx context.Context, kind string, apiKey string, appId string, apiURL string) (Pager, error) {
+	if apiURL != "" {
+		return nil, fmt.Errorf("the provider API URL is not supported by the demo build, which serves recorded responses")
+	}
 	switch strings.ToLower(kind) {
 	case "pagerduty":
 		rs := recorderServer(ctx, "PagerDuty")
@@ -23,6 +28,9 @@ func NewPager(ctx context.Context, kind string, apiKey string, appId string) (Pa
 	case "opsgenie":
 		rs := recorderServer(ctx, "Opsgenie")
 		return NewOpsgenieWithURL(apiKey, rs.URL), nil
+	case "xmatters":
+		rs := recorderServer(ctx, "XMatters")
+		return NewXMatters(apiKey, rs.URL), nil
 	}
 
 	return nil, fmt.Errorf("%w '%s'", ErrUnknownProvider, kind)
@@ -8,14 +8,39 @@ import (
 	"strings"
 )
 
-func NewPager(_ context.Context, kind string, apiKey string, appId string) (Pager, error) {
+// NewPager returns the provider of the given kind. apiURL overrides the default API URL of the
+// provider, e.g. to reach an instance hosted in the EU, and is left empty to use the default.
+func NewPager(_ context.Context, kind string, apiKey string, appId string, apiURL string) (Pager, error) {
 	switch strings.ToLower(kind) {
 	case "pagerduty":
+		if apiURL != "" {
+			return NewPagerDutyWithURL(apiKey, apiURL), nil
+		}
 		return NewPagerDuty(apiKey), nil
 	case "victorops":
+		if apiURL != "" {
+			return NewVictorOpsWithURL(apiKey, appId, strings.TrimSuffix(apiURL, "/")), nil
+		}
 		return NewVictorOps(apiKey, appId), nil
 	case "opsgenie":
+		if apiURL != "" {
+			// The Opsgenie SDK takes the host of the API, rather than its URL.
+			host := strings.TrimPrefix(strings.TrimPrefix(apiURL, "https://"), "http://")
+			return NewOpsgenieWithURL(apiKey, strings.TrimSuffix(host, "/")), nil
+		}
 		return NewOpsgenie(apiKey), nil
+	case "grafanaoncall":
+		// Every Grafana OnCall stack has its own API URL.
+		if apiURL == "" {
+			return nil, fmt.Errorf("the API URL of the Grafana OnCall stack must be passed as the provider API URL")
+		}
+		return NewGrafanaOnCall(apiKey, apiURL), nil
+	case "xmatters":
+		// Every xMatters company has its own instance URL.
+		if apiURL == "" {
+			return nil, fmt.Errorf("the URL of the xMatters instance must be passed as the provider API URL")
+		}
+		return NewXMatters(apiKey, apiURL), nil
 	}
 
 	return nil, fmt.Errorf("%w '%s'", ErrUnknownProvider, kind)
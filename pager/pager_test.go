@@ -32,7 +32,15 @@ func withTestDB(t *testing.T) context.Context {
 
 func pagerProviderHttpServer(t *testing.T) *httptest.Server {
 	t.Helper()
-	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	s := httptest.NewServer(pagerProviderHandler(t))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// pagerProviderHandler serves the recorded API responses of the provider under test.
+func pagerProviderHandler(t *testing.T) http.Handler {
+	t.Helper()
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		baseTestDir := t.Name()
 		// Get the root-most test name as directory.
 		// This should be "Test[ProviderName]", e.g. TestPagerDuty.
@@ -58,9 +66,6 @@ func pagerProviderHttpServer(t *testing.T) *httptest.Server {
 		}
 		http.ServeFile(w, r, filename)
 	})
-	s := httptest.NewServer(h)
-	t.Cleanup(s.Close)
-	return s
 }
 
 // assertJSON compares the JSON representation of `got` with the golden file.
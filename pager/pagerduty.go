@@ -3,23 +3,32 @@ package pager
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"net/http"
 	"slices"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/PagerDuty/go-pagerduty"
-	"github.com/firehydrant/signals-migrator/console"
+	"github.com/firehydrant/signals-migrator/diagnostics"
 	"github.com/firehydrant/signals-migrator/store"
 	"github.com/gosimple/slug"
 )
 
 type PagerDuty struct {
-	client *pagerduty.Client
-	now    func() time.Time
+	client      *pagerduty.Client
+	now         func() time.Time
+	concurrency int
+
+	overrideWindow time.Duration
 }
 
+// pagerDutyRateLimit is how many requests per second PagerDuty accepts for an API key, which is
+// 960 per minute.
+const pagerDutyRateLimit = 16
+
 var (
 	pdTeamInterface string
 
@@ -27,16 +36,22 @@ var (
 )
 
 func NewPagerDuty(apiKey string) *PagerDuty {
-	return &PagerDuty{
-		client: pagerduty.NewClient(apiKey),
-		now:    time.Now,
-	}
+	return newPagerDuty(pagerduty.NewClient(apiKey))
 }
 
 func NewPagerDutyWithURL(apiKey, url string) *PagerDuty {
+	return newPagerDuty(pagerduty.NewClient(apiKey, pagerduty.WithAPIEndpoint(url)))
+}
+
+func newPagerDuty(client *pagerduty.Client) *PagerDuty {
+	client.HTTPClient = &http.Client{
+		Transport: newRateLimitedTransport(pagerDutyRateLimit),
+		Timeout:   time.Minute,
+	}
 	return &PagerDuty{
-		client: pagerduty.NewClient(apiKey, pagerduty.WithAPIEndpoint(url)),
-		now:    time.Now,
+		client:      client,
+		now:         time.Now,
+		concurrency: DefaultConcurrency,
 	}
 }
 
@@ -48,6 +63,29 @@ func (p *PagerDuty) SetNow(now func() time.Time) {
 	p.now = now
 }
 
+// SetConcurrency sets how many teams and schedules are fetched at once.
+func (p *PagerDuty) SetConcurrency(n int) {
+	p.concurrency = n
+}
+
+// SetOverrideWindow enables importing the schedule overrides which are active or start within the
+// given window from now. Overrides are not imported while the window is zero.
+func (p *PagerDuty) SetOverrideWindow(window time.Duration) {
+	p.overrideWindow = window
+}
+
+// Preflight requests a single user.
+func (p *PagerDuty) Preflight(ctx context.Context) error {
+	if _, err := p.client.ListUsersWithContext(ctx, pagerduty.ListUsersOptions{Limit: 1}); err != nil {
+		var apiErr pagerduty.APIError
+		if errors.As(err, &apiErr) && (apiErr.StatusCode == http.StatusUnauthorized || apiErr.StatusCode == http.StatusForbidden) {
+			return fmt.Errorf("querying pagerduty: %w: %w", ErrUnauthorized, err)
+		}
+		return fmt.Errorf("querying pagerduty: %w", err)
+	}
+	return nil
+}
+
 func (p *PagerDuty) Kind() string {
 	return "PagerDuty"
 }
@@ -197,7 +235,7 @@ func (p *PagerDuty) loadServices(ctx context.Context) error {
 				}); err != nil {
 					if strings.Contains(err.Error(), "UNIQUE constraint") {
 						// Assume that team was already imported from another service.
-						console.Warnf("Team %s has been imported, skipping duplicate...\n", service.ID, team.ID)
+						diagnostics.Warnf(ctx, diagnostics.ResourceTeam, team.ID, "Team %s of service %s has been imported, skipping duplicate...\n", team.ID, service.ID)
 					} else {
 						return fmt.Errorf("saving team '%s (%s)' to db: %w", team.Name, team.ID, err)
 					}
@@ -208,7 +246,7 @@ func (p *PagerDuty) loadServices(ctx context.Context) error {
 				}); err != nil {
 					if strings.Contains(err.Error(), "UNIQUE constraint") {
 						// This should never happen, unless it's on a dirty database. Warn users anyway.
-						console.Warnf("Service %s already has team %s, skipping duplicate...\n", service.ID, team.ID)
+						diagnostics.Warnf(ctx, diagnostics.ResourceTeam, team.ID, "Service %s already has team %s, skipping duplicate...\n", service.ID, team.ID)
 					} else {
 						return fmt.Errorf("saving '%s (%s)' team as proxy for '%s (%s)' service: %w", team.Name, team.ID, service.Name, service.ID, err)
 					}
@@ -244,10 +282,12 @@ func (p *PagerDuty) loadTeamMembers(ctx context.Context) error {
 		return fmt.Errorf("listing teams: %w", err)
 	}
 
+	ids := []string{}
 	for _, team := range teams {
-		if err := p.loadMembers(ctx, team.ID); err != nil {
-			return fmt.Errorf("loading team members: %w", err)
-		}
+		ids = append(ids, team.ID)
+	}
+	if err := p.loadMembers(ctx, ids); err != nil {
+		return fmt.Errorf("loading team members: %w", err)
 	}
 	return nil
 }
@@ -258,45 +298,53 @@ func (p *PagerDuty) loadServiceTeamMembers(ctx context.Context) error {
 		return fmt.Errorf("listing teams: %w", err)
 	}
 
+	ids := []string{}
 	for _, team := range teams {
-		if err := p.loadMembers(ctx, team.ID); err != nil {
-			return fmt.Errorf("loading service team members: %w", err)
-		}
+		ids = append(ids, team.ID)
+	}
+	if err := p.loadMembers(ctx, ids); err != nil {
+		return fmt.Errorf("loading service team members: %w", err)
 	}
 	return nil
 }
 
-// loadMembers loads members of a team from PagerDuty API and saves them to the database.
-// teamID is the team ID which will be used in HTTP query to PagerDuty API, while memberOfTeamID is the
-// reference which will be used in database.
-func (p *PagerDuty) loadMembers(ctx context.Context, teamID string) error {
-	// PagerDuty REST API technically supports `includes[]=user` but it's not exposed in Go SDK.
-	// As such, we currently assume the user is already present in the database and only save the relationship.
-	opts := pagerduty.ListTeamMembersOptions{
-		Offset: 0,
-	}
+// loadMembers loads members of the teams from PagerDuty API and saves them to the database.
+// Teams are fetched concurrently, and saved in the order given.
+func (p *PagerDuty) loadMembers(ctx context.Context, teamIDs []string) error {
 	q := store.UseQueries(ctx)
-
-	for {
-		resp, err := p.client.ListTeamMembers(ctx, teamID, opts)
-		if err != nil {
-			return err
-		}
-
-		for _, member := range resp.Members {
+	return fetchEach(ctx, p.concurrency, teamIDs, p.fetchMembers, func(teamID string, members []pagerduty.Member) error {
+		for _, member := range members {
 			if err := q.InsertExtMembership(ctx, store.InsertExtMembershipParams{
 				TeamID: teamID,
 				UserID: member.User.ID,
 			}); err != nil {
 				if strings.Contains(err.Error(), "FOREIGN KEY constraint") {
-					console.Warnf("User %s not found for team %s, skipping...\n", member.User.ID, teamID)
+					diagnostics.Warnf(ctx, diagnostics.ResourceTeam, teamID, "User %s not found for team %s, skipping...\n", member.User.ID, teamID)
 				} else if strings.Contains(err.Error(), "UNIQUE constraint") {
-					console.Warnf("User %s already exists for team %s, skipping duplicate...\n", member.User.ID, teamID)
+					diagnostics.Warnf(ctx, diagnostics.ResourceTeam, teamID, "User %s already exists for team %s, skipping duplicate...\n", member.User.ID, teamID)
 				} else {
 					return fmt.Errorf("saving team member: %w", err)
 				}
 			}
 		}
+		return nil
+	})
+}
+
+// fetchMembers fetches every page of the members of a team.
+func (p *PagerDuty) fetchMembers(ctx context.Context, teamID string) ([]pagerduty.Member, error) {
+	// PagerDuty REST API technically supports `includes[]=user` but it's not exposed in Go SDK.
+	// As such, we currently assume the user is already present in the database and only save the relationship.
+	opts := pagerduty.ListTeamMembersOptions{
+		Offset: 0,
+	}
+	members := []pagerduty.Member{}
+	for {
+		resp, err := p.client.ListTeamMembers(ctx, teamID, opts)
+		if err != nil {
+			return nil, err
+		}
+		members = append(members, resp.Members...)
 
 		// Results are paginated, so break if we're on the last page.
 		if !resp.More {
@@ -304,10 +352,23 @@ func (p *PagerDuty) loadMembers(ctx context.Context, teamID string) error {
 		}
 		opts.Offset += uint(len(resp.Members))
 	}
-	return nil
+	return members, nil
+}
+
+// pdSchedule is a schedule to import, along with the team which owns it.
+type pdSchedule struct {
+	pagerduty.Schedule
+	teamID string
+}
+
+// pdScheduleDetail is what is fetched for each schedule on top of the list of schedules.
+type pdScheduleDetail struct {
+	layers    []pagerduty.ScheduleLayer
+	overrides []pagerduty.Override
 }
 
 func (p *PagerDuty) LoadSchedules(ctx context.Context) error {
+	schedules := []pdSchedule{}
 	opts := pagerduty.ListSchedulesOptions{Includes: []string{"schedule_layers"}}
 	for {
 		resp, err := p.client.ListSchedulesWithContext(ctx, opts)
@@ -315,9 +376,10 @@ func (p *PagerDuty) LoadSchedules(ctx context.Context) error {
 			return err
 		}
 
+		// Schedules of teams which aren't imported are skipped before fetching their details.
 		for _, schedule := range resp.Schedules {
-			if err := p.saveScheduleToDB(ctx, schedule); err != nil {
-				return fmt.Errorf("saving schedule to db: %w", err)
+			if teamID := p.scheduleTeamID(ctx, schedule); teamID != "" {
+				schedules = append(schedules, pdSchedule{Schedule: schedule, teamID: teamID})
 			}
 		}
 
@@ -328,81 +390,101 @@ func (p *PagerDuty) LoadSchedules(ctx context.Context) error {
 		opts.Offset += uint(len(resp.Schedules))
 	}
 
-	return nil
+	return fetchEach(ctx, p.concurrency, schedules, p.fetchScheduleDetail, func(schedule pdSchedule, detail pdScheduleDetail) error {
+		if err := p.saveScheduleToDB(ctx, schedule, detail); err != nil {
+			return fmt.Errorf("saving schedule to db: %w", err)
+		}
+		return nil
+	})
 }
 
-func (p *PagerDuty) saveScheduleToDB(ctx context.Context, schedule pagerduty.Schedule) error {
-	// PagerDuty uses a single schedule with multiple layers.
-	//   Within PagerDuty, these layers are compressed into a single schedule, with the later layers overriding the earlier layers in case of an overlap
-	// In FireHydrant, we will have a single schedule with multiple rotations,
-	//   Each layer will be converted into a rotation, however, rotations do not override each other
-	//   members of each respective rotation will all be on call
-
+// scheduleTeamID returns the team which owns a schedule, or an empty string when none of its teams
+// are imported.
+func (p *PagerDuty) scheduleTeamID(ctx context.Context, schedule pagerduty.Schedule) string {
 	// PagerDuty Team-Schedule Relationship:
 	// According to PagerDuty documentation: https://support.pagerduty.com/main/docs/teams#team-association-behavior
 	// - "Adding users to a schedule via API will add the users to any associated Teams"
 	// - "Associating a Team with a schedule via API will add the schedule's users to the Team"
 	// This means all teams associated with a schedule end up with the same users.
 	// Therefore, we can safely use the first team as the schedule owner without losing user information.
+	if len(schedule.Teams) == 0 {
+		diagnostics.Warnf(ctx, diagnostics.ResourceSchedule, schedule.ID, "No teams found for schedule %s, skipping...\n", schedule.ID)
+		return ""
+	}
+	// Find the first team that exists in ext_teams (user may have excluded some teams from import)
+	for _, t := range schedule.Teams {
+		if _, err := store.UseQueries(ctx).GetExtTeam(ctx, t.ID); err == nil {
+			return t.ID
+		}
+	}
+	diagnostics.Warnf(ctx, diagnostics.ResourceSchedule, schedule.ID, "Schedule %q (%s) belongs to a team that isn't imported.  Skipping...\n", schedule.Name, schedule.ID)
+	return ""
+}
 
-	q := store.UseQueries(ctx)
+// fetchScheduleDetail fetches the layers of a schedule rendered in its own time zone, and its
+// upcoming overrides.
+func (p *PagerDuty) fetchScheduleDetail(ctx context.Context, schedule pdSchedule) (pdScheduleDetail, error) {
+	// PagerDuty renders wall-clock fields (rotation_virtual_start, restriction
+	// start_time_of_day) in the requesting user's profile time zone, not the
+	// schedule's own. FireHydrant interprets those values in the schedule's
+	// declared time_zone, so re-fetch the schedule rendered in its own zone
+	// and build rotations from that response. Restriction times carry no
+	// offset, so this cannot be corrected after the fact.
+	detail, err := p.client.GetScheduleWithContext(ctx, schedule.ID, pagerduty.GetScheduleOptions{TimeZone: schedule.TimeZone})
+	if err != nil {
+		return pdScheduleDetail{}, fmt.Errorf("fetching schedule %s rendered in %q: %w", schedule.ID, schedule.TimeZone, err)
+	}
+	if p.overrideWindow <= 0 {
+		return pdScheduleDetail{layers: detail.ScheduleLayers}, nil
+	}
 
-	teamID := ""
-	if len(schedule.Teams) > 0 {
-		// Find the first team that exists in ext_teams (user may have excluded some teams from import)
-		for _, t := range schedule.Teams {
-			if _, err := q.GetExtTeam(ctx, t.ID); err == nil {
-				teamID = t.ID
-				break
-			}
-		}
-		if teamID == "" {
-			console.Warnf("Schedule %q (%s) belongs to a team that isn't imported.  Skipping...\n", schedule.Name, schedule.ID)
-			return nil
-		}
-	} else {
-		console.Warnf("No teams found for schedule %s, skipping...\n", schedule.ID)
-		return nil
+	now := p.now()
+	resp, err := p.client.ListOverridesWithContext(ctx, schedule.ID, pagerduty.ListOverridesOptions{
+		Since: now.Format(time.RFC3339),
+		Until: now.Add(p.overrideWindow).Format(time.RFC3339),
+	})
+	if err != nil {
+		return pdScheduleDetail{}, fmt.Errorf("fetching overrides for schedule %s: %w", schedule.ID, err)
 	}
+	return pdScheduleDetail{layers: detail.ScheduleLayers, overrides: resp.Overrides}, nil
+}
+
+func (p *PagerDuty) saveScheduleToDB(ctx context.Context, schedule pdSchedule, detail pdScheduleDetail) error {
+	// PagerDuty uses a single schedule with multiple layers.
+	//   Within PagerDuty, these layers are compressed into a single schedule, with the later layers overriding the earlier layers in case of an overlap
+	// In FireHydrant, we will have a single schedule with multiple rotations,
+	//   Each layer will be converted into a rotation, however, rotations do not override each other
+	//   members of each respective rotation will all be on call
+
+	q := store.UseQueries(ctx)
 
 	scheduleParams := store.InsertExtScheduleV2Params{
 		ID:               schedule.ID,
 		Name:             schedule.Name,
 		Description:      schedule.Description,
 		Timezone:         schedule.TimeZone,
-		TeamID:           teamID,
+		TeamID:           schedule.teamID,
 		SourceSystem:     "pagerduty",
 		SourceScheduleID: schedule.ID,
 	}
 
 	if err := q.InsertExtScheduleV2(ctx, scheduleParams); err != nil {
 		if strings.Contains(err.Error(), "FOREIGN KEY constraint") {
-			console.Warnf("Schedule %q (%s) references a missing team, skipping...\n", schedule.Name, schedule.ID)
+			diagnostics.Warnf(ctx, diagnostics.ResourceSchedule, schedule.ID, "Schedule %q (%s) references a missing team, skipping...\n", schedule.Name, schedule.ID)
 			return nil
 		}
 		return fmt.Errorf("saving schedule: %w", err)
 	}
 
-	// PagerDuty renders wall-clock fields (rotation_virtual_start, restriction
-	// start_time_of_day) in the requesting user's profile time zone, not the
-	// schedule's own. FireHydrant interprets those values in the schedule's
-	// declared time_zone, so re-fetch the schedule rendered in its own zone
-	// and build rotations from that response. Restriction times carry no
-	// offset, so this cannot be corrected after the fact.
-	detail, err := p.client.GetScheduleWithContext(ctx, schedule.ID, pagerduty.GetScheduleOptions{TimeZone: schedule.TimeZone})
-	if err != nil {
-		return fmt.Errorf("fetching schedule %s rendered in %q: %w", schedule.ID, schedule.TimeZone, err)
-	}
-
 	// Create rotation records under the parent schedule (each layer becomes a rotation).
 	// PagerDuty keeps ended/replaced layers in the schedule_layers response with a non-null
 	// `end` timestamp. Skip those so they don't show up as extra FireHydrant rotations.
 	now := p.now()
 	order := 0
-	for _, layer := range detail.ScheduleLayers {
+	for _, layer := range detail.layers {
 		if layer.End != "" {
 			if end, err := time.Parse(time.RFC3339, layer.End); err == nil && end.Before(now) {
-				console.Warnf("Schedule layer %q (%s) ended at %s, skipping...\n", layer.Name, layer.ID, layer.End)
+				diagnostics.Warnf(ctx, diagnostics.ResourceRotation, layer.ID, "Schedule layer %q (%s) ended at %s, skipping...\n", layer.Name, layer.ID, layer.End)
 				continue
 			}
 		}
@@ -411,6 +493,60 @@ func (p *PagerDuty) saveScheduleToDB(ctx context.Context, schedule pagerduty.Sch
 		}
 		order++
 	}
+
+	if err := p.saveOverridesToDB(ctx, schedule.ID, detail.overrides); err != nil {
+		return fmt.Errorf("saving overrides to db: %w", err)
+	}
+	return nil
+}
+
+// RenderOnCall returns the entries of the schedule's final layer, which is what PagerDuty computes
+// from all of its layers, restrictions and overrides.
+func (p *PagerDuty) RenderOnCall(ctx context.Context, scheduleID string, since time.Time, until time.Time) ([]OnCallShift, error) {
+	schedule, err := p.client.GetScheduleWithContext(ctx, scheduleID, pagerduty.GetScheduleOptions{
+		Since: since.Format(time.RFC3339),
+		Until: until.Format(time.RFC3339),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("fetching final schedule %s: %w", scheduleID, err)
+	}
+
+	shifts := []OnCallShift{}
+	for _, entry := range schedule.FinalSchedule.RenderedScheduleEntries {
+		start, err := time.Parse(time.RFC3339, entry.Start)
+		if err != nil {
+			return nil, fmt.Errorf("parsing start of on-call entry '%s': %w", entry.Start, err)
+		}
+		end, err := time.Parse(time.RFC3339, entry.End)
+		if err != nil {
+			return nil, fmt.Errorf("parsing end of on-call entry '%s': %w", entry.End, err)
+		}
+		shifts = append(shifts, OnCallShift{UserID: entry.User.ID, Start: start, End: end})
+	}
+	return shifts, nil
+}
+
+func (p *PagerDuty) saveOverridesToDB(ctx context.Context, scheduleID string, overrides []pagerduty.Override) error {
+	q := store.UseQueries(ctx)
+	for _, override := range overrides {
+		// Overrides are matched to FireHydrant users by email, same as the users themselves. Users
+		// which were not imported keep their PagerDuty ID so they can still be identified.
+		username := override.User.ID
+		if u, err := q.GetUserByExtID(ctx, override.User.ID); err == nil {
+			username = u.Email
+		} else {
+			diagnostics.Warnf(ctx, diagnostics.ResourceOverride, override.ID, "Override %s of schedule %s is assigned to user %s who is not imported.\n", override.ID, scheduleID, override.User.ID)
+		}
+		if err := q.InsertExtScheduleOverride(ctx, store.InsertExtScheduleOverrideParams{
+			ID:         override.ID,
+			ScheduleID: scheduleID,
+			Username:   username,
+			StartTime:  override.Start,
+			EndTime:    override.End,
+		}); err != nil {
+			return fmt.Errorf("saving override %s: %w", override.ID, err)
+		}
+	}
 	return nil
 }
 
@@ -441,22 +577,14 @@ func (p *PagerDuty) saveLayerToDB(ctx context.Context, scheduleID string, layer
 		RotationOrder: int64(layerOrder),
 	}
 
-	switch layer.RotationTurnLengthSeconds {
-	case 60 * 60 * 24:
-		rotationParams.Strategy = "daily"
-	case 60 * 60 * 24 * 7:
-		rotationParams.Strategy = "weekly"
-	default:
-		rotationParams.Strategy = "custom"
-		rotationParams.ShiftDuration = fmt.Sprintf("PT%dS", layer.RotationTurnLengthSeconds)
-	}
+	rotationParams.Strategy, rotationParams.ShiftDuration = rotationStrategy(time.Duration(layer.RotationTurnLengthSeconds) * time.Second)
 	virtualStart, err := time.Parse(time.RFC3339, layer.RotationVirtualStart)
 	if err == nil {
 		rotationParams.HandoffTime = virtualStart.Format(time.TimeOnly)
 		rotationParams.HandoffDay = strings.ToLower(virtualStart.Weekday().String())
 		rotationParams.StartTime = virtualStart.Format(time.RFC3339)
 	} else {
-		console.Errorf("unable to parse virtual start time '%v', assuming default values", layer.RotationVirtualStart)
+		diagnostics.Errorf(ctx, diagnostics.ResourceRotation, layer.ID, "unable to parse virtual start time '%v', assuming default values", layer.RotationVirtualStart)
 	}
 
 	q := store.UseQueries(ctx)
@@ -474,7 +602,7 @@ func (p *PagerDuty) saveLayerToDB(ctx context.Context, scheduleID string, layer
 			MemberOrder: int64(i),
 		}); err != nil {
 			if strings.Contains(err.Error(), "FOREIGN KEY constraint") {
-				console.Warnf("User %s not found for rotation %s, skipping...\n", user.User.ID, layer.ID)
+				diagnostics.Warnf(ctx, diagnostics.ResourceRotation, layer.ID, "User %s not found for rotation %s, skipping...\n", user.User.ID, layer.ID)
 				_ = q.InsertExtRotationMemberSkip(ctx, store.InsertExtRotationMemberSkipParams{
 					RotationID: layer.ID,
 					UserID:     user.User.ID,
@@ -482,7 +610,7 @@ func (p *PagerDuty) saveLayerToDB(ctx context.Context, scheduleID string, layer
 					Reason:     "missing_fh_user",
 				})
 			} else if strings.Contains(err.Error(), "UNIQUE constraint") {
-				console.Warnf("User %s already exists for rotation %s, skipping duplicate...\n", user.User.ID, layer.ID)
+				diagnostics.Warnf(ctx, diagnostics.ResourceRotation, layer.ID, "User %s already exists for rotation %s, skipping duplicate...\n", user.User.ID, layer.ID)
 			} else {
 				return fmt.Errorf("saving rotation user: %w", err)
 			}
@@ -539,7 +667,7 @@ func (p *PagerDuty) saveLayerToDB(ctx context.Context, scheduleID string, layer
 				return fmt.Errorf("saving weekly restriction: %w", err)
 			}
 		default:
-			console.Warnf("Unknown schedule restriction type '%s' for rotation '%s', skipping...\n", restriction.Type, layer.ID)
+			diagnostics.Warnf(ctx, diagnostics.ResourceRotation, layer.ID, "Unknown schedule restriction type '%s' for rotation '%s', skipping...\n", restriction.Type, layer.ID)
 		}
 	}
 
@@ -593,6 +721,10 @@ func (p *PagerDuty) saveEscalationPolicyToDB(ctx context.Context, policy pagerdu
 		RepeatLimit: int64(policy.NumLoops),
 		Annotations: annotations,
 	}
+	// PagerDuty loops back to the first rule once the last rule's delay has elapsed.
+	if n := len(policy.EscalationRules); policy.NumLoops > 0 && n > 0 {
+		ep.RepeatInterval = sql.NullString{Valid: true, String: fmt.Sprintf("PT%dM", policy.EscalationRules[n-1].Delay)}
+	}
 
 	if err := store.UseQueries(ctx).InsertExtEscalationPolicy(ctx, ep); err != nil {
 		return fmt.Errorf("saving escalation policy %s (%s): %w", ep.Name, ep.ID, err)
@@ -650,7 +782,7 @@ func (p *PagerDuty) saveEscalationPolicyStepToDB(
 	}
 
 	for i, target := range rule.Targets {
-		if err := p.saveEscalationPolicyStepTargetToDB(ctx, step.ID, target, i); err != nil {
+		if err := p.saveEscalationPolicyStepTargetToDB(ctx, step, target, i); err != nil {
 			return fmt.Errorf("saving escalation policy step target: %w", err)
 		}
 	}
@@ -659,10 +791,11 @@ func (p *PagerDuty) saveEscalationPolicyStepToDB(
 
 func (p *PagerDuty) saveEscalationPolicyStepTargetToDB(
 	ctx context.Context,
-	stepID string,
+	step store.InsertExtEscalationPolicyStepParams,
 	pdTarget pagerduty.APIObject,
 	position int,
 ) error {
+	stepID := step.ID
 	t := store.InsertExtEscalationPolicyStepTargetParams{EscalationPolicyStepID: stepID}
 	switch pdTarget.Type {
 	case "user", "user_reference":
@@ -672,7 +805,13 @@ func (p *PagerDuty) saveEscalationPolicyStepTargetToDB(
 		t.TargetType = store.TARGET_TYPE_SCHEDULE
 		t.TargetID = pdTarget.ID
 	default:
-		console.Warnf("Unknown escalation policy step target type '%s' for step '%s', skipping...\n", pdTarget.Type, stepID)
+		diagnostics.SkipStepTarget(ctx, store.InsertExtEscalationPolicyStepTargetSkipParams{
+			EscalationPolicyID: step.EscalationPolicyID,
+			StepPosition:       step.Position,
+			TargetType:         pdTarget.Type,
+			TargetID:           pdTarget.ID,
+			Reason:             store.TARGET_SKIP_UNSUPPORTED_TYPE,
+		}, "Unknown escalation policy step target type '%s' for step '%s', skipping...\n", pdTarget.Type, stepID)
 		return nil
 	}
 	if err := store.UseQueries(ctx).InsertExtEscalationPolicyStepTarget(ctx, t); err != nil {
@@ -4,10 +4,13 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"regexp"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -274,6 +277,131 @@ func TestPagerDuty(t *testing.T) {
 		assertJSON(t, schedules)
 	})
 
+	t.Run("LoadSchedulesImportsOverrides", func(t *testing.T) {
+		t.Parallel()
+		ctx, pd := setup(t)
+		pd.(pager.OverrideImporter).SetOverrideWindow(30 * 24 * time.Hour)
+
+		if err := pd.UseTeamInterface("team"); err != nil {
+			t.Fatalf("error setting team interface: %s", err)
+		}
+		if err := pd.LoadUsers(ctx); err != nil {
+			t.Fatalf("error loading users: %s", err)
+		}
+		if err := pd.LoadTeams(ctx); err != nil {
+			t.Fatalf("error loading teams: %s", err)
+		}
+		if err := pd.LoadSchedules(ctx); err != nil {
+			t.Fatalf("error loading schedules: %s", err)
+		}
+
+		overrides, err := store.UseQueries(ctx).ListExtScheduleOverridesByExtScheduleID(ctx, "P3D7DLW")
+		if err != nil {
+			t.Fatalf("error loading overrides: %s", err)
+		}
+		assertJSON(t, overrides)
+	})
+
+	t.Run("LoadSchedulesRetriesRateLimitedRequests", func(t *testing.T) {
+		// The first request for each resource is rejected, as rate limited or unavailable, and
+		// requests are held for a little while, so that concurrent ones overlap.
+		var mu sync.Mutex
+		requested := map[string]bool{}
+		inFlight, maxInFlight := 0, 0
+		fixtures := pagerProviderHandler(t)
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			retry := requested[r.URL.String()]
+			requested[r.URL.String()] = true
+			inFlight++
+			maxInFlight = max(maxInFlight, inFlight)
+			mu.Unlock()
+			defer func() {
+				mu.Lock()
+				inFlight--
+				mu.Unlock()
+			}()
+
+			time.Sleep(20 * time.Millisecond)
+			switch {
+			case retry:
+				fixtures.ServeHTTP(w, r)
+			case strings.Contains(r.URL.Path, "/overrides"):
+				w.Header().Set("Retry-After", "0")
+				w.WriteHeader(http.StatusServiceUnavailable)
+			default:
+				w.Header().Set("Retry-After", "0")
+				w.WriteHeader(http.StatusTooManyRequests)
+			}
+		}))
+		t.Cleanup(ts.Close)
+
+		load := func(pd *pager.PagerDuty) context.Context {
+			ctx := withTestDB(t)
+			pd.SetNow(func() time.Time { return pinnedNow })
+			pd.SetOverrideWindow(30 * 24 * time.Hour)
+			if err := pd.UseTeamInterface("team"); err != nil {
+				t.Fatalf("error setting team interface: %s", err)
+			}
+			if err := pd.LoadUsers(ctx); err != nil {
+				t.Fatalf("error loading users: %s", err)
+			}
+			if err := pd.LoadTeams(ctx); err != nil {
+				t.Fatalf("error loading teams: %s", err)
+			}
+			if err := pd.LoadTeamMembers(ctx); err != nil {
+				t.Fatalf("error loading team members: %s", err)
+			}
+			if err := pd.LoadSchedules(ctx); err != nil {
+				t.Fatalf("error loading schedules: %s", err)
+			}
+			return ctx
+		}
+		dump := func(ctx context.Context) string {
+			q := store.UseQueries(ctx)
+			memberships, err := q.ListExtTeamMemberships(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			schedules, err := q.ListExtSchedulesV2(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			rotations, err := q.ListExtRotations(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			overrides, err := q.ListExtScheduleOverridesByExtScheduleID(ctx, "P3D7DLW")
+			if err != nil {
+				t.Fatal(err)
+			}
+			return fmt.Sprintf("%v\n%v\n%v\n%v", memberships, schedules, rotations, overrides)
+		}
+
+		limited := pager.NewPagerDutyWithURL("api-key-very-secret", ts.URL)
+		limited.SetConcurrency(3)
+		got := dump(load(limited))
+
+		want := dump(load(pager.NewPagerDutyWithURL("api-key-very-secret", pagerProviderHttpServer(t).URL)))
+		if got != want {
+			t.Errorf("expected retried requests to import the same data, got:\n%s\nwant:\n%s", got, want)
+		}
+		if maxInFlight < 2 || maxInFlight > 3 {
+			t.Errorf("expected between 2 and 3 requests in flight at once, got %d", maxInFlight)
+		}
+	})
+
+	t.Run("RenderOnCall", func(t *testing.T) {
+		t.Parallel()
+		ctx, pd := setup(t)
+
+		shifts, err := pd.(pager.OnCallRenderer).RenderOnCall(ctx, "P3D7DLW", pinnedNow, pinnedNow.AddDate(0, 0, 7))
+		if err != nil {
+			t.Fatalf("error rendering on-call: %s", err)
+		}
+		assertJSON(t, shifts)
+	})
+
 	t.Run("LoadSchedulesRecordsMemberSkips", func(t *testing.T) {
 		t.Parallel()
 		ctx, pd := setup(t)
@@ -0,0 +1,74 @@
+package pager_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/firehydrant/signals-migrator/pager"
+)
+
+func TestPreflight(t *testing.T) {
+	// Every provider is pointed at the same server through the provider API URL, which rejects the
+	// API key "wrong", however the provider sends it.
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username, _, _ := r.BasicAuth()
+		if strings.Contains(r.Header.Get("Authorization")+r.Header.Get("X-VO-Api-Key"), "wrong") || username == "wrong" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"message": "unauthorized"}`))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/users":
+			_, _ = w.Write([]byte(`{"users": []}`))
+		case "/v2/users/":
+			_, _ = w.Write([]byte(`{"data": []}`))
+		case "/api-public/v1/team":
+			_, _ = w.Write([]byte(`[]`))
+		case "/api/v1/users/":
+			_, _ = w.Write([]byte(`{"results": []}`))
+		case "/api/xm/1/people":
+			_, _ = w.Write([]byte(`{"data": []}`))
+		default:
+			t.Errorf("unexpected request to %s", r.URL)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(ts.Close)
+
+	for _, kind := range []string{"pagerduty", "opsgenie", "victorops", "grafanaoncall", "xmatters"} {
+		t.Run(kind, func(t *testing.T) {
+			ctx := context.Background()
+
+			p, err := pager.NewPager(ctx, kind, "api-key-very-secret", "app-id", ts.URL)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := p.(pager.Preflighter).Preflight(ctx); err != nil {
+				t.Errorf("expected preflight to pass, got %s", err)
+			}
+
+			p, err = pager.NewPager(ctx, kind, "wrong", "app-id", ts.URL)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := p.(pager.Preflighter).Preflight(ctx); !errors.Is(err, pager.ErrUnauthorized) {
+				t.Errorf("expected preflight to fail as unauthorized, got %v", err)
+			}
+		})
+	}
+}
+
+func TestNewPagerRequiresAPIURL(t *testing.T) {
+	for _, kind := range []string{"grafanaoncall", "xmatters"} {
+		t.Run(kind, func(t *testing.T) {
+			if _, err := pager.NewPager(context.Background(), kind, "api-key-very-secret", "https://example.com", ""); err == nil {
+				t.Errorf("expected %s to require the provider API URL", kind)
+			}
+		})
+	}
+}
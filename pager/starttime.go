@@ -0,0 +1,123 @@
+package pager
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/firehydrant/signals-migrator/diagnostics"
+	"github.com/firehydrant/signals-migrator/store"
+)
+
+// StartTimeWindow is how far in the past FireHydrant accepts the start time of a rotation with a
+// custom strategy.
+const StartTimeWindow = 30 * 24 * time.Hour
+
+// AdjustStartTimes moves the start time of every custom rotation which started more than
+// StartTimeWindow before now forward, so that FireHydrant accepts it.
+//
+// Start times are moved by whole cycles through the members of the rotation, so that whoever is on
+// call stays the same. When a cycle is longer than the window, they are moved by whole shifts
+// instead, and the members are rotated along so that the same member starts. When a single shift
+// is longer than the window too, the start time cannot be moved into it and is only warned about.
+func AdjustStartTimes(ctx context.Context, now time.Time) error {
+	q := store.UseQueries(ctx)
+	rotations, err := q.ListExtRotations(ctx)
+	if err != nil {
+		return fmt.Errorf("querying rotations: %w", err)
+	}
+
+	oldest := now.Add(-StartTimeWindow)
+	for _, r := range rotations {
+		if r.Strategy != "custom" || r.StartTime == "" {
+			continue
+		}
+		start, err := time.Parse(time.RFC3339, r.StartTime)
+		if err != nil {
+			diagnostics.Warnf(ctx, diagnostics.ResourceRotation, r.ID,
+				"Unable to parse start time '%s' of rotation '%s', leaving it as is.\n", r.StartTime, r.Name)
+			continue
+		}
+		if !start.Before(oldest) {
+			continue
+		}
+		shift, err := ParseShiftDuration(r.ShiftDuration)
+		if err != nil {
+			diagnostics.Warnf(ctx, diagnostics.ResourceRotation, r.ID,
+				"Unable to adjust start time of rotation '%s': %s.\n", r.Name, err)
+			continue
+		}
+		members, err := q.ListExtRotationMembers(ctx, r.ID)
+		if err != nil {
+			return fmt.Errorf("querying members of rotation '%s': %w", r.Name, err)
+		}
+
+		n := int64(max(1, len(members)))
+		shifts := int64(now.Sub(start)/(shift*time.Duration(n))) * n
+		if start.Add(time.Duration(shifts) * shift).Before(oldest) {
+			shifts = int64(now.Sub(start) / shift)
+		}
+		adjusted := start.Add(time.Duration(shifts) * shift)
+		if adjusted.Before(oldest) {
+			diagnostics.Warnf(ctx, diagnostics.ResourceRotation, r.ID,
+				"Unable to move start time %s of rotation '%s' within %d days of now, as its shifts last %s. FireHydrant will reject it.\n",
+				r.StartTime, r.Name, int(StartTimeWindow.Hours()/24), r.ShiftDuration)
+			continue
+		}
+		if err := q.UpdateExtRotationStartTime(ctx, store.UpdateExtRotationStartTimeParams{
+			ID:        r.ID,
+			StartTime: adjusted.Format(time.RFC3339),
+		}); err != nil {
+			return fmt.Errorf("updating start time of rotation '%s': %w", r.Name, err)
+		}
+
+		turn := shifts % n
+		if turn == 0 {
+			diagnostics.Infof(ctx, diagnostics.ResourceRotation, r.ID,
+				"Moved start time of rotation '%s' from %s to %s, %d shifts later.\n",
+				r.Name, r.StartTime, adjusted.Format(time.RFC3339), shifts)
+			continue
+		}
+		for i, m := range members {
+			if err := q.UpdateExtRotationMemberOrder(ctx, store.UpdateExtRotationMemberOrderParams{
+				RotationID:  r.ID,
+				UserID:      m.UserID,
+				MemberOrder: (int64(i) - turn + n) % n,
+			}); err != nil {
+				return fmt.Errorf("updating member order of rotation '%s': %w", r.Name, err)
+			}
+		}
+		diagnostics.Infof(ctx, diagnostics.ResourceRotation, r.ID,
+			"Moved start time of rotation '%s' from %s to %s, %d shifts later, starting with member %d of %d.\n",
+			r.Name, r.StartTime, adjusted.Format(time.RFC3339), shifts, turn+1, n)
+	}
+	return nil
+}
+
+var isoDurationRegexp = regexp.MustCompile(`^P(?:(\d+)W)?(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?)?$`)
+
+// ParseShiftDuration parses the ISO 8601 shift duration of a custom rotation, such as "PT36H" or
+// "P2W".
+func ParseShiftDuration(s string) (time.Duration, error) {
+	m := isoDurationRegexp.FindStringSubmatch(s)
+	if m == nil || s == "P" || s == "PT" {
+		return 0, fmt.Errorf("invalid shift duration '%s'", s)
+	}
+	var d time.Duration
+	for i, unit := range []time.Duration{7 * 24 * time.Hour, 24 * time.Hour, time.Hour, time.Minute, time.Second} {
+		if m[i+1] == "" {
+			continue
+		}
+		n, err := strconv.Atoi(m[i+1])
+		if err != nil {
+			return 0, fmt.Errorf("invalid shift duration '%s': %w", s, err)
+		}
+		d += time.Duration(n) * unit
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("invalid shift duration '%s'", s)
+	}
+	return d, nil
+}
@@ -0,0 +1,127 @@
+package pager_test
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/firehydrant/signals-migrator/diagnostics"
+	"github.com/firehydrant/signals-migrator/pager"
+	"github.com/firehydrant/signals-migrator/store"
+)
+
+func TestAdjustStartTimes(t *testing.T) {
+	ctx := diagnostics.WithCollector(withTestDB(t))
+	seed := `
+INSERT INTO ext_users VALUES('a','A','a@example.com',NULL,'');
+INSERT INTO ext_users VALUES('b','B','b@example.com',NULL,'');
+INSERT INTO ext_users VALUES('c','C','c@example.com',NULL,'');
+INSERT INTO ext_users VALUES('d','D','d@example.com',NULL,'');
+INSERT INTO ext_users VALUES('e','E','e@example.com',NULL,'');
+INSERT INTO ext_teams VALUES('team','Team','team',NULL,0,1,'');
+INSERT INTO ext_schedules_v2 VALUES('schedule','Schedule','','UTC','team','pagerduty','schedule');
+
+INSERT INTO ext_rotations VALUES('recent','schedule','Recent','','custom','PT12H','2024-03-20T00:00:00Z','','',0);
+INSERT INTO ext_rotations VALUES('short-cycle','schedule','Short cycle','','custom','PT12H','2024-01-01T00:00:00-05:00','','',1);
+INSERT INTO ext_rotations VALUES('long-cycle','schedule','Long cycle','','custom','P2W','2023-10-02T00:00:00Z','','',2);
+INSERT INTO ext_rotations VALUES('weekly','schedule','Weekly','','weekly','','','09:00:00','monday',3);
+INSERT INTO ext_rotations VALUES('long-shift','schedule','Long shift','','custom','P6W','2023-12-01T00:00:00Z','','',4);
+
+INSERT INTO ext_rotation_members VALUES('short-cycle','a',0);
+INSERT INTO ext_rotation_members VALUES('short-cycle','b',1);
+INSERT INTO ext_rotation_members VALUES('long-cycle','a',0);
+INSERT INTO ext_rotation_members VALUES('long-cycle','b',1);
+INSERT INTO ext_rotation_members VALUES('long-cycle','c',2);
+INSERT INTO ext_rotation_members VALUES('long-cycle','d',3);
+INSERT INTO ext_rotation_members VALUES('long-cycle','e',4);
+`
+	if _, err := store.FromContext(ctx).ExecContext(ctx, seed); err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Date(2024, 4, 1, 6, 0, 0, 0, time.UTC)
+	if err := pager.AdjustStartTimes(ctx, now); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]struct {
+		startTime string
+		members   []string
+	}{
+		"recent": {startTime: "2024-03-20T00:00:00Z"},
+		// 182 shifts of 12 hours, which is 91 whole cycles through both members.
+		"short-cycle": {startTime: "2024-04-01T00:00:00-05:00", members: []string{"a", "b"}},
+		// A cycle takes 70 days, so the start time is moved by 13 shifts of 2 weeks instead, and the
+		// 13th shift was d's.
+		"long-cycle": {startTime: "2024-04-01T00:00:00Z", members: []string{"d", "e", "a", "b", "c"}},
+		"weekly":     {startTime: ""},
+		// Two shifts of 6 weeks later is still 38 days ago, and a third is in the future.
+		"long-shift": {startTime: "2023-12-01T00:00:00Z"},
+	}
+	rotations, err := store.UseQueries(ctx).ListExtRotations(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range rotations {
+		if r.StartTime != want[r.ID].startTime {
+			t.Errorf("rotation %s: expected start time %q, got %q", r.ID, want[r.ID].startTime, r.StartTime)
+		}
+		members, err := store.UseQueries(ctx).ListExtRotationMembers(ctx, r.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := []string{}
+		for _, m := range members {
+			got = append(got, m.UserID)
+		}
+		if len(got) > 0 && !slices.Equal(got, want[r.ID].members) {
+			t.Errorf("rotation %s: expected members %v, got %v", r.ID, want[r.ID].members, got)
+		}
+	}
+
+	recorded := func(severity diagnostics.Severity) []string {
+		ids := []string{}
+		for _, e := range diagnostics.FromContext(ctx).Entries() {
+			if e.Severity == severity {
+				ids = append(ids, e.SourceID)
+			}
+		}
+		slices.Sort(ids)
+		return ids
+	}
+	if adjusted := recorded(diagnostics.SeverityInfo); !slices.Equal(adjusted, []string{"long-cycle", "short-cycle"}) {
+		t.Errorf("expected adjustments of long-cycle and short-cycle to be recorded, got %v", adjusted)
+	}
+	if warned := recorded(diagnostics.SeverityWarning); !slices.Equal(warned, []string{"long-shift"}) {
+		t.Errorf("expected a warning about long-shift, got %v", warned)
+	}
+
+	// Adjusted start times are within the window, so running it again changes nothing.
+	if err := pager.AdjustStartTimes(ctx, now); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(recorded(diagnostics.SeverityInfo)); n != 2 {
+		t.Errorf("expected no more adjustments, got %d entries", n)
+	}
+}
+
+func TestParseShiftDuration(t *testing.T) {
+	for s, want := range map[string]time.Duration{
+		"PT93600S": 26 * time.Hour,
+		"PT2H":     2 * time.Hour,
+		"P2W":      14 * 24 * time.Hour,
+		"P1DT12H":  36 * time.Hour,
+	} {
+		got, err := pager.ParseShiftDuration(s)
+		if err != nil {
+			t.Errorf("%s: %s", s, err)
+		} else if got != want {
+			t.Errorf("%s: expected %s, got %s", s, want, got)
+		}
+	}
+	for _, s := range []string{"", "P", "PT", "PT0S", "2h"} {
+		if _, err := pager.ParseShiftDuration(s); err == nil {
+			t.Errorf("%s: expected an error", s)
+		}
+	}
+}
@@ -2,16 +2,30 @@ package pager
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
 	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
-	"github.com/firehydrant/signals-migrator/console"
+	"github.com/firehydrant/signals-migrator/diagnostics"
 	"github.com/firehydrant/signals-migrator/store"
+	"github.com/gosimple/slug"
 	"github.com/victorops/go-victorops/victorops"
 )
 
 type VictorOps struct {
 	client *victorops.Client
+
+	// go-victorops does not cover rotations, so those endpoints are requested directly.
+	apiKey string
+	appID  string
+	url    string
 }
 
 func NewVictorOps(apiKey string, appId string) *VictorOps {
@@ -21,6 +35,9 @@ func NewVictorOps(apiKey string, appId string) *VictorOps {
 func NewVictorOpsWithURL(apiKey string, appId string, url string) *VictorOps {
 	return &VictorOps{
 		client: victorops.NewClient(appId, apiKey, url),
+		apiKey: apiKey,
+		appID:  appId,
+		url:    url,
 	}
 }
 
@@ -36,6 +53,15 @@ func (v *VictorOps) UseTeamInterface(string) error {
 	return nil
 }
 
+// Preflight lists the teams, which needs both the API ID and the API key.
+func (v *VictorOps) Preflight(ctx context.Context) error {
+	var teams []json.RawMessage
+	if err := v.get(ctx, "v1/team", &teams); err != nil {
+		return fmt.Errorf("querying victorops at %s: %w", v.url, err)
+	}
+	return nil
+}
+
 func (v *VictorOps) Teams(ctx context.Context) ([]store.ExtTeam, error) {
 	return store.UseQueries(ctx).ListExtTeams(ctx)
 }
@@ -139,14 +165,483 @@ func (v *VictorOps) LoadTeamMembers(ctx context.Context) error {
 	return nil
 }
 
+// voRotationGroup is a VictorOps rotation, which is made of one or more shifts.
+// Each rotation group is imported as a schedule, and each of its shifts as a rotation.
+type voRotationGroup struct {
+	Label  string    `json:"label"`
+	Slug   string    `json:"slug"`
+	Shifts []voShift `json:"shifts"`
+}
+
+type voShift struct {
+	Label    string `json:"label"`
+	Timezone string `json:"timezone"`
+	// Start is the handoff anchor of the shift, in milliseconds since epoch.
+	Start int64 `json:"start"`
+	// Duration is the number of days each member is on call before handing off.
+	Duration int `json:"duration"`
+	// ShiftType is one of "std" (24/7), "pho" (partial day) or "cstm" (multi-day).
+	ShiftType string `json:"shifttype"`
+
+	Mask  *voShiftMask `json:"mask"`
+	Mask2 *voShiftMask `json:"mask2"`
+	Mask3 *voShiftMask `json:"mask3"`
+
+	ShiftMembers []struct {
+		Username string `json:"username"`
+		Slot     int    `json:"slot"`
+	} `json:"shiftMembers"`
+}
+
+// voShiftMask describes when a shift is active: the time windows apply to every day marked true.
+// Multi-day shifts spread their window across mask (first day), mask2 (days in between) and mask3 (last day).
+type voShiftMask struct {
+	Day  map[string]bool `json:"day"`
+	Time []struct {
+		Start voTimeOfDay `json:"start"`
+		End   voTimeOfDay `json:"end"`
+	} `json:"time"`
+}
+
+type voTimeOfDay struct {
+	Hour   int `json:"hour"`
+	Minute int `json:"minute"`
+}
+
+func (t voTimeOfDay) minutes() int {
+	return t.Hour*60 + t.Minute
+}
+
+var voWeekdays = map[string]time.Weekday{
+	"su": time.Sunday,
+	"m":  time.Monday,
+	"t":  time.Tuesday,
+	"w":  time.Wednesday,
+	"th": time.Thursday,
+	"f":  time.Friday,
+	"sa": time.Saturday,
+}
+
+// get requests an endpoint of the VictorOps public API which isn't covered by go-victorops
+// and decodes the JSON response into out.
+func (v *VictorOps) get(ctx context.Context, endpoint string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, v.url+"/api-public/"+endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("composing request: %w", err)
+	}
+	req.Header.Set("X-VO-Api-Id", v.appID)
+	req.Header.Set("X-VO-Api-Key", v.apiKey)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return statusError(resp.StatusCode, body)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decoding response: %w", err)
+	}
+	return nil
+}
+
+func (v *VictorOps) rotationGroups(ctx context.Context, teamID string) ([]voRotationGroup, error) {
+	var resp struct {
+		RotationGroups []voRotationGroup `json:"rotationGroups"`
+	}
+	if err := v.get(ctx, "v1/teams/"+teamID+"/rotations", &resp); err != nil {
+		return nil, err
+	}
+	return resp.RotationGroups, nil
+}
+
+// voScheduleID returns the ID a rotation group is stored under. Rotation groups are identified by
+// their slug, which escalation policies also use to refer to them.
+func voScheduleID(teamID string, groupSlug string, label string) string {
+	if groupSlug != "" {
+		return groupSlug
+	}
+	return fmt.Sprintf("%s-%s", teamID, slug.Make(label))
+}
+
 func (v *VictorOps) LoadSchedules(ctx context.Context) error {
-	// TODO: implement
-	console.Warnf("victorops.LoadSchedules is not currently supported.")
+	q := store.UseQueries(ctx)
+	teams, err := q.ListTeams(ctx)
+	if err != nil {
+		return fmt.Errorf("loading teams: %w", err)
+	}
+	for _, team := range teams {
+		groups, err := v.rotationGroups(ctx, team.ID)
+		if err != nil {
+			return fmt.Errorf("querying victorops rotations for team '%s': %w", team.ID, err)
+		}
+		for _, group := range groups {
+			if err := v.saveRotationGroupToDB(ctx, team.ID, group); err != nil {
+				return fmt.Errorf("saving rotation '%s' to db: %w", group.Label, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (v *VictorOps) saveRotationGroupToDB(ctx context.Context, teamID string, group voRotationGroup) error {
+	// VictorOps sets the time zone per shift, while FireHydrant sets it per schedule.
+	// Use the first shift's time zone, which is what the VictorOps UI defaults new shifts to.
+	timezone := "UTC"
+	if len(group.Shifts) > 0 && group.Shifts[0].Timezone != "" {
+		timezone = group.Shifts[0].Timezone
+	}
+
+	scheduleID := voScheduleID(teamID, group.Slug, group.Label)
+	if err := store.UseQueries(ctx).InsertExtScheduleV2(ctx, store.InsertExtScheduleV2Params{
+		ID:               scheduleID,
+		Name:             group.Label,
+		Description:      "",
+		Timezone:         timezone,
+		TeamID:           teamID,
+		SourceSystem:     "victorops",
+		SourceScheduleID: scheduleID,
+	}); err != nil {
+		return fmt.Errorf("saving schedule: %w", err)
+	}
+
+	for i, shift := range group.Shifts {
+		if shift.Timezone != "" && shift.Timezone != timezone {
+			diagnostics.Warnf(ctx, diagnostics.ResourceSchedule, scheduleID, "Shift %q of rotation %q uses time zone %s, but will be imported as %s.\n", shift.Label, group.Label, shift.Timezone, timezone)
+		}
+		if err := v.saveShiftToDB(ctx, scheduleID, shift, i); err != nil {
+			return fmt.Errorf("saving shift to db: %w", err)
+		}
+	}
 	return nil
 }
 
+func (v *VictorOps) saveShiftToDB(ctx context.Context, scheduleID string, shift voShift, shiftOrder int) error {
+	q := store.UseQueries(ctx)
+	schedule, err := q.GetExtScheduleV2(ctx, scheduleID)
+	if err != nil {
+		return fmt.Errorf("getting schedule info: %w", err)
+	}
+
+	rotationName := shift.Label
+	if rotationName == "" {
+		rotationName = fmt.Sprintf("%srotation%d", schedule.Name, shiftOrder+1)
+	}
+
+	desc := fmt.Sprintf("%s (%s)", schedule.Description, rotationName)
+	desc = strings.TrimSpace(desc)
+
+	loc, err := time.LoadLocation(schedule.Timezone)
+	if err != nil {
+		diagnostics.Warnf(ctx, diagnostics.ResourceSchedule, scheduleID, "unable to parse location %s.  using UTC instead", schedule.Timezone)
+		loc = time.UTC
+	}
+	start := time.UnixMilli(shift.Start).In(loc)
+
+	days := shift.Duration
+	if days <= 0 {
+		diagnostics.Warnf(ctx, diagnostics.ResourceSchedule, scheduleID, "Shift %q of rotation %q has no handoff interval, assuming weekly.\n", rotationName, schedule.Name)
+		days = 7
+	}
+
+	rotationID := fmt.Sprintf("%s-%d", scheduleID, shiftOrder)
+	rotationParams := store.InsertExtRotationParams{
+		ID:            rotationID,
+		ScheduleID:    scheduleID,
+		Name:          rotationName,
+		Description:   desc,
+		StartTime:     start.Format(time.RFC3339),
+		HandoffTime:   start.Format(time.TimeOnly),
+		HandoffDay:    strings.ToLower(start.Weekday().String()),
+		RotationOrder: int64(shiftOrder),
+	}
+	rotationParams.Strategy, rotationParams.ShiftDuration = rotationStrategy(time.Duration(days) * 24 * time.Hour)
+
+	if err := q.InsertExtRotation(ctx, rotationParams); err != nil {
+		return fmt.Errorf("saving rotation: %w", err)
+	}
+
+	// ExtRotationMembers
+	// VictorOps orders members by their slot in the shift.
+	members := shift.ShiftMembers
+	sort.SliceStable(members, func(i, j int) bool { return members[i].Slot < members[j].Slot })
+	for i, member := range members {
+		if err := q.InsertExtRotationMember(ctx, store.InsertExtRotationMemberParams{
+			RotationID:  rotationID,
+			UserID:      member.Username,
+			MemberOrder: int64(i),
+		}); err != nil {
+			if strings.Contains(err.Error(), "FOREIGN KEY constraint") {
+				diagnostics.Warnf(ctx, diagnostics.ResourceRotation, rotationID, "User %s not found for rotation %s, skipping...\n", member.Username, rotationID)
+				_ = q.InsertExtRotationMemberSkip(ctx, store.InsertExtRotationMemberSkipParams{
+					RotationID: rotationID,
+					UserID:     member.Username,
+					UserEmail:  "",
+					Reason:     "missing_fh_user",
+				})
+			} else if strings.Contains(err.Error(), "UNIQUE constraint") {
+				diagnostics.Warnf(ctx, diagnostics.ResourceRotation, rotationID, "User %s already exists for rotation %s, skipping duplicate...\n", member.Username, rotationID)
+			} else {
+				return fmt.Errorf("saving rotation user: %w", err)
+			}
+		}
+	}
+
+	// ExtRotationRestrictions
+	for i, r := range voShiftRestrictions(shift) {
+		r.RotationID = rotationID
+		r.RestrictionIndex = strconv.Itoa(i)
+		if err := q.InsertExtRotationRestriction(ctx, r); err != nil {
+			return fmt.Errorf("saving shift restriction: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// voShiftRestrictions converts the masks of a shift into weekly restrictions.
+//
+// Rather than interpreting each shift type separately, every mask window is laid out on a minute-by-minute
+// map of the week and contiguous coverage is emitted as one restriction. This keeps partial-day windows
+// crossing midnight as a single restriction (like PagerDuty's daily restrictions), and stitches the three
+// masks of a multi-day shift back into one span, e.g. Monday 09:00 to Friday 17:00.
+// A shift which covers the whole week has no restrictions.
+func voShiftRestrictions(shift voShift) []store.InsertExtRotationRestrictionParams {
+	const minutesPerDay = 24 * 60
+	const minutesPerWeek = 7 * minutesPerDay
+
+	covered := make([]bool, minutesPerWeek)
+	for _, mask := range []*voShiftMask{shift.Mask, shift.Mask2, shift.Mask3} {
+		if mask == nil {
+			continue
+		}
+		for key, active := range mask.Day {
+			day, ok := voWeekdays[key]
+			if !active || !ok {
+				continue
+			}
+			for _, window := range mask.Time {
+				start := int(day)*minutesPerDay + window.Start.minutes()
+				length := window.End.minutes() - window.Start.minutes()
+				if length <= 0 {
+					// The window ends on the next day, or spans the full day when start equals end.
+					length += minutesPerDay
+				}
+				for m := range length {
+					covered[(start+m)%minutesPerWeek] = true
+				}
+			}
+		}
+	}
+
+	// Find a minute which isn't covered to start scanning from, so spans wrapping around
+	// Saturday midnight are not split in two. If there is none, the shift is always active.
+	offset := -1
+	for m, c := range covered {
+		if !c {
+			offset = m
+			break
+		}
+	}
+	if offset == -1 {
+		return nil
+	}
+
+	type span struct{ start, end int }
+	spans := []span{}
+	spanStart := -1
+	for i := 1; i <= minutesPerWeek; i++ {
+		m := offset + i
+		c := covered[m%minutesPerWeek]
+		if c && spanStart == -1 {
+			spanStart = m
+		}
+		if !c && spanStart != -1 {
+			spans = append(spans, span{spanStart % minutesPerWeek, m % minutesPerWeek})
+			spanStart = -1
+		}
+	}
+	// Scanning began at an arbitrary point of the week, so order spans from Sunday.
+	sort.Slice(spans, func(i, j int) bool { return spans[i].start < spans[j].start })
+
+	format := func(m int) (string, string) {
+		day := strings.ToLower(time.Weekday(m / minutesPerDay).String())
+		return day, fmt.Sprintf("%02d:%02d:00", (m%minutesPerDay)/60, m%60)
+	}
+	restrictions := make([]store.InsertExtRotationRestrictionParams, len(spans))
+	for i, s := range spans {
+		restrictions[i].StartDay, restrictions[i].StartTime = format(s.start)
+		restrictions[i].EndDay, restrictions[i].EndTime = format(s.end)
+	}
+	return restrictions
+}
+
 func (v *VictorOps) LoadEscalationPolicies(ctx context.Context) error {
-	// TODO: implement
-	console.Warnf("victorops.LoadEscalationPolicies is not currently supported.")
+	list, _, err := v.client.GetAllEscalationPolicies()
+	if err != nil {
+		return fmt.Errorf("querying victorops: %w", err)
+	}
+
+	for _, p := range list.Policies {
+		policy, _, err := v.client.GetEscalationPolicy(p.Policy.Slug)
+		if err != nil {
+			return fmt.Errorf("querying victorops escalation policy '%s': %w", p.Policy.Slug, err)
+		}
+		// The list response is the only one which reliably carries the owning team.
+		if policy.TeamID == "" {
+			policy.TeamID = p.Team.Slug
+		}
+		if policy.ID == "" {
+			policy.ID = p.Policy.Slug
+		}
+		if err := v.saveEscalationPolicyToDB(ctx, *policy, p.Team.Name); err != nil {
+			return fmt.Errorf("saving escalation policy to db: %w", err)
+		}
+	}
+	return nil
+}
+
+func (v *VictorOps) saveEscalationPolicyToDB(ctx context.Context, policy victorops.EscalationPolicy, teamName string) error {
+	annotations := fmt.Sprintf("[VictorOps] %s", policy.Name)
+	if teamName != "" {
+		annotations += fmt.Sprintf("\n[Team] %s", teamName)
+	}
+	if policy.IgnoreCustomPagingPolicies {
+		annotations += "\n[Ignores custom paging policies]"
+	}
+
+	ep := store.InsertExtEscalationPolicyParams{
+		ID:          policy.ID,
+		Name:        policy.Name,
+		Description: "",
+		TeamID:      sql.NullString{Valid: policy.TeamID != "", String: policy.TeamID},
+		Annotations: annotations,
+	}
+
+	// VictorOps can route to another escalation policy from any step, while FireHydrant can only
+	// hand off to another policy once all steps are exhausted. A routing entry is therefore only
+	// imported as handoff when it is the sole entry of the last step.
+	steps := policy.Steps
+	if n := len(steps); n > 0 && len(steps[n-1].Entries) == 1 && steps[n-1].Entries[0].ExecutionType == "policy_routing" {
+		if target := steps[n-1].Entries[0].TargetPolicy["policySlug"]; target != "" {
+			ep.HandoffTargetType = store.TARGET_TYPE_ESCALATION_POLICY
+			ep.HandoffTargetID = target
+			steps = steps[:n-1]
+		}
+	}
+
+	if err := store.UseQueries(ctx).InsertExtEscalationPolicy(ctx, ep); err != nil {
+		if sqlErr, ok := store.AsSQLError(err); ok && sqlErr.IsForeignKeyConstraint() {
+			diagnostics.Warnf(ctx, diagnostics.ResourceEscalationPolicy, ep.ID, "Escalation policy %q (%s) belongs to a team that isn't imported. Skipping...\n", ep.Name, ep.ID)
+			return nil
+		}
+		return fmt.Errorf("saving escalation policy %q (%s): %w", ep.Name, ep.ID, err)
+	}
+
+	// Timeouts are computed from all steps, so the last step still waits before handing off.
+	for i, step := range steps {
+		if err := v.saveEscalationPolicyStepToDB(ctx, policy, step, int64(i), voStepTimeout(ctx, policy, i)); err != nil {
+			return fmt.Errorf("saving escalation step to db: %w", err)
+		}
+	}
+	return nil
+}
+
+// voEntryTargetID identifies what an escalation policy step entry notifies, for entries which
+// have no FireHydrant equivalent.
+func voEntryTargetID(entry victorops.EscalationPolicyStepEntry) string {
+	for _, id := range []string{
+		entry.RotationGroup["slug"],
+		entry.Webhook["slug"],
+		entry.Email["address"],
+		entry.TargetPolicy["policySlug"],
+	} {
+		if id != "" {
+			return id
+		}
+	}
+	return ""
+}
+
+// voStepTimeout converts VictorOps step timeouts to FireHydrant's.
+// VictorOps declares how long to wait *before* running a step, since the previous one was run.
+// FireHydrant declares how long to wait *after* running a step, before moving on to the next one.
+// As such, the timeout of a FireHydrant step is the timeout of the following VictorOps step,
+// bounded to what FireHydrant supports (1 to 60 minutes). The last step has nothing to wait for
+// and defaults to 1 minute.
+func voStepTimeout(ctx context.Context, policy victorops.EscalationPolicy, position int) string {
+	if position+1 >= len(policy.Steps) {
+		return "PT1M"
+	}
+	next := policy.Steps[position+1].Timeout
+	if next > 60 {
+		diagnostics.Warnf(ctx, diagnostics.ResourceEscalationPolicy, policy.ID, "Actual delay time for step %d is %d minutes.  Locking to a max of 60 minutes.\n", position+1, next)
+	}
+	return fmt.Sprintf("PT%dM", int(math.Max(1, math.Min(float64(next), 60))))
+}
+
+func (v *VictorOps) saveEscalationPolicyStepToDB(
+	ctx context.Context,
+	policy victorops.EscalationPolicy,
+	s victorops.EscalationPolicySteps,
+	position int64,
+	timeout string,
+) error {
+	q := store.UseQueries(ctx)
+	stepID := fmt.Sprintf("%s-%d", policy.ID, position)
+	if err := q.InsertExtEscalationPolicyStep(ctx, store.InsertExtEscalationPolicyStepParams{
+		ID:                 stepID,
+		EscalationPolicyID: policy.ID,
+		Position:           position,
+		Timeout:            timeout,
+	}); err != nil {
+		return fmt.Errorf("saving escalation policy step: %w", err)
+	}
+
+	for _, entry := range s.Entries {
+		t := store.InsertExtEscalationPolicyStepTargetParams{EscalationPolicyStepID: stepID}
+
+		// Entries notifying the next or previous person in a rotation ("rotation_group_next",
+		// "rotation_group_previous"), webhooks and emails have no FireHydrant equivalent.
+		switch entry.ExecutionType {
+		case "user":
+			t.TargetType = store.TARGET_TYPE_USER
+			t.TargetID = entry.User["username"]
+		case "rotation_group":
+			t.TargetType = store.TARGET_TYPE_SCHEDULE
+			t.TargetID = voScheduleID(policy.TeamID, entry.RotationGroup["slug"], entry.RotationGroup["label"])
+			if _, err := q.GetExtScheduleV2(ctx, t.TargetID); err != nil {
+				diagnostics.SkipStepTarget(ctx, store.InsertExtEscalationPolicyStepTargetSkipParams{
+					EscalationPolicyID: policy.ID,
+					StepPosition:       position,
+					TargetType:         t.TargetType,
+					TargetID:           t.TargetID,
+					Reason:             store.TARGET_SKIP_NOT_IMPORTED,
+				}, "Rotation '%s' for policy '%s' step %d isn't imported, skipping...\n", t.TargetID, policy.ID, position)
+				continue
+			}
+		default:
+			diagnostics.SkipStepTarget(ctx, store.InsertExtEscalationPolicyStepTargetSkipParams{
+				EscalationPolicyID: policy.ID,
+				StepPosition:       position,
+				TargetType:         entry.ExecutionType,
+				TargetID:           voEntryTargetID(entry),
+				Reason:             store.TARGET_SKIP_UNSUPPORTED_TYPE,
+			}, "Escalation policy step target is '%s' for policy '%s' step %d, skipping...\n", entry.ExecutionType, policy.ID, position)
+			continue
+		}
+
+		if err := q.InsertExtEscalationPolicyStepTarget(ctx, t); err != nil {
+			if strings.Contains(err.Error(), "UNIQUE constraint") {
+				diagnostics.Warnf(ctx, diagnostics.ResourceEscalationPolicy, policy.ID, "Target %s already exists for step %s, skipping duplicate...\n", t.TargetID, stepID)
+				continue
+			}
+			return fmt.Errorf("saving escalation policy step target: %w", err)
+		}
+	}
 	return nil
 }
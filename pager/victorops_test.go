@@ -64,4 +64,191 @@ func TestVictorOps(t *testing.T) {
 		}
 		assertJSON(t, members)
 	})
+
+	t.Run("LoadSchedules", func(t *testing.T) {
+		t.Parallel()
+		ctx, vo := setup(t)
+
+		if err := vo.LoadUsers(ctx); err != nil {
+			t.Fatalf("error loading users: %s", err)
+		}
+		if err := vo.LoadTeams(ctx); err != nil {
+			t.Fatalf("error loading teams: %s", err)
+		}
+		if err := vo.LoadSchedules(ctx); err != nil {
+			t.Fatalf("error loading schedules: %s", err)
+		}
+		schedules, err := store.UseQueries(ctx).ListExtSchedulesV2(ctx)
+		if err != nil {
+			t.Fatalf("error loading schedules: %s", err)
+		}
+		t.Logf("found %d schedules", len(schedules))
+		assertJSON(t, schedules)
+	})
+
+	t.Run("LoadRotations", func(t *testing.T) {
+		t.Parallel()
+		ctx, vo := setup(t)
+
+		if err := vo.LoadUsers(ctx); err != nil {
+			t.Fatalf("error loading users: %s", err)
+		}
+		if err := vo.LoadTeams(ctx); err != nil {
+			t.Fatalf("error loading teams: %s", err)
+		}
+		if err := vo.LoadSchedules(ctx); err != nil {
+			t.Fatalf("error loading schedules: %s", err)
+		}
+
+		// Shifts map to rotations with their members (by slot) and restrictions, as follows:
+		// - "Weekly 24/7" is always active, so it has no restrictions.
+		// - "Business hours" is a partial day shift on weekdays.
+		// - "Overnight" crosses midnight, so it ends on the following day.
+		// - "Mon-Fri" is a multi-day shift whose three masks are merged into a single span.
+		type rotation struct {
+			store.ExtRotation
+			Members      []store.ExtRotationMember      `json:"members"`
+			Restrictions []store.ExtRotationRestriction `json:"restrictions"`
+		}
+		q := store.UseQueries(ctx)
+		extRotations, err := q.ListExtRotations(ctx)
+		if err != nil {
+			t.Fatalf("error loading rotations: %s", err)
+		}
+		rotations := []rotation{}
+		for _, r := range extRotations {
+			members, err := q.ListExtRotationMembers(ctx, r.ID)
+			if err != nil {
+				t.Fatalf("error loading rotation members: %s", err)
+			}
+			restrictions, err := q.ListExtRotationRestrictions(ctx, r.ID)
+			if err != nil {
+				t.Fatalf("error loading rotation restrictions: %s", err)
+			}
+			rotations = append(rotations, rotation{r, members, restrictions})
+		}
+		assertJSON(t, rotations)
+	})
+
+	t.Run("LoadSchedulesRecordsMemberSkips", func(t *testing.T) {
+		t.Parallel()
+		ctx, vo := setup(t)
+
+		// jessiejames is a member of the "Weekly 24/7" shift but is absent from the users fixture.
+		if err := vo.LoadUsers(ctx); err != nil {
+			t.Fatalf("error loading users: %s", err)
+		}
+		if err := vo.LoadTeams(ctx); err != nil {
+			t.Fatalf("error loading teams: %s", err)
+		}
+		if err := vo.LoadSchedules(ctx); err != nil {
+			t.Fatalf("error loading schedules: %s", err)
+		}
+
+		skips, err := store.UseQueries(ctx).ListRotationMemberSkips(ctx)
+		if err != nil {
+			t.Fatalf("error listing rotation member skips: %s", err)
+		}
+		if len(skips) != 1 {
+			t.Fatalf("expected 1 skip record, got %d", len(skips))
+		}
+		if skips[0].UserID != "jessiejames" {
+			t.Errorf("skip.UserID: expected %q, got %q", "jessiejames", skips[0].UserID)
+		}
+		if skips[0].RotationID != "rtg-primary-0" {
+			t.Errorf("skip.RotationID: expected %q, got %q", "rtg-primary-0", skips[0].RotationID)
+		}
+	})
+
+	t.Run("LoadEscalationPolicies", func(t *testing.T) {
+		t.Parallel()
+		ctx, vo := setup(t)
+
+		if err := vo.LoadUsers(ctx); err != nil {
+			t.Fatalf("error loading users: %s", err)
+		}
+		if err := vo.LoadTeams(ctx); err != nil {
+			t.Fatalf("error loading teams: %s", err)
+		}
+		if err := vo.LoadSchedules(ctx); err != nil {
+			t.Fatalf("error loading schedules: %s", err)
+		}
+		if err := vo.LoadEscalationPolicies(ctx); err != nil {
+			t.Fatalf("error loading escalation policies: %s", err)
+		}
+
+		// "Meowth Only" belongs to a team which isn't imported, and "Team Rocket Primary"
+		// hands off to "Fallback" through its last step.
+		policies, err := store.UseQueries(ctx).ListExtEscalationPolicies(ctx)
+		if err != nil {
+			t.Fatalf("error loading escalation policies: %s", err)
+		}
+		t.Logf("found %d escalation policies", len(policies))
+		assertJSON(t, policies)
+	})
+
+	t.Run("LoadEscalationPolicySteps", func(t *testing.T) {
+		t.Parallel()
+		ctx, vo := setup(t)
+
+		if err := vo.LoadUsers(ctx); err != nil {
+			t.Fatalf("error loading users: %s", err)
+		}
+		if err := vo.LoadTeams(ctx); err != nil {
+			t.Fatalf("error loading teams: %s", err)
+		}
+		if err := vo.LoadSchedules(ctx); err != nil {
+			t.Fatalf("error loading schedules: %s", err)
+		}
+		if err := vo.LoadEscalationPolicies(ctx); err != nil {
+			t.Fatalf("error loading escalation policies: %s", err)
+		}
+
+		// Timeouts are shifted by one step, and unsupported entries (webhooks, next in
+		// rotation) are dropped while their siblings are kept.
+		type step struct {
+			store.ExtEscalationPolicyStep
+			Targets []store.ExtEscalationPolicyStepTarget `json:"targets"`
+		}
+		q := store.UseQueries(ctx)
+		extSteps, err := q.ListExtEscalationPolicySteps(ctx, "pol-primary")
+		if err != nil {
+			t.Fatalf("error loading escalation policy steps: %s", err)
+		}
+		steps := []step{}
+		for _, s := range extSteps {
+			targets, err := q.ListExtEscalationPolicyStepTargets(ctx, s.ID)
+			if err != nil {
+				t.Fatalf("error loading targets for step %s: %s", s.ID, err)
+			}
+			steps = append(steps, step{s, targets})
+		}
+		assertJSON(t, steps)
+	})
+
+	t.Run("ListEscalationPolicyStepTargetSkips", func(t *testing.T) {
+		t.Parallel()
+		ctx, vo := setup(t)
+
+		if err := vo.LoadUsers(ctx); err != nil {
+			t.Fatalf("error loading users: %s", err)
+		}
+		if err := vo.LoadTeams(ctx); err != nil {
+			t.Fatalf("error loading teams: %s", err)
+		}
+		if err := vo.LoadSchedules(ctx); err != nil {
+			t.Fatalf("error loading schedules: %s", err)
+		}
+		if err := vo.LoadEscalationPolicies(ctx); err != nil {
+			t.Fatalf("error loading escalation policies: %s", err)
+		}
+
+		// Dropped entries are recorded against the step they belonged to, along with how many
+		// targets the step has left.
+		skips, err := store.UseQueries(ctx).ListEscalationPolicyStepTargetSkips(ctx)
+		if err != nil {
+			t.Fatalf("error listing escalation policy step target skips: %s", err)
+		}
+		assertJSON(t, skips)
+	})
 }
@@ -0,0 +1,729 @@
+package pager
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/firehydrant/signals-migrator/diagnostics"
+	"github.com/firehydrant/signals-migrator/store"
+	"github.com/gosimple/slug"
+)
+
+// XMatters imports from the xMatters REST API. Groups are imported as teams, the shifts of each
+// group as the rotations of a schedule, and the escalation of each shift as an escalation policy.
+type XMatters struct {
+	username string
+	password string
+	url      string
+	now      func() time.Time
+}
+
+// NewXMatters returns a provider for the xMatters instance at instanceURL, e.g. https://acme.xmatters.com.
+// apiKey is either an API key and its secret, or a username and password, joined by a colon.
+func NewXMatters(apiKey string, instanceURL string) *XMatters {
+	username, password, _ := strings.Cut(apiKey, ":")
+	return &XMatters{
+		username: username,
+		password: password,
+		url:      strings.TrimSuffix(instanceURL, "/"),
+		now:      time.Now,
+	}
+}
+
+// SetNow overrides the clock used to skip ended shifts, so tests can pin it.
+func (x *XMatters) SetNow(now func() time.Time) {
+	x.now = now
+}
+
+func (x *XMatters) Kind() string {
+	return "xMatters"
+}
+
+func (x *XMatters) TeamInterfaces() []string {
+	return []string{"group"}
+}
+
+func (x *XMatters) UseTeamInterface(string) error {
+	return nil
+}
+
+func (x *XMatters) Teams(ctx context.Context) ([]store.ExtTeam, error) {
+	return store.UseQueries(ctx).ListExtTeams(ctx)
+}
+
+// do requests rawURL with the credentials and returns the response body.
+func (x *XMatters) do(ctx context.Context, rawURL string) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("composing request: %w", err)
+	}
+	req.SetBasicAuth(x.username, x.password)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return nil, statusError(resp.StatusCode, body)
+	}
+	return resp.Body, nil
+}
+
+// Preflight requests a single person, rather than every page of them.
+func (x *XMatters) Preflight(ctx context.Context) error {
+	body, err := x.do(ctx, x.url+"/api/xm/1/people?limit=1")
+	if err != nil {
+		return fmt.Errorf("querying xmatters at %s: %w", x.url, err)
+	}
+	return body.Close()
+}
+
+// xmList requests every page of a list endpoint of the xMatters API. Pages are requested by offset,
+// which is appended to the raw query so that parameters such as "embed=members,rotation" are sent
+// as is.
+func xmList[T any](ctx context.Context, x *XMatters, endpoint string, query string) ([]T, error) {
+	var results []T
+	for {
+		q := query
+		if offset := len(results); offset > 0 {
+			if q != "" {
+				q += "&"
+			}
+			q += "offset=" + strconv.Itoa(offset)
+		}
+		u := x.url + "/api/xm/1/" + endpoint
+		if q != "" {
+			u += "?" + q
+		}
+
+		body, err := x.do(ctx, u)
+		if err != nil {
+			return nil, fmt.Errorf("querying xmatters %s: %w", endpoint, err)
+		}
+		var page struct {
+			Count int `json:"count"`
+			Total int `json:"total"`
+			Data  []T `json:"data"`
+			Links struct {
+				Next string `json:"next"`
+			} `json:"links"`
+		}
+		err = json.NewDecoder(body).Decode(&page)
+		body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("decoding xmatters %s: %w", endpoint, err)
+		}
+		results = append(results, page.Data...)
+		if page.Links.Next == "" || len(page.Data) == 0 || len(results) >= page.Total {
+			return results, nil
+		}
+	}
+}
+
+// xmRecipient is a person, group or device referred to by group members and shift members.
+type xmRecipient struct {
+	ID            string `json:"id"`
+	TargetName    string `json:"targetName"`
+	RecipientType string `json:"recipientType"`
+}
+
+func (x *XMatters) LoadUsers(ctx context.Context) error {
+	type person struct {
+		ID         string `json:"id"`
+		TargetName string `json:"targetName"`
+		FirstName  string `json:"firstName"`
+		LastName   string `json:"lastName"`
+		Status     string `json:"status"`
+		Devices    struct {
+			Data []struct {
+				DeviceType   string `json:"deviceType"`
+				EmailAddress string `json:"emailAddress"`
+			} `json:"data"`
+		} `json:"devices"`
+	}
+	people, err := xmList[person](ctx, x, "people", "embed=devices")
+	if err != nil {
+		return err
+	}
+	for _, p := range people {
+		// xMatters keeps email addresses as notification devices. Usernames are often email
+		// addresses as well, which are used for people without an email device.
+		email := ""
+		for _, d := range p.Devices.Data {
+			if d.DeviceType == "EMAIL" && d.EmailAddress != "" {
+				email = d.EmailAddress
+				break
+			}
+		}
+		if email == "" && strings.Contains(p.TargetName, "@") {
+			email = p.TargetName
+		}
+
+		name := strings.TrimSpace(p.FirstName + " " + p.LastName)
+		if name == "" {
+			name = p.TargetName
+		}
+		annotations := fmt.Sprintf("[xMatters] %s", p.TargetName)
+		if p.Status != "" && p.Status != "ACTIVE" {
+			annotations += fmt.Sprintf(" [%s]", p.Status)
+		}
+		if err := store.UseQueries(ctx).InsertExtUser(ctx, store.InsertExtUserParams{
+			ID:          p.ID,
+			Name:        name,
+			Email:       email,
+			Annotations: annotations,
+		}); err != nil {
+			return fmt.Errorf("saving user to db: %w", err)
+		}
+	}
+	return nil
+}
+
+func (x *XMatters) LoadTeams(ctx context.Context) error {
+	type group struct {
+		ID          string `json:"id"`
+		TargetName  string `json:"targetName"`
+		Description string `json:"description"`
+	}
+	groups, err := xmList[group](ctx, x, "groups", "")
+	if err != nil {
+		return err
+	}
+	for _, g := range groups {
+		annotations := fmt.Sprintf("[xMatters] %s", g.TargetName)
+		if g.Description != "" {
+			annotations += "\n" + g.Description
+		}
+		if err := store.UseQueries(ctx).InsertExtTeam(ctx, store.InsertExtTeamParams{
+			ID:          g.ID,
+			Name:        g.TargetName,
+			Slug:        slug.Make(g.TargetName),
+			Annotations: annotations,
+		}); err != nil {
+			return fmt.Errorf("saving team to db: %w", err)
+		}
+	}
+	return nil
+}
+
+func (x *XMatters) LoadTeamMembers(ctx context.Context) error {
+	type member struct {
+		Member xmRecipient `json:"member"`
+	}
+	q := store.UseQueries(ctx)
+	teams, err := q.ListTeams(ctx)
+	if err != nil {
+		return fmt.Errorf("loading teams: %w", err)
+	}
+	for _, team := range teams {
+		members, err := xmList[member](ctx, x, "groups/"+team.ID+"/members", "")
+		if err != nil {
+			return err
+		}
+		// Groups may also have devices and other groups as members, which have no equivalent.
+		for _, m := range members {
+			if m.Member.RecipientType != "PERSON" {
+				continue
+			}
+			if err := q.InsertExtMembership(ctx, store.InsertExtMembershipParams{
+				TeamID: team.ID,
+				UserID: m.Member.ID,
+			}); err != nil {
+				return fmt.Errorf("saving team member to db: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+type xmShift struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// Start and End are the bounds of the first occurrence of the shift.
+	Start      string `json:"start"`
+	End        string `json:"end"`
+	Timezone   string `json:"timezone"`
+	Recurrence *struct {
+		// Frequency is one of "ONCE", "DAILY", "WEEKLY", "MONTHLY" or "YEARLY".
+		Frequency   string   `json:"frequency"`
+		RepeatEvery int      `json:"repeatEvery"`
+		OnDays      []string `json:"onDays"`
+		End         struct {
+			// EndBy is one of "NEVER", "DATE" or "NUMBER_OF_OCCURRENCES".
+			EndBy string `json:"endBy"`
+			Date  string `json:"date"`
+		} `json:"end"`
+	} `json:"recurrence"`
+	Rotation *struct {
+		// Type is "NONE" for shifts whose members don't take turns.
+		Type             string `json:"type"`
+		Interval         int    `json:"interval"`
+		IntervalUnit     string `json:"intervalUnit"`
+		NextRotationTime string `json:"nextRotationTime"`
+		NumberOfUsers    int    `json:"numberOfUsers"`
+	} `json:"rotation"`
+	Members struct {
+		Data []xmShiftMember `json:"data"`
+	} `json:"members"`
+}
+
+// xmShiftMember is a member of a shift. Members are notified in order of position, each of them
+// after waiting Delay minutes since the previous one was notified.
+type xmShiftMember struct {
+	Position   int         `json:"position"`
+	Delay      int         `json:"delay"`
+	InRotation bool        `json:"inRotation"`
+	Recipient  xmRecipient `json:"recipient"`
+}
+
+var xmWeekdays = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+func (s xmShift) rotating() bool {
+	return s.Rotation != nil && s.Rotation.Type != "" && s.Rotation.Type != "NONE"
+}
+
+// members returns the members of the shift sorted by position.
+func (s xmShift) members() []xmShiftMember {
+	members := append([]xmShiftMember(nil), s.Members.Data...)
+	sort.SliceStable(members, func(i, j int) bool { return members[i].Position < members[j].Position })
+	return members
+}
+
+// onCall returns the members who are imported as the shift's rotation. When the shift rotates, those
+// are the people taking turns, in their current order. Otherwise, the first person notified is the
+// one on call, and everyone else is only reached through escalation.
+func (s xmShift) onCall() []xmShiftMember {
+	onCall := []xmShiftMember{}
+	for _, m := range s.members() {
+		if m.Recipient.RecipientType != "PERSON" {
+			continue
+		}
+		if !s.rotating() {
+			return []xmShiftMember{m}
+		}
+		if m.InRotation {
+			onCall = append(onCall, m)
+		}
+	}
+	return onCall
+}
+
+// turnLength is how long each member is on call before the shift rotates to the next one.
+func (s xmShift) turnLength() (time.Duration, error) {
+	if !s.rotating() {
+		return 7 * 24 * time.Hour, nil
+	}
+	interval := max(s.Rotation.Interval, 1)
+	switch strings.TrimSuffix(strings.ToUpper(s.Rotation.IntervalUnit), "S") {
+	case "HOUR":
+		return time.Duration(interval) * time.Hour, nil
+	case "DAY":
+		return time.Duration(interval) * 24 * time.Hour, nil
+	case "WEEK":
+		return time.Duration(interval) * 7 * 24 * time.Hour, nil
+	default:
+		return 0, fmt.Errorf("unsupported rotation interval unit '%s'", s.Rotation.IntervalUnit)
+	}
+}
+
+func (x *XMatters) shifts(ctx context.Context, groupID string) ([]xmShift, error) {
+	return xmList[xmShift](ctx, x, "groups/"+groupID+"/shifts", "embed=members,rotation")
+}
+
+// LoadSchedules imports the shifts of each selected group as a schedule, with a rotation per shift.
+func (x *XMatters) LoadSchedules(ctx context.Context) error {
+	q := store.UseQueries(ctx)
+	teams, err := q.ListTeams(ctx)
+	if err != nil {
+		return fmt.Errorf("loading teams: %w", err)
+	}
+	for _, team := range teams {
+		shifts, err := x.shifts(ctx, team.ID)
+		if err != nil {
+			return err
+		}
+		if len(shifts) == 0 {
+			continue
+		}
+		if err := x.saveScheduleToDB(ctx, team, shifts); err != nil {
+			return fmt.Errorf("saving schedule of group '%s' to db: %w", team.Name, err)
+		}
+	}
+	return nil
+}
+
+func (x *XMatters) saveScheduleToDB(ctx context.Context, team store.LinkedTeam, shifts []xmShift) error {
+	// xMatters sets the time zone per shift, while FireHydrant sets it per schedule.
+	timezone := "UTC"
+	if shifts[0].Timezone != "" {
+		timezone = shifts[0].Timezone
+	}
+	for _, s := range shifts[1:] {
+		if s.Timezone != "" && s.Timezone != timezone {
+			diagnostics.Warnf(ctx, diagnostics.ResourceSchedule, team.ID, "Shift %q of group %q is in %s, while the schedule is in %s. Restrictions are converted to %s.\n", s.Name, team.Name, s.Timezone, timezone, timezone)
+		}
+	}
+
+	if err := store.UseQueries(ctx).InsertExtScheduleV2(ctx, store.InsertExtScheduleV2Params{
+		ID:               team.ID,
+		Name:             team.Name,
+		Description:      "",
+		Timezone:         timezone,
+		TeamID:           team.ID,
+		SourceSystem:     "xmatters",
+		SourceScheduleID: team.ID,
+	}); err != nil {
+		return fmt.Errorf("saving schedule: %w", err)
+	}
+
+	rotationOrder := 0
+	for _, shift := range shifts {
+		saved, err := x.saveShiftToDB(ctx, team.ID, shift, rotationOrder)
+		if err != nil {
+			return fmt.Errorf("saving shift '%s': %w", shift.Name, err)
+		}
+		if saved {
+			rotationOrder++
+		}
+	}
+	return nil
+}
+
+// shiftEnded reports whether the recurrence of the shift ended before now.
+func (x *XMatters) shiftEnded(shift xmShift) bool {
+	if shift.Recurrence == nil || shift.Recurrence.End.EndBy != "DATE" {
+		return false
+	}
+	end, err := time.Parse(time.RFC3339, shift.Recurrence.End.Date)
+	return err == nil && end.Before(x.now())
+}
+
+// saveShiftToDB saves a shift as a rotation of the schedule and reports whether it was saved.
+func (x *XMatters) saveShiftToDB(ctx context.Context, scheduleID string, shift xmShift, rotationOrder int) (bool, error) {
+	q := store.UseQueries(ctx)
+	schedule, err := q.GetExtScheduleV2(ctx, scheduleID)
+	if err != nil {
+		return false, fmt.Errorf("getting schedule info: %w", err)
+	}
+	loc, err := time.LoadLocation(schedule.Timezone)
+	if err != nil {
+		diagnostics.Warnf(ctx, diagnostics.ResourceSchedule, scheduleID, "unable to parse location %s.  using UTC instead", schedule.Timezone)
+		loc = time.UTC
+	}
+
+	if shift.Recurrence == nil || shift.Recurrence.Frequency == "" || shift.Recurrence.Frequency == "ONCE" {
+		diagnostics.Warnf(ctx, diagnostics.ResourceRotation, shift.ID, "Shift %q of schedule %q doesn't recur, skipping...\n", shift.Name, schedule.Name)
+		return false, nil
+	}
+	if x.shiftEnded(shift) {
+		diagnostics.Infof(ctx, diagnostics.ResourceRotation, shift.ID, "Shift %q of schedule %q ended on %s, skipping...\n", shift.Name, schedule.Name, shift.Recurrence.End.Date)
+		return false, nil
+	}
+	restrictions, err := x.shiftRestrictions(ctx, shift, loc)
+	if err != nil {
+		diagnostics.Warnf(ctx, diagnostics.ResourceRotation, shift.ID, "Shift %q of schedule %q can't be imported: %s. Skipping...\n", shift.Name, schedule.Name, err.Error())
+		return false, nil
+	}
+	turnLength, err := shift.turnLength()
+	if err != nil {
+		diagnostics.Warnf(ctx, diagnostics.ResourceRotation, shift.ID, "Shift %q of schedule %q can't be imported: %s. Skipping...\n", shift.Name, schedule.Name, err.Error())
+		return false, nil
+	}
+	onCall := shift.onCall()
+	if len(onCall) == 0 {
+		diagnostics.Warnf(ctx, diagnostics.ResourceRotation, shift.ID, "Shift %q of schedule %q has nobody on call, skipping...\n", shift.Name, schedule.Name)
+		return false, nil
+	}
+	if shift.rotating() && shift.Rotation.NumberOfUsers > 1 {
+		diagnostics.Warnf(ctx, diagnostics.ResourceRotation, shift.ID, "Shift %q of schedule %q has %d people on call at a time, which is imported as one.\n", shift.Name, schedule.Name, shift.Rotation.NumberOfUsers)
+	}
+
+	// xMatters lists the members of a rotating shift in their current order, so the first of them
+	// is on call until the next rotation. Shifts which don't rotate are anchored at their first occurrence.
+	start, err := time.Parse(time.RFC3339, shift.Start)
+	if err != nil {
+		return false, fmt.Errorf("parsing start '%s': %w", shift.Start, err)
+	}
+	if shift.rotating() && shift.Rotation.NextRotationTime != "" {
+		next, err := time.Parse(time.RFC3339, shift.Rotation.NextRotationTime)
+		if err != nil {
+			return false, fmt.Errorf("parsing next rotation time '%s': %w", shift.Rotation.NextRotationTime, err)
+		}
+		start = next.Add(-turnLength)
+	}
+	start = start.In(loc)
+
+	name := shift.Name
+	if name == "" {
+		name = fmt.Sprintf("%srotation%d", schedule.Name, rotationOrder+1)
+	}
+	rotationParams := store.InsertExtRotationParams{
+		ID:            shift.ID,
+		ScheduleID:    scheduleID,
+		Name:          name,
+		Description:   name,
+		StartTime:     start.Format(time.RFC3339),
+		HandoffTime:   start.Format(time.TimeOnly),
+		HandoffDay:    strings.ToLower(start.Weekday().String()),
+		RotationOrder: int64(rotationOrder),
+	}
+	rotationParams.Strategy, rotationParams.ShiftDuration = rotationStrategy(turnLength)
+	if err := q.InsertExtRotation(ctx, rotationParams); err != nil {
+		return false, fmt.Errorf("saving rotation: %w", err)
+	}
+
+	for i, m := range onCall {
+		if err := q.InsertExtRotationMember(ctx, store.InsertExtRotationMemberParams{
+			RotationID:  shift.ID,
+			UserID:      m.Recipient.ID,
+			MemberOrder: int64(i),
+		}); err != nil {
+			if strings.Contains(err.Error(), "FOREIGN KEY constraint") {
+				diagnostics.Warnf(ctx, diagnostics.ResourceRotation, shift.ID, "User %s not found for rotation %s, skipping...\n", m.Recipient.TargetName, shift.ID)
+				_ = q.InsertExtRotationMemberSkip(ctx, store.InsertExtRotationMemberSkipParams{
+					RotationID: shift.ID,
+					UserID:     m.Recipient.ID,
+					UserEmail:  "",
+					Reason:     "missing_fh_user",
+				})
+			} else if strings.Contains(err.Error(), "UNIQUE constraint") {
+				diagnostics.Warnf(ctx, diagnostics.ResourceRotation, shift.ID, "User %s already exists for rotation %s, skipping duplicate...\n", m.Recipient.TargetName, shift.ID)
+			} else {
+				return false, fmt.Errorf("saving rotation user: %w", err)
+			}
+		}
+	}
+
+	for i, r := range restrictions {
+		r.RotationID = shift.ID
+		r.RestrictionIndex = strconv.Itoa(i)
+		if err := q.InsertExtRotationRestriction(ctx, r); err != nil {
+			return false, fmt.Errorf("saving shift restriction: %w", err)
+		}
+	}
+	return true, nil
+}
+
+// shiftRestrictions returns the weekly restrictions of a shift which only covers part of the week,
+// e.g. weekdays from 09:00 to 17:00, converted to loc.
+func (x *XMatters) shiftRestrictions(ctx context.Context, shift xmShift, loc *time.Location) ([]store.InsertExtRotationRestrictionParams, error) {
+	shiftLoc := loc
+	if shift.Timezone != "" {
+		l, err := time.LoadLocation(shift.Timezone)
+		if err != nil {
+			return nil, fmt.Errorf("loading time zone '%s': %w", shift.Timezone, err)
+		}
+		shiftLoc = l
+	}
+	start, err := time.Parse(time.RFC3339, shift.Start)
+	if err != nil {
+		return nil, fmt.Errorf("parsing start '%s': %w", shift.Start, err)
+	}
+	end, err := time.Parse(time.RFC3339, shift.End)
+	if err != nil {
+		return nil, fmt.Errorf("parsing end '%s': %w", shift.End, err)
+	}
+	start = start.In(shiftLoc)
+	duration := end.Sub(start)
+	if duration <= 0 {
+		return nil, fmt.Errorf("shift ends before it starts")
+	}
+
+	r := shift.Recurrence
+	if r.RepeatEvery > 1 {
+		diagnostics.Warnf(ctx, diagnostics.ResourceRotation, shift.ID, "Shift %q repeats every %d %s periods, which is imported as repeating every period.\n", shift.Name, r.RepeatEvery, strings.ToLower(r.Frequency))
+	}
+	days := []time.Weekday{}
+	for _, d := range r.OnDays {
+		day, ok := xmWeekdays[strings.ToUpper(d)]
+		if !ok {
+			return nil, fmt.Errorf("unknown day of week '%s'", d)
+		}
+		days = append(days, day)
+	}
+	switch r.Frequency {
+	case "DAILY":
+		if len(days) == 0 {
+			days = []time.Weekday{time.Sunday, time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday}
+		}
+	case "WEEKLY":
+		if len(days) == 0 {
+			days = []time.Weekday{start.Weekday()}
+		}
+	default:
+		return nil, fmt.Errorf("unsupported recurrence frequency '%s'", r.Frequency)
+	}
+
+	if duration >= 7*24*time.Hour || (len(days) == 7 && duration >= 24*time.Hour) {
+		return nil, nil
+	}
+	return weeklyRestrictions(start, duration, days, loc), nil
+}
+
+// LoadEscalationPolicies imports the escalation of each shift of the selected groups as an
+// escalation policy of the group's team.
+func (x *XMatters) LoadEscalationPolicies(ctx context.Context) error {
+	q := store.UseQueries(ctx)
+	teams, err := q.ListTeams(ctx)
+	if err != nil {
+		return fmt.Errorf("loading teams: %w", err)
+	}
+	for _, team := range teams {
+		shifts, err := x.shifts(ctx, team.ID)
+		if err != nil {
+			return err
+		}
+		for _, shift := range shifts {
+			if x.shiftEnded(shift) {
+				continue
+			}
+			if err := x.saveEscalationPolicyToDB(ctx, team, shift); err != nil {
+				return fmt.Errorf("saving escalation policy to db: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// saveEscalationPolicyToDB saves the escalation of a shift. xMatters notifies shift members in order,
+// each after a delay since the previous one, so members without a delay join the step before them and
+// every delay becomes the timeout of the previous step. Members who are on call in the shift's rotation
+// are notified through the group's schedule instead.
+func (x *XMatters) saveEscalationPolicyToDB(ctx context.Context, team store.LinkedTeam, shift xmShift) error {
+	q := store.UseQueries(ctx)
+	name := fmt.Sprintf("%s - %s", team.Name, shift.Name)
+	ep := store.InsertExtEscalationPolicyParams{
+		ID:          shift.ID,
+		Name:        name,
+		Description: "",
+		TeamID:      sql.NullString{Valid: true, String: team.ID},
+		Annotations: fmt.Sprintf("[xMatters] %s\n[Group] %s", shift.Name, team.Name),
+	}
+
+	onCall := map[string]bool{}
+	if _, err := q.GetExtRotation(ctx, shift.ID); err == nil {
+		for _, m := range shift.onCall() {
+			onCall[m.Recipient.ID] = true
+		}
+	}
+
+	type step struct {
+		targets []store.InsertExtEscalationPolicyStepTargetParams
+		// delay is how long to wait after this step, in minutes.
+		delay int
+	}
+	steps := []step{}
+	notifiedSchedule := false
+	// delay accumulates the delays of skipped members, which still delay everyone after them.
+	delay := 0
+	// A skipped member belongs to the step it would have been added to.
+	skipTarget := func(m xmShiftMember, reason string) store.InsertExtEscalationPolicyStepTargetSkipParams {
+		position := len(steps) - 1
+		if len(steps) == 0 || delay > 0 {
+			position = len(steps)
+		}
+		return store.InsertExtEscalationPolicyStepTargetSkipParams{
+			EscalationPolicyID: shift.ID,
+			StepPosition:       int64(position),
+			TargetType:         strings.ToLower(m.Recipient.RecipientType),
+			TargetID:           m.Recipient.ID,
+			Reason:             reason,
+		}
+	}
+	for _, m := range shift.members() {
+		delay += m.Delay
+		t := store.InsertExtEscalationPolicyStepTargetParams{}
+		switch {
+		case onCall[m.Recipient.ID]:
+			if notifiedSchedule {
+				continue
+			}
+			notifiedSchedule = true
+			t.TargetType = store.TARGET_TYPE_SCHEDULE
+			t.TargetID = team.ID
+		case m.Recipient.RecipientType == "PERSON":
+			t.TargetType = store.TARGET_TYPE_USER
+			t.TargetID = m.Recipient.ID
+		case m.Recipient.RecipientType == "GROUP":
+			if _, err := q.GetExtScheduleV2(ctx, m.Recipient.ID); err != nil {
+				diagnostics.SkipStepTarget(ctx, skipTarget(m, store.TARGET_SKIP_NOT_IMPORTED),
+					"Group '%s' for escalation policy '%s' isn't imported as a schedule, skipping...\n", m.Recipient.TargetName, name)
+				continue
+			}
+			t.TargetType = store.TARGET_TYPE_SCHEDULE
+			t.TargetID = m.Recipient.ID
+		default:
+			diagnostics.SkipStepTarget(ctx, skipTarget(m, store.TARGET_SKIP_UNSUPPORTED_TYPE),
+				"Escalation policy '%s' notifies %s '%s', skipping...\n", name, strings.ToLower(m.Recipient.RecipientType), m.Recipient.TargetName)
+			continue
+		}
+
+		if len(steps) == 0 || delay > 0 {
+			if len(steps) > 0 {
+				steps[len(steps)-1].delay = delay
+			}
+			steps = append(steps, step{})
+		}
+		delay = 0
+		last := &steps[len(steps)-1]
+		last.targets = append(last.targets, t)
+	}
+	if len(steps) == 0 {
+		diagnostics.Warnf(ctx, diagnostics.ResourceEscalationPolicy, shift.ID, "Escalation policy '%s' notifies nobody, skipping...\n", name)
+		return nil
+	}
+
+	if err := q.InsertExtEscalationPolicy(ctx, ep); err != nil {
+		if sqlErr, ok := store.AsSQLError(err); ok && sqlErr.IsForeignKeyConstraint() {
+			diagnostics.Warnf(ctx, diagnostics.ResourceEscalationPolicy, ep.ID, "Escalation policy %q (%s) belongs to a team that isn't imported. Skipping...\n", ep.Name, ep.ID)
+			return nil
+		}
+		return fmt.Errorf("saving escalation policy %q (%s): %w", ep.Name, ep.ID, err)
+	}
+
+	for position, s := range steps {
+		stepID := fmt.Sprintf("%s-%d", shift.ID, position)
+		// FireHydrant steps time out after 1 to 60 minutes.
+		if s.delay > 60 {
+			diagnostics.Warnf(ctx, diagnostics.ResourceEscalationPolicy, shift.ID, "Actual delay time for step %d is %d minutes.  Locking to a max of 60 minutes.\n", position, s.delay)
+		}
+		if err := q.InsertExtEscalationPolicyStep(ctx, store.InsertExtEscalationPolicyStepParams{
+			ID:                 stepID,
+			EscalationPolicyID: shift.ID,
+			Position:           int64(position),
+			Timeout:            fmt.Sprintf("PT%dM", max(1, min(s.delay, 60))),
+		}); err != nil {
+			return fmt.Errorf("saving escalation policy step: %w", err)
+		}
+		for _, t := range s.targets {
+			t.EscalationPolicyStepID = stepID
+			if err := q.InsertExtEscalationPolicyStepTarget(ctx, t); err != nil {
+				if strings.Contains(err.Error(), "UNIQUE constraint") {
+					diagnostics.Warnf(ctx, diagnostics.ResourceEscalationPolicy, shift.ID, "Target %s already exists for step %s, skipping duplicate...\n", t.TargetID, stepID)
+					continue
+				}
+				return fmt.Errorf("saving escalation policy step target: %w", err)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,186 @@
+package pager_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/firehydrant/signals-migrator/pager"
+	"github.com/firehydrant/signals-migrator/store"
+)
+
+func TestXMatters(t *testing.T) {
+	// Shifts which ended are skipped relative to this date.
+	pinnedNow := time.Date(2024, 4, 12, 0, 0, 0, 0, time.UTC)
+
+	// Avoid sharing setup code between tests to prevent test pollution in parallel execution.
+	setup := func(t *testing.T) (context.Context, pager.Pager) {
+		ctx := withTestDB(t)
+		ts := pagerProviderHttpServer(t)
+		x := pager.NewXMatters("api-key:very-secret", ts.URL)
+		x.SetNow(func() time.Time { return pinnedNow })
+		return ctx, x
+	}
+
+	loadSchedules := func(t *testing.T, ctx context.Context, x pager.Pager) {
+		t.Helper()
+		if err := x.LoadUsers(ctx); err != nil {
+			t.Fatalf("error loading users: %s", err)
+		}
+		if err := x.LoadTeams(ctx); err != nil {
+			t.Fatalf("error loading teams: %s", err)
+		}
+		if err := x.LoadSchedules(ctx); err != nil {
+			t.Fatalf("error loading schedules: %s", err)
+		}
+	}
+
+	t.Run("LoadUsers", func(t *testing.T) {
+		t.Parallel()
+		ctx, x := setup(t)
+
+		// People are listed across two pages. Emails come from email devices, or from usernames
+		// which are email addresses.
+		if err := x.LoadUsers(ctx); err != nil {
+			t.Fatalf("error loading users: %s", err)
+		}
+		u, err := store.UseQueries(ctx).ListExtUsers(ctx)
+		if err != nil {
+			t.Fatalf("error loading users: %s", err)
+		}
+		assertJSON(t, u)
+	})
+
+	t.Run("LoadTeams", func(t *testing.T) {
+		t.Parallel()
+		ctx, x := setup(t)
+
+		if err := x.LoadTeams(ctx); err != nil {
+			t.Fatalf("error loading teams: %s", err)
+		}
+		teams, err := store.UseQueries(ctx).ListExtTeams(ctx)
+		if err != nil {
+			t.Fatalf("error loading teams: %s", err)
+		}
+		assertJSON(t, teams)
+	})
+
+	t.Run("LoadTeamMembers", func(t *testing.T) {
+		t.Parallel()
+		ctx, x := setup(t)
+
+		if err := x.LoadUsers(ctx); err != nil {
+			t.Fatalf("error loading users: %s", err)
+		}
+		if err := x.LoadTeams(ctx); err != nil {
+			t.Fatalf("error loading teams: %s", err)
+		}
+		if err := x.LoadTeamMembers(ctx); err != nil {
+			t.Fatalf("error loading team members: %s", err)
+		}
+		members, err := store.UseQueries(ctx).ListExtTeamMemberships(ctx)
+		if err != nil {
+			t.Fatalf("error loading team members: %s", err)
+		}
+		assertJSON(t, members)
+	})
+
+	t.Run("LoadSchedules", func(t *testing.T) {
+		t.Parallel()
+		ctx, x := setup(t)
+		loadSchedules(t, ctx, x)
+
+		// Each group with shifts is a schedule, in the time zone of its first shift.
+		schedules, err := store.UseQueries(ctx).ListExtSchedulesV2(ctx)
+		if err != nil {
+			t.Fatalf("error loading schedules: %s", err)
+		}
+		t.Logf("found %d schedules", len(schedules))
+		assertJSON(t, schedules)
+	})
+
+	t.Run("LoadRotations", func(t *testing.T) {
+		t.Parallel()
+		ctx, x := setup(t)
+		loadSchedules(t, ctx, x)
+
+		// Shifts map to rotations as follows:
+		// - "Primary" rotates weekly between the members in rotation, and has no restrictions.
+		// - "Business hours" doesn't rotate, so only its first member is on call, on weekdays.
+		// - "Nights" rotates daily and is restricted to 22:00 to 06:00 in London.
+		// - "Old rotation" ended and "Month-end close" repeats monthly, so both are skipped.
+		type rotation struct {
+			store.ExtRotation
+			Members      []store.ExtRotationMember      `json:"members"`
+			Restrictions []store.ExtRotationRestriction `json:"restrictions"`
+		}
+		q := store.UseQueries(ctx)
+		extRotations, err := q.ListExtRotations(ctx)
+		if err != nil {
+			t.Fatalf("error loading rotations: %s", err)
+		}
+		rotations := []rotation{}
+		for _, r := range extRotations {
+			members, err := q.ListExtRotationMembers(ctx, r.ID)
+			if err != nil {
+				t.Fatalf("error loading rotation members: %s", err)
+			}
+			restrictions, err := q.ListExtRotationRestrictions(ctx, r.ID)
+			if err != nil {
+				t.Fatalf("error loading rotation restrictions: %s", err)
+			}
+			rotations = append(rotations, rotation{r, members, restrictions})
+		}
+		assertJSON(t, rotations)
+	})
+
+	t.Run("LoadEscalationPolicies", func(t *testing.T) {
+		t.Parallel()
+		ctx, x := setup(t)
+		loadSchedules(t, ctx, x)
+		if err := x.LoadEscalationPolicies(ctx); err != nil {
+			t.Fatalf("error loading escalation policies: %s", err)
+		}
+
+		// Each shift which hasn't ended has an escalation policy, including those which aren't
+		// imported as rotations.
+		policies, err := store.UseQueries(ctx).ListExtEscalationPolicies(ctx)
+		if err != nil {
+			t.Fatalf("error loading escalation policies: %s", err)
+		}
+		t.Logf("found %d escalation policies", len(policies))
+		assertJSON(t, policies)
+	})
+
+	t.Run("LoadEscalationPolicySteps", func(t *testing.T) {
+		t.Parallel()
+		ctx, x := setup(t)
+		loadSchedules(t, ctx, x)
+		if err := x.LoadEscalationPolicies(ctx); err != nil {
+			t.Fatalf("error loading escalation policies: %s", err)
+		}
+
+		// Members without a delay join the previous step, members in rotation are notified through
+		// the schedule, and groups through their own schedule.
+		type step struct {
+			store.ExtEscalationPolicyStep
+			Targets []store.ExtEscalationPolicyStepTarget `json:"targets"`
+		}
+		q := store.UseQueries(ctx)
+		steps := []step{}
+		for _, id := range []string{"s-primary", "s-business", "s-nights", "s-monthly"} {
+			extSteps, err := q.ListExtEscalationPolicySteps(ctx, id)
+			if err != nil {
+				t.Fatalf("error loading escalation policy steps: %s", err)
+			}
+			for _, s := range extSteps {
+				targets, err := q.ListExtEscalationPolicyStepTargets(ctx, s.ID)
+				if err != nil {
+					t.Fatalf("error loading targets for step %s: %s", s.ID, err)
+				}
+				steps = append(steps, step{s, targets})
+			}
+		}
+		assertJSON(t, steps)
+	})
+}
@@ -0,0 +1,644 @@
+// Package pulumirender renders the imported resources as a Pulumi YAML program, for use with
+// the FireHydrant Terraform provider bridged to Pulumi.
+package pulumirender
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/firehydrant/signals-migrator/console"
+	"github.com/firehydrant/signals-migrator/diagnostics"
+	"github.com/firehydrant/signals-migrator/store"
+	"gopkg.in/yaml.v3"
+)
+
+// Tokens of the resources and functions of the bridged FireHydrant provider.
+const (
+	typeTeam             = "firehydrant:index/team:Team"
+	typeOnCallSchedule   = "firehydrant:index/onCallSchedule:OnCallSchedule"
+	typeRotation         = "firehydrant:index/rotation:Rotation"
+	typeEscalationPolicy = "firehydrant:index/escalationPolicy:EscalationPolicy"
+	functionGetUser      = "firehydrant:index/getUser:getUser"
+)
+
+type PulumiRender struct {
+	// Output file directory.
+	dir string
+	// Output file name.
+	filename string
+	// Name of the Pulumi project.
+	project string
+
+	variables *yaml.Node
+	resources *yaml.Node
+}
+
+// New prepares a Pulumi YAML program, which is written to name. Pulumi expects the program in
+// a file named Pulumi.yaml.
+func New(name string, project string) (*PulumiRender, error) {
+	baseDir := filepath.Dir(name)
+	if err := os.MkdirAll(baseDir, 0755); err != nil {
+		return nil, fmt.Errorf("preparing output directory: %w", err)
+	}
+	return &PulumiRender{
+		dir:       baseDir,
+		filename:  filepath.Base(name),
+		project:   project,
+		variables: mapping(),
+		resources: mapping(),
+	}, nil
+}
+
+func (r *PulumiRender) Filepath() string {
+	return filepath.Join(r.dir, r.filename)
+}
+
+func (r *PulumiRender) Filename() string {
+	return r.filename
+}
+
+func (r *PulumiRender) Write(ctx context.Context) error {
+	toWrite := []func(context.Context) error{
+		r.FireHydrantUsers,
+		r.FireHydrantTeams,
+		r.FireHydrantOnCallSchedules,
+		r.FireHydrantRotations,
+		r.FireHydrantEscalationPolicies,
+	}
+	for _, w := range toWrite {
+		if err := w(ctx); err != nil {
+			return err
+		}
+	}
+
+	program := mapping()
+	set(program, "name", str(r.project))
+	set(program, "runtime", str("yaml"))
+	set(program, "description", str("FireHydrant Signals resources generated by signals-migrator."))
+	if len(r.variables.Content) > 0 {
+		set(program, "variables", r.variables)
+	}
+	if len(r.resources.Content) > 0 {
+		set(program, "resources", r.resources)
+	}
+
+	f, err := os.Create(r.Filepath())
+	if err != nil {
+		return fmt.Errorf("creating file: %w", err)
+	}
+	defer f.Close()
+
+	enc := yaml.NewEncoder(f)
+	enc.SetIndent(2)
+	if err := enc.Encode(&yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{program}}); err != nil {
+		return fmt.Errorf("writing file: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return fmt.Errorf("writing file: %w", err)
+	}
+	console.Successf("Pulumi program has been written to %s\n", r.Filepath())
+	return nil
+}
+
+// Logical names of resources are prefixed by their kind, as the slug of a team may well be the
+// slug of a user or an escalation policy too.
+
+func userName(slug string) string {
+	return "user_" + slug
+}
+
+func teamName(slug string) string {
+	return "team_" + slug
+}
+
+func scheduleName(teamSlug string, s store.ExtSchedulesV2) string {
+	return fmt.Sprintf("schedule_%s_%s", teamSlug, s.TFSlug())
+}
+
+func rotationName(teamSlug string, s store.ExtSchedulesV2, rotation store.ExtRotation) string {
+	return fmt.Sprintf("rotation_%s_%s_%s", teamSlug, s.TFSlug(), rotation.TFSlug())
+}
+
+func policyName(p store.ExtEscalationPolicy) string {
+	return "escalation_policy_" + p.TFSlug()
+}
+
+// resource declares a resource of the program, and returns its properties.
+func (r *PulumiRender) resource(name string, resourceType string, comments ...string) (*yaml.Node, *yaml.Node) {
+	res := mapping()
+	key := set(r.resources, name, res)
+	key.HeadComment = comment(comments...)
+	set(res, "type", str(resourceType))
+	props := mapping()
+	set(res, "properties", props)
+	return res, props
+}
+
+// importExisting imports a resource which already exists in FireHydrant, rather than creating it.
+func importExisting(ctx context.Context, res *yaml.Node, resourceType string, sourceID string) error {
+	importID, err := store.ExistingImportID(ctx, resourceType, sourceID)
+	if err != nil {
+		return err
+	}
+	if importID != "" {
+		set(res, "options", mapping("import", str(importID)))
+	}
+	return nil
+}
+
+// comment sets the comment above a resource declared earlier.
+func (r *PulumiRender) comment(res *yaml.Node, comments ...string) {
+	for i := 0; i+1 < len(r.resources.Content); i += 2 {
+		if r.resources.Content[i+1] == res {
+			r.resources.Content[i].HeadComment = comment(comments...)
+		}
+	}
+}
+
+func (r *PulumiRender) FireHydrantUsers(ctx context.Context) error {
+	users, err := store.UseQueries(ctx).ListFhUsers(ctx)
+	if err != nil {
+		return fmt.Errorf("querying users: %w", err)
+	}
+	for _, u := range users {
+		annotations, err := store.UseQueries(ctx).ListFhUserAnnotations(ctx, sql.NullString{String: u.ID, Valid: true})
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("querying annotations for user '%s': %w", u.Email, err)
+		}
+
+		invoke := mapping()
+		set(invoke, "function", str(functionGetUser))
+		set(invoke, "arguments", mapping("email", str(u.Email)))
+		key := set(r.variables, userName(u.TFSlug()), mapping("fn::invoke", invoke))
+		key.HeadComment = comment(annotations...)
+	}
+	return nil
+}
+
+func (r *PulumiRender) FireHydrantTeams(ctx context.Context) error {
+	q := store.UseQueries(ctx)
+	extTeams, err := q.ListTeamsToImport(ctx)
+	if err != nil {
+		return fmt.Errorf("querying teams: %w", err)
+	}
+
+	// As with Terraform, member teams are merged into their group team, and teams which already
+	// exist in FireHydrant are imported.
+	type team struct {
+		res         *yaml.Node
+		memberships *yaml.Node
+		comments    []string
+	}
+	teams := map[string]*team{}
+	importedMembership := map[string]bool{}
+	for _, t := range extTeams {
+		name := t.ValidName()
+		tfSlug := t.TFSlug()
+
+		fhTeam, ok := teams[name]
+		if !ok {
+			res, props := r.resource(teamName(tfSlug), typeTeam)
+			set(props, "name", str(name))
+			fhTeam = &team{res: res, memberships: sequence()}
+			teams[name] = fhTeam
+		}
+		if t.FhTeamID.Valid && t.FhTeamID.String != "" && !hasKey(fhTeam.res, "options") {
+			set(fhTeam.res, "options", mapping("import", str(t.FhTeamID.String)))
+		}
+
+		memberTeams, err := q.ListMemberExtTeams(ctx, t.ID)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("querying member teams: %w", err)
+		}
+		teamIDs := []string{t.ID}
+		for _, mt := range memberTeams {
+			teamIDs = append(teamIDs, mt.ID)
+		}
+
+		for _, teamID := range teamIDs {
+			annotation, err := q.GetExtTeamAnnotation(ctx, teamID)
+			if err != nil && !errors.Is(err, sql.ErrNoRows) {
+				return fmt.Errorf("querying annotation for team '%s': %w", teamID, err)
+			}
+			fhTeam.comments = append(fhTeam.comments, annotation)
+
+			members, err := q.ListFhMembersByExtTeamID(ctx, teamID)
+			if err != nil {
+				return fmt.Errorf("querying team members: %w", err)
+			}
+			for _, m := range members {
+				if importedMembership[tfSlug+m.TFSlug()] {
+					continue
+				}
+				fhTeam.memberships.Content = append(fhTeam.memberships.Content, mapping("userId", ref(userName(m.TFSlug()))))
+				importedMembership[tfSlug+m.TFSlug()] = true
+			}
+		}
+	}
+
+	for _, t := range teams {
+		if len(t.memberships.Content) > 0 {
+			set(value(t.res, "properties"), "memberships", t.memberships)
+		}
+	}
+	for _, t := range teams {
+		r.comment(t.res, t.comments...)
+	}
+	return nil
+}
+
+// scheduleTeamSlug returns the slug of the FireHydrant team owning a schedule.
+func scheduleTeamSlug(ctx context.Context, s store.ExtSchedulesV2) (string, error) {
+	team, err := store.UseQueries(ctx).GetExtTeam(ctx, s.TeamID)
+	if err != nil {
+		return "", fmt.Errorf("querying team for schedule '%s': %w", s.Name, err)
+	}
+	// The slug is the one of the linked team, which may be an existing FireHydrant team.
+	linkedTeam, err := store.UseQueries(ctx).GetTeamByExtID(ctx, team.ID)
+	if err != nil {
+		return "", fmt.Errorf("querying linked_team for team '%s': %w", team.ID, err)
+	}
+	return linkedTeam.TFSlug(), nil
+}
+
+func (r *PulumiRender) FireHydrantOnCallSchedules(ctx context.Context) error {
+	q := store.UseQueries(ctx)
+	schedules, err := q.ListExtSchedulesV2(ctx)
+	if err != nil {
+		return fmt.Errorf("querying schedules: %w", err)
+	}
+
+	for _, s := range schedules {
+		teamSlug, err := scheduleTeamSlug(ctx, s)
+		if err != nil {
+			return err
+		}
+		team, err := q.GetExtTeam(ctx, s.TeamID)
+		if err != nil {
+			return fmt.Errorf("querying team for schedule '%s': %w", s.Name, err)
+		}
+
+		comments := []string{team.Annotations}
+		overrides, err := q.ListExtScheduleOverridesByExtScheduleID(ctx, s.ID)
+		if err != nil {
+			return fmt.Errorf("querying overrides: %w", err)
+		}
+		if len(overrides) > 0 {
+			comments = append(comments, "Overrides found for this schedule:")
+			for _, override := range overrides {
+				user := override.Username
+				if u, err := q.GetUserByEmail(ctx, override.Username); err != nil || !u.FhUserID.Valid {
+					user += " (not imported)"
+				}
+				comments = append(comments, fmt.Sprintf("User: %s  Starting: %s  Ending: %s", user, override.StartTime, override.EndTime))
+			}
+			comments = append(comments, "Overrides can't be managed with Pulumi. Run the import with '--output-mode apply' to create them, or add them manually.",
+				"You can see documention for adding overrides here: https://docs.firehydrant.com/docs/signals-on-call-schedules#overrides")
+		}
+
+		res, props := r.resource(scheduleName(teamSlug, s), typeOnCallSchedule, comments...)
+		if err := importExisting(ctx, res, store.EXISTING_RESOURCE_SCHEDULE, s.ID); err != nil {
+			return err
+		}
+		set(props, "name", str(s.Name))
+		if s.Description != "" {
+			set(props, "description", str(s.Description))
+		}
+		set(props, "teamId", ref(teamName(teamSlug)))
+
+		rotations, err := q.ListExtRotationsByScheduleID(ctx, s.ID)
+		if err != nil {
+			return fmt.Errorf("querying rotations: %w", err)
+		}
+		// The first rotation is declared along with the schedule, and the others as separate resources.
+		if len(rotations) > 0 {
+			first := rotations[0]
+			if first.Name != "" && first.Name != s.Name {
+				set(props, "rotationName", str(first.Name))
+			}
+			if first.Description != "" && first.Description != s.Description {
+				set(props, "rotationDescription", str(first.Description))
+			}
+			if err := r.rotationProperties(ctx, s, first, props, false); err != nil {
+				return fmt.Errorf("rendering rotation data '%s': %w", first.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (r *PulumiRender) FireHydrantRotations(ctx context.Context) error {
+	q := store.UseQueries(ctx)
+	schedules, err := q.ListExtSchedulesV2(ctx)
+	if err != nil {
+		return fmt.Errorf("querying schedules: %w", err)
+	}
+
+	for _, s := range schedules {
+		rotations, err := q.ListExtRotationsByScheduleID(ctx, s.ID)
+		if err != nil {
+			return fmt.Errorf("querying rotations: %w", err)
+		}
+		if len(rotations) < 2 {
+			continue
+		}
+		teamSlug, err := scheduleTeamSlug(ctx, s)
+		if err != nil {
+			return err
+		}
+		team, err := q.GetExtTeam(ctx, s.TeamID)
+		if err != nil {
+			return fmt.Errorf("querying team for schedule '%s': %w", s.Name, err)
+		}
+
+		for _, rotation := range rotations[1:] {
+			res, props := r.resource(rotationName(teamSlug, s, rotation), typeRotation, team.Annotations)
+			if err := importExisting(ctx, res, store.EXISTING_RESOURCE_ROTATION, rotation.ID); err != nil {
+				return err
+			}
+			set(props, "name", str(rotation.Name))
+			if rotation.Description != "" {
+				set(props, "description", str(rotation.Description))
+			}
+			set(props, "teamId", ref(teamName(teamSlug)))
+			set(props, "scheduleId", ref(scheduleName(teamSlug, s)))
+			if err := r.rotationProperties(ctx, s, rotation, props, true); err != nil {
+				return fmt.Errorf("rendering rotation data '%s': %w", rotation.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// rotationProperties sets the members, strategy and restrictions of a rotation. Rotations list
+// their members, while schedules only list the IDs of the members of their first rotation.
+func (r *PulumiRender) rotationProperties(ctx context.Context, s store.ExtSchedulesV2, rotation store.ExtRotation, props *yaml.Node, useMembers bool) error {
+	q := store.UseQueries(ctx)
+	set(props, "timeZone", str(s.Timezone))
+	if rotation.StartTime != "" {
+		set(props, "startTime", str(rotation.StartTime))
+	}
+
+	members, err := q.ListFhMembersByExtRotationID(ctx, rotation.ID)
+	if err != nil {
+		return fmt.Errorf("querying members for rotation '%s': %w", rotation.Name, err)
+	}
+	memberList := sequence()
+	for _, m := range members {
+		if useMembers {
+			memberList.Content = append(memberList.Content, mapping("userId", ref(userName(m.TFSlug()))))
+		} else {
+			memberList.Content = append(memberList.Content, ref(userName(m.TFSlug())))
+		}
+	}
+	if useMembers {
+		set(props, "members", memberList)
+	} else {
+		set(props, "memberIds", memberList)
+	}
+
+	strategy := mapping("type", str(rotation.Strategy))
+	if rotation.Strategy == "weekly" || rotation.Strategy == "daily" {
+		set(strategy, "handoffDay", str(rotation.HandoffDay))
+	}
+	if rotation.Strategy == "custom" {
+		set(strategy, "shiftDuration", str(rotation.ShiftDuration))
+	} else {
+		set(strategy, "handoffTime", str(rotation.HandoffTime))
+	}
+	set(props, "strategy", strategy)
+
+	restrictions, err := q.ListExtRotationRestrictions(ctx, rotation.ID)
+	if err != nil {
+		return fmt.Errorf("querying restrictions for rotation '%s': %w", rotation.Name, err)
+	}
+	if len(restrictions) > 0 {
+		list := sequence()
+		for _, restriction := range restrictions {
+			list.Content = append(list.Content, mapping(
+				"startDay", str(restriction.StartDay),
+				"startTime", str(restriction.StartTime),
+				"endDay", str(restriction.EndDay),
+				"endTime", str(restriction.EndTime),
+			))
+		}
+		set(props, "restrictions", list)
+	}
+	return nil
+}
+
+func (r *PulumiRender) FireHydrantEscalationPolicies(ctx context.Context) error {
+	q := store.UseQueries(ctx)
+	policies, err := q.ListExtEscalationPolicies(ctx)
+	if err != nil {
+		return fmt.Errorf("querying escalation policies: %w", err)
+	}
+	defaults, err := store.DefaultEscalationPolicyIDs(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, p := range policies {
+		comments := []string{p.Annotations}
+		if comment, err := diagnostics.RepeatInterval(ctx, p); err != nil {
+			return err
+		} else if comment != "" {
+			comments = append(comments, comment)
+		}
+		res, props := r.resource(policyName(p), typeEscalationPolicy)
+		if err := importExisting(ctx, res, store.EXISTING_RESOURCE_ESCALATION_POLICY, p.ID); err != nil {
+			return err
+		}
+		set(props, "name", str(p.Name))
+		if p.Description != "" {
+			set(props, "description", str(p.Description))
+		}
+		if p.TeamID.Valid && p.TeamID.String != "" {
+			t, err := q.GetTeamByExtID(ctx, p.TeamID.String)
+			if err != nil {
+				return fmt.Errorf("querying team '%s' for policy '%s': %w", p.TeamID.String, p.Name, err)
+			}
+			set(props, "teamId", ref(teamName(t.TFSlug())))
+		}
+
+		steps, err := q.ListExtEscalationPolicySteps(ctx, p.ID)
+		if err != nil {
+			return fmt.Errorf("querying steps for policy '%s': %w", p.Name, err)
+		}
+		stepList := sequence()
+		for _, s := range steps {
+			targets, err := q.ListExtEscalationPolicyStepTargets(ctx, s.ID)
+			if err != nil {
+				return fmt.Errorf("querying targets for step %d of %s: %w", s.Position, p.Name, err)
+			}
+			skip := func(t store.ExtEscalationPolicyStepTarget, reason string) store.InsertExtEscalationPolicyStepTargetSkipParams {
+				return store.InsertExtEscalationPolicyStepTargetSkipParams{
+					EscalationPolicyID: p.ID,
+					StepPosition:       s.Position,
+					TargetType:         t.TargetType,
+					TargetID:           t.TargetID,
+					Reason:             reason,
+				}
+			}
+
+			targetList := sequence()
+			for _, t := range targets {
+				var id *yaml.Node
+				switch t.TargetType {
+				case store.TARGET_TYPE_USER:
+					u, err := q.GetUserByExtID(ctx, t.TargetID)
+					if err != nil {
+						diagnostics.SkipStepTarget(ctx, skip(t, store.TARGET_SKIP_MISSING_FH_USER),
+							"Skipping user '%s' in step %d of %s: %s\n", t.TargetID, s.Position, p.Name, err.Error())
+						continue
+					}
+					id = ref(userName(u.TFSlug()))
+				case store.TARGET_TYPE_SCHEDULE:
+					schedule, err := q.GetExtScheduleV2(ctx, t.TargetID)
+					if err != nil {
+						diagnostics.SkipStepTarget(ctx, skip(t, store.TARGET_SKIP_NOT_IMPORTED),
+							"Skipping schedule '%s' in step %d of %s: %s\n", t.TargetID, s.Position, p.Name, err.Error())
+						continue
+					}
+					if _, err := q.GetExtTeam(ctx, schedule.TeamID); err != nil {
+						diagnostics.SkipStepTarget(ctx, skip(t, store.TARGET_SKIP_NOT_IMPORTED),
+							"Skipping schedule '%s' in step %d of %s, its team is not imported: %s\n", schedule.Name, s.Position, p.Name, err.Error())
+						continue
+					}
+					teamSlug, err := scheduleTeamSlug(ctx, schedule)
+					if err != nil {
+						return err
+					}
+					id = ref(scheduleName(teamSlug, schedule))
+				default:
+					diagnostics.SkipStepTarget(ctx, skip(t, store.TARGET_SKIP_UNSUPPORTED_TYPE),
+						"Skipping unknown target type '%s' in step %d of %s.\n", t.TargetType, s.Position, p.Name)
+					continue
+				}
+				targetList.Content = append(targetList.Content, mapping("type", str(t.TargetType), "id", id))
+			}
+			stepList.Content = append(stepList.Content, mapping("timeout", str(s.Timeout), "targets", targetList))
+		}
+		set(props, "steps", stepList)
+		set(props, "repetitions", integer(p.RepeatLimit))
+		set(props, "default", boolean(defaults[p.ID]))
+
+		handoff, err := r.handoffStep(ctx, p)
+		if err != nil {
+			return err
+		}
+		if handoff != nil {
+			set(props, "handoffStep", handoff)
+		} else if p.HandoffTargetType != "" {
+			comments = append(comments, fmt.Sprintf("Handoff to %s '%s' was skipped as it is not imported.", p.HandoffTargetType, p.HandoffTargetID))
+		}
+
+		r.comment(res, comments...)
+	}
+	return nil
+}
+
+// handoffStep returns the step FireHydrant hands off to once every step and repetition of the
+// policy is exhausted, or nil when there is none or its target is not imported.
+func (r *PulumiRender) handoffStep(ctx context.Context, p store.ExtEscalationPolicy) (*yaml.Node, error) {
+	var target *yaml.Node
+	switch p.HandoffTargetType {
+	case "":
+		return nil, nil
+	case store.TARGET_TYPE_ESCALATION_POLICY:
+		handoff, err := store.UseQueries(ctx).GetExtEscalationPolicy(ctx, p.HandoffTargetID)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("querying handoff policy '%s' for policy '%s': %w", p.HandoffTargetID, p.Name, err)
+		}
+		if err == nil {
+			target = ref(policyName(handoff))
+		}
+	case store.TARGET_TYPE_TEAM:
+		t, err := store.UseQueries(ctx).GetTeamByExtID(ctx, p.HandoffTargetID)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("querying handoff team '%s' for policy '%s': %w", p.HandoffTargetID, p.Name, err)
+		}
+		if err == nil && t.ToImport == 1 {
+			target = ref(teamName(t.TFSlug()))
+		}
+	default:
+		diagnostics.Errorf(ctx, diagnostics.ResourceEscalationPolicy, p.ID, "unknown handoff target type '%s' for %s\n", p.HandoffTargetType, p.Name)
+		return nil, nil
+	}
+
+	if target == nil {
+		diagnostics.Warnf(ctx, diagnostics.ResourceEscalationPolicy, p.ID, "Handoff of %s to %s '%s' was skipped as it is not imported.\n", p.Name, p.HandoffTargetType, p.HandoffTargetID)
+		return nil, nil
+	}
+	return mapping("targetType", str(p.HandoffTargetType), "targetId", target), nil
+}
+
+// mapping returns a YAML mapping of the given keys and values, in order.
+func mapping(kv ...any) *yaml.Node {
+	m := &yaml.Node{Kind: yaml.MappingNode}
+	for i := 0; i+1 < len(kv); i += 2 {
+		set(m, kv[i].(string), kv[i+1].(*yaml.Node))
+	}
+	return m
+}
+
+// set appends a key to a mapping, and returns the key's node.
+func set(m *yaml.Node, key string, v *yaml.Node) *yaml.Node {
+	k := &yaml.Node{Kind: yaml.ScalarNode, Value: key}
+	m.Content = append(m.Content, k, v)
+	return k
+}
+
+func value(m *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			return m.Content[i+1]
+		}
+	}
+	return nil
+}
+
+func hasKey(m *yaml.Node, key string) bool {
+	return value(m, key) != nil
+}
+
+func sequence() *yaml.Node {
+	return &yaml.Node{Kind: yaml.SequenceNode}
+}
+
+// str is a literal string. Pulumi interpolates "${...}" in strings, so it is escaped.
+func str(s string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: strings.ReplaceAll(s, "${", "$${")}
+}
+
+// ref refers to the ID of a resource or the user returned by a lookup.
+func ref(name string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: fmt.Sprintf("${%s.id}", name)}
+}
+
+func integer(i int64) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: strconv.FormatInt(i, 10)}
+}
+
+func boolean(b bool) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: strconv.FormatBool(b)}
+}
+
+// comment joins annotations into a YAML comment, skipping empty lines.
+func comment(annotations ...string) string {
+	lines := []string{}
+	for _, a := range annotations {
+		for _, line := range strings.Split(strings.TrimSpace(a), "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				lines = append(lines, line)
+			}
+		}
+	}
+	return strings.Join(lines, "\n")
+}
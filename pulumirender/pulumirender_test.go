@@ -0,0 +1,56 @@
+package pulumirender_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/firehydrant/signals-migrator/pulumirender"
+	"github.com/firehydrant/signals-migrator/store"
+	"gopkg.in/yaml.v3"
+	"gotest.tools/v3/golden"
+)
+
+// assertRender renders the seed of the test as a Pulumi program, and compares it against its
+// golden file.
+func assertRender(t *testing.T) {
+	seed, err := os.ReadFile(filepath.Join("testdata", t.Name()+"_seed.sql"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := store.WithContext(context.Background())
+	t.Cleanup(func() { store.FromContext(ctx).Close() })
+	if _, err := store.FromContext(ctx).ExecContext(ctx, strings.TrimSpace(string(seed))); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := pulumirender.New(filepath.Join(t.TempDir(), "Pulumi.yaml"), "test-to-fh-signals")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Write(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := os.ReadFile(r.Filepath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var program map[string]any
+	if err := yaml.Unmarshal(content, &program); err != nil {
+		t.Fatalf("invalid YAML: %s", err)
+	}
+	golden.Assert(t, string(content), t.Name()+".golden.yaml")
+}
+
+func TestRender(t *testing.T) {
+	// Teams merged from groups, an existing team imported, and schedules and rotations
+	// referred to across teams.
+	t.Run("Teams", assertRender)
+
+	// Handoffs, repeats and annotations.
+	t.Run("EscalationPolicyHandoff", assertRender)
+}
@@ -0,0 +1,62 @@
+package simulate
+
+import (
+	"slices"
+	"time"
+
+	"github.com/firehydrant/signals-migrator/pager"
+)
+
+// Divergence is a period in which different users are on call in the source provider and in the
+// migrated schedule.
+type Divergence struct {
+	Start    time.Time `json:"start"`
+	End      time.Time `json:"end"`
+	Source   []string  `json:"source"`
+	Migrated []string  `json:"migrated"`
+}
+
+// Compare returns every period between since and until in which the users on call in source and
+// migrated differ. Consecutive periods with the same difference are reported as one.
+func Compare(source []pager.OnCallShift, migrated []pager.OnCallShift, since time.Time, until time.Time) []Divergence {
+	boundaries := []time.Time{since, until}
+	for _, s := range slices.Concat(source, migrated) {
+		for _, t := range []time.Time{s.Start, s.End} {
+			if t.After(since) && t.Before(until) {
+				boundaries = append(boundaries, t)
+			}
+		}
+	}
+	slices.SortFunc(boundaries, time.Time.Compare)
+	boundaries = slices.CompactFunc(boundaries, time.Time.Equal)
+
+	divergences := []Divergence{}
+	for i := 0; i+1 < len(boundaries); i++ {
+		start, end := boundaries[i], boundaries[i+1]
+		src, mig := onCallAt(source, start), onCallAt(migrated, start)
+		if slices.Equal(src, mig) {
+			continue
+		}
+		if n := len(divergences); n > 0 {
+			last := &divergences[n-1]
+			if last.End.Equal(start) && slices.Equal(last.Source, src) && slices.Equal(last.Migrated, mig) {
+				last.End = end
+				continue
+			}
+		}
+		divergences = append(divergences, Divergence{Start: start, End: end, Source: src, Migrated: mig})
+	}
+	return divergences
+}
+
+// onCallAt returns the sorted IDs of the users on call at t.
+func onCallAt(shifts []pager.OnCallShift, t time.Time) []string {
+	users := []string{}
+	for _, s := range shifts {
+		if !s.Start.After(t) && s.End.After(t) {
+			users = append(users, s.UserID)
+		}
+	}
+	slices.Sort(users)
+	return slices.Compact(users)
+}
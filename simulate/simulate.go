@@ -0,0 +1,288 @@
+// Package simulate computes who would be on call in the migrated FireHydrant schedules, from the
+// rotations and restrictions saved in the store, so that it can be compared with who is on call in
+// the source provider.
+package simulate
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/firehydrant/signals-migrator/pager"
+	"github.com/firehydrant/signals-migrator/store"
+)
+
+// Schedule returns who is on call for the imported schedule between since and until. Like in
+// FireHydrant, every rotation of the schedule is on call at the same time, and an override replaces
+// whoever is on call for its duration.
+func Schedule(ctx context.Context, scheduleID string, since time.Time, until time.Time) ([]pager.OnCallShift, error) {
+	q := store.UseQueries(ctx)
+	schedule, err := q.GetExtScheduleV2(ctx, scheduleID)
+	if err != nil {
+		return nil, fmt.Errorf("getting schedule %s: %w", scheduleID, err)
+	}
+	loc, err := time.LoadLocation(schedule.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("loading time zone '%s' of schedule %s: %w", schedule.Timezone, scheduleID, err)
+	}
+
+	rotations, err := q.ListExtRotationsByScheduleID(ctx, scheduleID)
+	if err != nil {
+		return nil, fmt.Errorf("listing rotations of schedule %s: %w", scheduleID, err)
+	}
+	shifts := []pager.OnCallShift{}
+	for _, r := range rotations {
+		members, err := q.ListExtRotationMembers(ctx, r.ID)
+		if err != nil {
+			return nil, fmt.Errorf("listing members of rotation %s: %w", r.ID, err)
+		}
+		restrictions, err := q.ListExtRotationRestrictions(ctx, r.ID)
+		if err != nil {
+			return nil, fmt.Errorf("listing restrictions of rotation %s: %w", r.ID, err)
+		}
+		userIDs := make([]string, 0, len(members))
+		for _, m := range members {
+			userIDs = append(userIDs, m.UserID)
+		}
+		s, err := rotationShifts(r, userIDs, restrictions, loc, since, until)
+		if err != nil {
+			return nil, fmt.Errorf("simulating rotation %s: %w", r.ID, err)
+		}
+		shifts = append(shifts, s...)
+	}
+
+	overrides, err := q.ListExtScheduleOverridesByExtScheduleID(ctx, scheduleID)
+	if err != nil {
+		return nil, fmt.Errorf("listing overrides of schedule %s: %w", scheduleID, err)
+	}
+	for _, o := range overrides {
+		start, err := parseTime(o.StartTime)
+		if err != nil {
+			return nil, fmt.Errorf("parsing start of override %s: %w", o.ID, err)
+		}
+		end, err := parseTime(o.EndTime)
+		if err != nil {
+			return nil, fmt.Errorf("parsing end of override %s: %w", o.ID, err)
+		}
+		start, end = clamp(start, since, until), clamp(end, since, until)
+		if !start.Before(end) {
+			continue
+		}
+		// Overrides are saved with the user's email, or with the provider's user ID when the user
+		// was not imported.
+		userID := o.Username
+		if u, err := q.GetUserByEmail(ctx, o.Username); err == nil {
+			userID = u.ID
+		}
+		shifts = append(cut(shifts, start, end), pager.OnCallShift{UserID: userID, Start: start, End: end})
+	}
+	return shifts, nil
+}
+
+// rotationShifts returns the shifts of a rotation between since and until, limited to its restrictions.
+// Members take turns in order starting at the rotation's start time, and nobody is on call before it.
+func rotationShifts(r store.ExtRotation, userIDs []string, restrictions []store.ExtRotationRestriction, loc *time.Location, since time.Time, until time.Time) ([]pager.OnCallShift, error) {
+	if len(userIDs) == 0 {
+		return nil, nil
+	}
+	anchor, err := rotationStart(r, loc, since)
+	if err != nil {
+		return nil, err
+	}
+
+	// Daily and weekly handoffs follow the wall clock of the schedule's time zone, so they move
+	// with daylight saving time, while custom shifts are an exact duration.
+	var turnLength time.Duration
+	var boundary func(turn int) time.Time
+	switch r.Strategy {
+	case "daily":
+		turnLength = 24 * time.Hour
+		boundary = func(turn int) time.Time { return anchor.AddDate(0, 0, turn) }
+	case "weekly":
+		turnLength = 7 * 24 * time.Hour
+		boundary = func(turn int) time.Time { return anchor.AddDate(0, 0, 7*turn) }
+	case "custom":
+		turnLength, err = pager.ParseShiftDuration(r.ShiftDuration)
+		if err != nil {
+			return nil, err
+		}
+		boundary = func(turn int) time.Time { return anchor.Add(time.Duration(turn) * turnLength) }
+	default:
+		return nil, fmt.Errorf("unknown rotation strategy '%s'", r.Strategy)
+	}
+
+	turn := 0
+	if since.After(anchor) {
+		turn = int(since.Sub(anchor) / turnLength)
+		for turn > 0 && boundary(turn).After(since) {
+			turn--
+		}
+		for !boundary(turn + 1).After(since) {
+			turn++
+		}
+	}
+
+	windows, err := restrictionWindows(restrictions, loc, since, until)
+	if err != nil {
+		return nil, err
+	}
+	shifts := []pager.OnCallShift{}
+	for ; boundary(turn).Before(until); turn++ {
+		start, end := clamp(boundary(turn), since, until), clamp(boundary(turn+1), since, until)
+		userID := userIDs[turn%len(userIDs)]
+		if windows == nil {
+			shifts = append(shifts, pager.OnCallShift{UserID: userID, Start: start, End: end})
+			continue
+		}
+		for _, w := range windows {
+			s, e := clamp(w.Start, start, end), clamp(w.End, start, end)
+			if s.Before(e) {
+				shifts = append(shifts, pager.OnCallShift{UserID: userID, Start: s, End: e})
+			}
+		}
+	}
+	return shifts, nil
+}
+
+// rotationStart returns the time the first turn of the rotation starts at. Rotations without a
+// start time are anchored to their last handoff before since.
+func rotationStart(r store.ExtRotation, loc *time.Location, since time.Time) (time.Time, error) {
+	if r.StartTime != "" {
+		start, err := time.Parse(time.RFC3339, r.StartTime)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("parsing start time '%s': %w", r.StartTime, err)
+		}
+		return start.In(loc), nil
+	}
+
+	handoff, err := time.Parse(time.TimeOnly, r.HandoffTime)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("parsing handoff time '%s': %w", r.HandoffTime, err)
+	}
+	local := since.In(loc)
+	start := time.Date(local.Year(), local.Month(), local.Day(), handoff.Hour(), handoff.Minute(), handoff.Second(), 0, loc)
+	if r.Strategy == "weekly" {
+		day, err := weekday(r.HandoffDay)
+		if err != nil {
+			return time.Time{}, err
+		}
+		start = start.AddDate(0, 0, int(day-start.Weekday()))
+	}
+	for start.After(since) {
+		start = start.AddDate(0, 0, -7)
+	}
+	return start, nil
+}
+
+type window struct {
+	Start time.Time
+	End   time.Time
+}
+
+// restrictionWindows returns the periods between since and until in which a rotation with the given
+// restrictions is on call, or nil when the rotation is not restricted. Restrictions ending at or
+// before their start wrap around to the following week.
+func restrictionWindows(restrictions []store.ExtRotationRestriction, loc *time.Location, since time.Time, until time.Time) ([]window, error) {
+	if len(restrictions) == 0 {
+		return nil, nil
+	}
+
+	// Windows are generated week by week, starting a week early to include the ones wrapping
+	// around into the first week.
+	local := since.In(loc)
+	week := time.Date(local.Year(), local.Month(), local.Day()-int(local.Weekday())-7, 0, 0, 0, 0, loc)
+	windows := []window{}
+	for ; week.Before(until); week = week.AddDate(0, 0, 7) {
+		for _, r := range restrictions {
+			start, err := weekTime(week, r.StartDay, r.StartTime)
+			if err != nil {
+				return nil, err
+			}
+			end, err := weekTime(week, r.EndDay, r.EndTime)
+			if err != nil {
+				return nil, err
+			}
+			if !end.After(start) {
+				end = end.AddDate(0, 0, 7)
+			}
+			if end.After(since) && start.Before(until) {
+				windows = append(windows, window{Start: start, End: end})
+			}
+		}
+	}
+
+	// Restrictions may overlap, such as daily restrictions saved for every day of the week, so they
+	// are merged to not count the same period twice.
+	slices.SortFunc(windows, func(a, b window) int { return a.Start.Compare(b.Start) })
+	merged := []window{}
+	for _, w := range windows {
+		if n := len(merged); n > 0 && !w.Start.After(merged[n-1].End) {
+			if w.End.After(merged[n-1].End) {
+				merged[n-1].End = w.End
+			}
+			continue
+		}
+		merged = append(merged, w)
+	}
+	return merged, nil
+}
+
+func weekTime(week time.Time, day string, timeOfDay string) (time.Time, error) {
+	d, err := weekday(day)
+	if err != nil {
+		return time.Time{}, err
+	}
+	t, err := time.Parse(time.TimeOnly, timeOfDay)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("parsing restriction time '%s': %w", timeOfDay, err)
+	}
+	return time.Date(week.Year(), week.Month(), week.Day()+int(d), t.Hour(), t.Minute(), t.Second(), 0, week.Location()), nil
+}
+
+func weekday(day string) (time.Weekday, error) {
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		if strings.EqualFold(d.String(), day) {
+			return d, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown day of week '%s'", day)
+}
+
+// parseTime parses override times, which are saved as RFC 3339 by PagerDuty and RFC 1123 by Opsgenie.
+func parseTime(s string) (time.Time, error) {
+	t, err := time.Parse(time.RFC3339, s)
+	if err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC1123Z, s)
+}
+
+// cut removes the period between start and end from the shifts.
+func cut(shifts []pager.OnCallShift, start time.Time, end time.Time) []pager.OnCallShift {
+	result := make([]pager.OnCallShift, 0, len(shifts))
+	for _, s := range shifts {
+		if !s.Start.Before(end) || !s.End.After(start) {
+			result = append(result, s)
+			continue
+		}
+		if s.Start.Before(start) {
+			result = append(result, pager.OnCallShift{UserID: s.UserID, Start: s.Start, End: start})
+		}
+		if s.End.After(end) {
+			result = append(result, pager.OnCallShift{UserID: s.UserID, Start: end, End: s.End})
+		}
+	}
+	return result
+}
+
+func clamp(t time.Time, lo time.Time, hi time.Time) time.Time {
+	if t.Before(lo) {
+		return lo
+	}
+	if t.After(hi) {
+		return hi
+	}
+	return t
+}
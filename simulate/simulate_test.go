@@ -0,0 +1,84 @@
+package simulate_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/firehydrant/signals-migrator/internal/testkit"
+	"github.com/firehydrant/signals-migrator/pager"
+	"github.com/firehydrant/signals-migrator/simulate"
+	"github.com/firehydrant/signals-migrator/store"
+)
+
+func seedStore(t *testing.T) context.Context {
+	t.Helper()
+
+	ctx := testkit.NewStore(t, context.Background())
+	seed, err := os.ReadFile(filepath.Join("testdata", "seed.sql"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.FromContext(ctx).ExecContext(ctx, string(seed)); err != nil {
+		t.Fatal(err)
+	}
+	return ctx
+}
+
+func TestSchedule(t *testing.T) {
+	ctx := seedStore(t)
+
+	t.Run("WeeklyAcrossDaylightSavingTime", func(t *testing.T) {
+		// Daylight saving time starts on 2024-03-10 in Los Angeles, and the Monday handoff stays at 09:00.
+		since := time.Date(2024, 3, 6, 0, 0, 0, 0, time.UTC)
+		shifts, err := simulate.Schedule(ctx, "PWEEKLY", since, since.AddDate(0, 0, 14))
+		if err != nil {
+			t.Fatal(err)
+		}
+		testkit.GoldenJSON(t, shifts)
+	})
+
+	t.Run("RestrictedWithOverride", func(t *testing.T) {
+		since := time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)
+		shifts, err := simulate.Schedule(ctx, "PNIGHTS", since, since.AddDate(0, 0, 3))
+		if err != nil {
+			t.Fatal(err)
+		}
+		testkit.GoldenJSON(t, shifts)
+	})
+}
+
+func TestCompare(t *testing.T) {
+	at := func(hour int) time.Time { return time.Date(2024, 3, 4, hour, 0, 0, 0, time.UTC) }
+	source := []pager.OnCallShift{
+		{UserID: "PALICE", Start: at(0), End: at(8)},
+		{UserID: "PBOB", Start: at(8), End: at(16)},
+		{UserID: "PCAROL", Start: at(16), End: at(24)},
+	}
+	migrated := []pager.OnCallShift{
+		{UserID: "PALICE", Start: at(0), End: at(6)},
+		{UserID: "PALICE", Start: at(6), End: at(10)},
+		{UserID: "PBOB", Start: at(10), End: at(16)},
+		{UserID: "PCAROL", Start: at(18), End: at(24)},
+		{UserID: "PBOB", Start: at(20), End: at(24)},
+	}
+
+	got := simulate.Compare(source, migrated, at(0), at(24))
+	want := []simulate.Divergence{
+		{Start: at(8), End: at(10), Source: []string{"PBOB"}, Migrated: []string{"PALICE"}},
+		{Start: at(16), End: at(18), Source: []string{"PCAROL"}, Migrated: []string{}},
+		{Start: at(20), End: at(24), Source: []string{"PCAROL"}, Migrated: []string{"PBOB", "PCAROL"}},
+	}
+	if !slices.EqualFunc(got, want, func(a, b simulate.Divergence) bool {
+		return a.Start.Equal(b.Start) && a.End.Equal(b.End) && slices.Equal(a.Source, b.Source) && slices.Equal(a.Migrated, b.Migrated)
+	}) {
+		t.Errorf("expected divergences:\n%+v\ngot:\n%+v", want, got)
+	}
+
+	if d := simulate.Compare(source, source, at(0), at(24)); len(d) != 0 {
+		t.Errorf("expected no divergences comparing a schedule with itself, got %+v", d)
+	}
+}
@@ -0,0 +1,28 @@
+BEGIN TRANSACTION;
+
+INSERT INTO ext_users VALUES('PALICE','Alice','alice@example.com',NULL,'');
+INSERT INTO ext_users VALUES('PBOB','Bob','bob@example.com',NULL,'');
+INSERT INTO ext_users VALUES('PCAROL','Carol','carol@example.com',NULL,'');
+
+INSERT INTO ext_teams VALUES('PPAYMENTS','Payments','payments',NULL,0,1,'');
+
+INSERT INTO ext_schedules_v2 VALUES('PWEEKLY','Weekly','Handoff on Monday morning','America/Los_Angeles','PPAYMENTS','pagerduty','PWEEKLY');
+INSERT INTO ext_rotations VALUES('PWEEKLY1','PWEEKLY','Layer 1','','weekly','','2024-03-04T09:00:00-08:00','09:00:00','monday',0);
+INSERT INTO ext_rotation_members VALUES('PWEEKLY1','PALICE',0);
+INSERT INTO ext_rotation_members VALUES('PWEEKLY1','PBOB',1);
+
+-- Nights only, saved as a daily restriction expanded to every day of the week.
+INSERT INTO ext_schedules_v2 VALUES('PNIGHTS','Nights','','UTC','PPAYMENTS','pagerduty','PNIGHTS');
+INSERT INTO ext_rotations VALUES('PNIGHTS1','PNIGHTS','Layer 1','','custom','PT36H','2024-03-01T00:00:00Z','00:00:00','friday',0);
+INSERT INTO ext_rotation_members VALUES('PNIGHTS1','PCAROL',0);
+INSERT INTO ext_rotation_members VALUES('PNIGHTS1','PBOB',1);
+INSERT INTO ext_rotation_restrictions VALUES('PNIGHTS1','0-0','22:00:00','sunday','06:00:00','monday');
+INSERT INTO ext_rotation_restrictions VALUES('PNIGHTS1','0-1','22:00:00','monday','06:00:00','tuesday');
+INSERT INTO ext_rotation_restrictions VALUES('PNIGHTS1','0-2','22:00:00','tuesday','06:00:00','wednesday');
+INSERT INTO ext_rotation_restrictions VALUES('PNIGHTS1','0-3','22:00:00','wednesday','06:00:00','thursday');
+INSERT INTO ext_rotation_restrictions VALUES('PNIGHTS1','0-4','22:00:00','thursday','06:00:00','friday');
+INSERT INTO ext_rotation_restrictions VALUES('PNIGHTS1','0-5','22:00:00','friday','06:00:00','saturday');
+INSERT INTO ext_rotation_restrictions VALUES('PNIGHTS1','0-6','22:00:00','saturday','06:00:00','sunday');
+INSERT INTO ext_schedule_overrides VALUES('POVERRIDE1','PNIGHTS','alice@example.com','2024-03-05T02:00:00Z','2024-03-05T04:00:00Z');
+
+COMMIT;
@@ -0,0 +1,23 @@
+package store
+
+import (
+	"context"
+	"fmt"
+)
+
+// RecordAppliedResources records resources created in FireHydrant in a single write, so that
+// resources created together by one request are either all recorded or none of them are.
+func RecordAppliedResources(ctx context.Context, resources ...InsertAppliedResourceParams) error {
+	tx, err := FromContext(ctx).conn.Begin()
+	if err != nil {
+		return fmt.Errorf("recording applied resources: %w", err)
+	}
+	q := UseQueries(ctx).WithTx(tx)
+	for _, r := range resources {
+		if err := q.InsertAppliedResource(ctx, r); err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("recording applied %s '%s': %w", r.ResourceType, r.SourceID, err)
+		}
+	}
+	return tx.Commit()
+}
@@ -1,7 +1,42 @@
 package store
 
 const (
-	TARGET_TYPE_USER     = "User"
-	TARGET_TYPE_TEAM     = "Team"
-	TARGET_TYPE_SCHEDULE = "OnCallSchedule"
+	TARGET_TYPE_USER              = "User"
+	TARGET_TYPE_TEAM              = "Team"
+	TARGET_TYPE_SCHEDULE          = "OnCallSchedule"
+	TARGET_TYPE_ESCALATION_POLICY = "EscalationPolicy"
+)
+
+// Import phases, in the order they run.
+const (
+	IMPORT_PHASE_USERS               = "users"
+	IMPORT_PHASE_TEAMS               = "teams"
+	IMPORT_PHASE_SCHEDULES           = "schedules"
+	IMPORT_PHASE_ESCALATION_POLICIES = "escalation_policies"
+)
+
+// Reasons a target of an escalation policy step is skipped.
+const (
+	TARGET_SKIP_UNSUPPORTED_TYPE        = "unsupported_target_type"
+	TARGET_SKIP_UNSUPPORTED_NOTIFY_TYPE = "unsupported_notify_type"
+	TARGET_SKIP_NOT_IMPORTED            = "target_not_imported"
+	TARGET_SKIP_MISSING_FH_USER         = "missing_fh_user"
+)
+
+// How a provider user was linked to their FireHydrant user.
+const (
+	USER_MATCH_EMAIL     = "email"
+	USER_MATCH_SUGGESTED = "suggested"
+	USER_MATCH_USER_MAP  = "user_map"
+	USER_MATCH_ANSWERS   = "answers"
+	USER_MATCH_MANUAL    = "manual"
+	USER_MATCH_CREATED   = "created"
+)
+
+// Kinds of imported resources which may be matched to an existing FireHydrant resource. They are
+// the same as the resource types recorded by the apply mode.
+const (
+	EXISTING_RESOURCE_SCHEDULE          = "on_call_schedule"
+	EXISTING_RESOURCE_ROTATION          = "rotation"
+	EXISTING_RESOURCE_ESCALATION_POLICY = "escalation_policy"
 )
@@ -0,0 +1,40 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// ExistingImportID returns the ID to import an imported resource with, when it was matched to a
+// resource which already exists in FireHydrant, or an empty string otherwise.
+func ExistingImportID(ctx context.Context, resourceType string, sourceID string) (string, error) {
+	existing, err := UseQueries(ctx).GetFhExistingResource(ctx, GetFhExistingResourceParams{
+		ResourceType: resourceType,
+		SourceID:     sourceID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("querying existing %s for '%s': %w", resourceType, sourceID, err)
+	}
+	return existing.ImportID, nil
+}
+
+// ExistingFhID returns the FireHydrant ID of the resource an imported resource was matched to,
+// when it already exists in FireHydrant, or an empty string otherwise.
+func ExistingFhID(ctx context.Context, resourceType string, sourceID string) (string, error) {
+	existing, err := UseQueries(ctx).GetFhExistingResource(ctx, GetFhExistingResourceParams{
+		ResourceType: resourceType,
+		SourceID:     sourceID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("querying existing %s for '%s': %w", resourceType, sourceID, err)
+	}
+	return existing.FhID, nil
+}
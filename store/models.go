@@ -8,6 +8,11 @@ import (
 	"database/sql"
 )
 
+type ExtDefaultEscalationPolicy struct {
+	TeamID             string `json:"team_id"`
+	EscalationPolicyID string `json:"escalation_policy_id"`
+}
+
 type ExtEscalationPolicy struct {
 	ID                string         `json:"id"`
 	Name              string         `json:"name"`
@@ -39,6 +44,14 @@ type ExtMembership struct {
 	TeamID string `json:"team_id"`
 }
 
+type ExtEscalationPolicyStepTargetSkip struct {
+	EscalationPolicyID string `json:"escalation_policy_id"`
+	StepPosition       int64  `json:"step_position"`
+	TargetType         string `json:"target_type"`
+	TargetID           string `json:"target_id"`
+	Reason             string `json:"reason"`
+}
+
 type ExtRotation struct {
 	ID            string `json:"id"`
 	ScheduleID    string `json:"schedule_id"`
@@ -147,6 +160,24 @@ type ExtUser struct {
 	Annotations string         `json:"annotations"`
 }
 
+type ExtUserMatch struct {
+	UserID string `json:"user_id"`
+	Method string `json:"method"`
+}
+
+type FhAppliedResource struct {
+	ResourceType string `json:"resource_type"`
+	SourceID     string `json:"source_id"`
+	FhID         string `json:"fh_id"`
+}
+
+type FhExistingResource struct {
+	ResourceType string `json:"resource_type"`
+	SourceID     string `json:"source_id"`
+	FhID         string `json:"fh_id"`
+	ImportID     string `json:"import_id"`
+}
+
 type FhTeam struct {
 	ID   string `json:"id"`
 	Name string `json:"name"`
@@ -159,6 +190,17 @@ type FhUser struct {
 	Email string `json:"email"`
 }
 
+type ImportPhase struct {
+	Name        string `json:"name"`
+	CompletedAt string `json:"completed_at"`
+}
+
+type ImportSession struct {
+	ID       int64  `json:"id"`
+	Provider string `json:"provider"`
+	Answers  string `json:"answers"`
+}
+
 type LinkedTeam struct {
 	ID          string         `json:"id"`
 	Name        string         `json:"name"`
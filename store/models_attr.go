@@ -66,6 +66,17 @@ func (r *ExtEscalationPolicy) TFSlug() string {
 	return strings.ReplaceAll(slug.Make(r.Name), "-", "_")
 }
 
+// TFSlug produces a Terraform-safe identifier from the schedule name. slug.Make handles
+// non-ASCII characters (emojis, accented letters) that the Terraform HCL parser otherwise
+// rejects in resource labels.
+func (s *ExtSchedulesV2) TFSlug() string {
+	return strings.ReplaceAll(slug.Make(s.Name), "-", "_")
+}
+
+func (r *ExtRotation) TFSlug() string {
+	return strings.ReplaceAll(slug.Make(r.Name), "-", "_")
+}
+
 func (t *ExtTeam) TFSlug() string {
 	if t.Slug == "" {
 		return strings.ReplaceAll(slug.Make(t.Name), "-", "_")
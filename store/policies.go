@@ -0,0 +1,42 @@
+package store
+
+import (
+	"context"
+	"fmt"
+)
+
+// DefaultEscalationPolicyIDs returns the IDs of the escalation policies which are the default of
+// their team: the one chosen for the team, or its only policy when none was chosen. A policy
+// without a team is the default only when it is the only escalation policy migrated.
+func DefaultEscalationPolicyIDs(ctx context.Context) (map[string]bool, error) {
+	q := UseQueries(ctx)
+	chosen, err := q.ListExtDefaultEscalationPolicies(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("querying default escalation policies: %w", err)
+	}
+	policies, err := q.ListExtEscalationPolicies(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("querying escalation policies: %w", err)
+	}
+
+	defaults := map[string]bool{}
+	byTeam := map[string][]string{}
+	for _, p := range policies {
+		if p.TeamID.Valid && p.TeamID.String != "" {
+			byTeam[p.TeamID.String] = append(byTeam[p.TeamID.String], p.ID)
+		}
+	}
+	for _, c := range chosen {
+		defaults[c.EscalationPolicyID] = true
+		delete(byTeam, c.TeamID)
+	}
+	for _, ids := range byTeam {
+		if len(ids) == 1 {
+			defaults[ids[0]] = true
+		}
+	}
+	if len(policies) == 1 && (!policies[0].TeamID.Valid || policies[0].TeamID.String == "") {
+		defaults[policies[0].ID] = true
+	}
+	return defaults, nil
+}
@@ -10,6 +10,9 @@ INSERT INTO fh_users (id, name, email) VALUES (?, ?, ?);
 -- name: GetUserByExtID :one
 SELECT * FROM linked_users WHERE id = ?;
 
+-- name: GetUserByEmail :one
+SELECT * FROM linked_users WHERE email = ? LIMIT 1;
+
 -- name: ListFhUserAnnotations :many
 SELECT annotations FROM linked_users WHERE fh_user_id = ?;
 
@@ -87,6 +90,19 @@ DELETE FROM ext_teams WHERE to_import = 0;
 -- name: LinkExtUser :exec
 UPDATE ext_users SET fh_user_id = ? WHERE id = ?;
 
+-- name: InsertExtUserMatch :exec
+INSERT INTO ext_user_matches (user_id, method) VALUES (?, ?)
+  ON CONFLICT (user_id) DO UPDATE SET method = excluded.method;
+
+-- name: DeleteExtUserMatch :exec
+DELETE FROM ext_user_matches WHERE user_id = ?;
+
+-- name: ListUserMatches :many
+SELECT linked_users.id, linked_users.email, linked_users.fh_user_id, linked_users.fh_email, ext_user_matches.method
+FROM linked_users
+LEFT JOIN ext_user_matches ON ext_user_matches.user_id = linked_users.id
+ORDER BY linked_users.email, linked_users.id;
+
 -- name: LinkExtTeam :exec
 UPDATE ext_teams SET fh_team_id = ? WHERE id = ?;
 
@@ -120,6 +136,9 @@ WHERE ext_teams.id = ?;
 -- name: ListExtEscalationPolicies :many
 SELECT * FROM ext_escalation_policies;
 
+-- name: GetExtEscalationPolicy :one
+SELECT * FROM ext_escalation_policies WHERE id = ?;
+
 -- name: InsertExtEscalationPolicy :exec
 INSERT INTO ext_escalation_policies (id, name, description, team_id, repeat_interval, repeat_limit, handoff_target_type, handoff_target_id, annotations, to_import)
 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
@@ -136,6 +155,13 @@ UPDATE ext_escalation_policies SET to_import = 1 WHERE id = ?;
 -- name: DeleteExtEscalationPolicyUnimported :exec
 DELETE FROM ext_escalation_policies WHERE to_import = 0;
 
+-- name: ListExtDefaultEscalationPolicies :many
+SELECT * FROM ext_default_escalation_policies;
+
+-- name: InsertExtDefaultEscalationPolicy :exec
+INSERT INTO ext_default_escalation_policies (team_id, escalation_policy_id) VALUES (?, ?)
+  ON CONFLICT (team_id) DO UPDATE SET escalation_policy_id = excluded.escalation_policy_id;
+
 -- name: ListExtEscalationPolicySteps :many
 SELECT * FROM ext_escalation_policy_steps
 WHERE escalation_policy_id = ?
@@ -176,6 +202,9 @@ SELECT * FROM ext_rotations WHERE id = ?;
 INSERT INTO ext_rotations (id, schedule_id, name, description, strategy, shift_duration, start_time, handoff_time, handoff_day, rotation_order)
 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
 
+-- name: UpdateExtRotationStartTime :exec
+UPDATE ext_rotations SET start_time = ? WHERE id = ?;
+
 -- name: ListExtRotationMembers :many
 SELECT * FROM ext_rotation_members WHERE rotation_id = ? ORDER BY member_order ASC;
 
@@ -183,6 +212,9 @@ SELECT * FROM ext_rotation_members WHERE rotation_id = ? ORDER BY member_order A
 INSERT INTO ext_rotation_members (rotation_id, user_id, member_order)
 VALUES (?, ?, ?);
 
+-- name: UpdateExtRotationMemberOrder :exec
+UPDATE ext_rotation_members SET member_order = ? WHERE rotation_id = ? AND user_id = ?;
+
 -- name: ListExtRotationRestrictions :many
 SELECT * FROM ext_rotation_restrictions WHERE rotation_id = ? ORDER BY restriction_index ASC;
 
@@ -220,3 +252,94 @@ FROM ext_rotation_member_skips s
 JOIN ext_rotations r      ON r.id  = s.rotation_id
 JOIN ext_schedules_v2 sch ON sch.id = r.schedule_id
 ORDER BY sch.name, r.name, s.user_email;
+
+-- name: InsertExtEscalationPolicyStepTargetSkip :exec
+INSERT INTO ext_escalation_policy_step_target_skips (escalation_policy_id, step_position, target_type, target_id, reason)
+VALUES (?, ?, ?, ?, ?)
+ON CONFLICT (escalation_policy_id, step_position, target_type, target_id) DO UPDATE SET reason = excluded.reason;
+
+-- name: ListEscalationPolicyStepTargetSkips :many
+-- remaining_targets counts the targets of the step which are left once skipped ones are removed.
+SELECT
+    s.escalation_policy_id,
+    p.name AS escalation_policy_name,
+    s.step_position,
+    s.target_type,
+    s.target_id,
+    s.reason,
+    (
+        SELECT COUNT(*) FROM ext_escalation_policy_steps st
+        JOIN ext_escalation_policy_step_targets t ON t.escalation_policy_step_id = st.id
+        WHERE st.escalation_policy_id = s.escalation_policy_id AND st.position = s.step_position
+        AND NOT EXISTS (
+            SELECT 1 FROM ext_escalation_policy_step_target_skips k
+            WHERE k.escalation_policy_id = st.escalation_policy_id AND k.step_position = st.position
+            AND k.target_type = t.target_type AND k.target_id = t.target_id
+        )
+    ) AS remaining_targets
+FROM ext_escalation_policy_step_target_skips s
+JOIN ext_escalation_policies p ON p.id = s.escalation_policy_id
+ORDER BY p.name, s.step_position, s.target_type, s.target_id;
+
+-- name: GetImportSession :one
+SELECT * FROM import_session WHERE id = 1;
+
+-- name: SaveImportSession :exec
+INSERT INTO import_session (id, provider, answers) VALUES (1, ?, ?)
+ON CONFLICT (id) DO UPDATE SET provider = excluded.provider, answers = excluded.answers;
+
+-- name: ListCompletedImportPhases :many
+SELECT name FROM import_phases;
+
+-- name: CompleteImportPhase :exec
+INSERT INTO import_phases (name, completed_at) VALUES (?, ?)
+ON CONFLICT (name) DO UPDATE SET completed_at = excluded.completed_at;
+
+-- name: DeleteExtUsers :exec
+DELETE FROM ext_users;
+
+-- name: DeleteExtUserMatches :exec
+DELETE FROM ext_user_matches;
+
+-- name: DeleteFhUsers :exec
+DELETE FROM fh_users;
+
+-- name: DeleteExtTeams :exec
+DELETE FROM ext_teams;
+
+-- name: DeleteFhTeams :exec
+DELETE FROM fh_teams;
+
+-- name: DeleteExtScheduleOverrides :exec
+DELETE FROM ext_schedule_overrides;
+
+-- name: DeleteExtSchedulesV2 :exec
+DELETE FROM ext_schedules_v2;
+
+-- name: DeleteExtEscalationPolicies :exec
+DELETE FROM ext_escalation_policies;
+
+-- name: DeleteExtDefaultEscalationPolicies :exec
+DELETE FROM ext_default_escalation_policies;
+
+-- name: DeleteExtEscalationPolicyStepTargetSkips :exec
+DELETE FROM ext_escalation_policy_step_target_skips;
+
+-- name: GetAppliedResource :one
+SELECT fh_id FROM fh_applied_resources WHERE resource_type = ? AND source_id = ?;
+
+-- name: InsertAppliedResource :exec
+INSERT INTO fh_applied_resources (resource_type, source_id, fh_id) VALUES (?, ?, ?);
+
+-- name: ListAppliedResources :many
+SELECT * FROM fh_applied_resources;
+
+-- name: GetFhExistingResource :one
+SELECT * FROM fh_existing_resources WHERE resource_type = ? AND source_id = ?;
+
+-- name: InsertFhExistingResource :exec
+INSERT INTO fh_existing_resources (resource_type, source_id, fh_id, import_id) VALUES (?, ?, ?, ?)
+  ON CONFLICT (resource_type, source_id) DO UPDATE SET fh_id = excluded.fh_id, import_id = excluded.import_id;
+
+-- name: DeleteFhExistingResources :exec
+DELETE FROM fh_existing_resources;
@@ -10,6 +10,48 @@ import (
 	"database/sql"
 )
 
+const completeImportPhase = `-- name: CompleteImportPhase :exec
+INSERT INTO import_phases (name, completed_at) VALUES (?, ?)
+ON CONFLICT (name) DO UPDATE SET completed_at = excluded.completed_at
+`
+
+type CompleteImportPhaseParams struct {
+	Name        string `json:"name"`
+	CompletedAt string `json:"completed_at"`
+}
+
+func (q *Queries) CompleteImportPhase(ctx context.Context, arg CompleteImportPhaseParams) error {
+	_, err := q.db.ExecContext(ctx, completeImportPhase, arg.Name, arg.CompletedAt)
+	return err
+}
+
+const deleteExtDefaultEscalationPolicies = `-- name: DeleteExtDefaultEscalationPolicies :exec
+DELETE FROM ext_default_escalation_policies
+`
+
+func (q *Queries) DeleteExtDefaultEscalationPolicies(ctx context.Context) error {
+	_, err := q.db.ExecContext(ctx, deleteExtDefaultEscalationPolicies)
+	return err
+}
+
+const deleteExtEscalationPolicies = `-- name: DeleteExtEscalationPolicies :exec
+DELETE FROM ext_escalation_policies
+`
+
+func (q *Queries) DeleteExtEscalationPolicies(ctx context.Context) error {
+	_, err := q.db.ExecContext(ctx, deleteExtEscalationPolicies)
+	return err
+}
+
+const deleteExtEscalationPolicyStepTargetSkips = `-- name: DeleteExtEscalationPolicyStepTargetSkips :exec
+DELETE FROM ext_escalation_policy_step_target_skips
+`
+
+func (q *Queries) DeleteExtEscalationPolicyStepTargetSkips(ctx context.Context) error {
+	_, err := q.db.ExecContext(ctx, deleteExtEscalationPolicyStepTargetSkips)
+	return err
+}
+
 const deleteExtEscalationPolicyUnimported = `-- name: DeleteExtEscalationPolicyUnimported :exec
 DELETE FROM ext_escalation_policies WHERE to_import = 0
 `
@@ -19,6 +61,24 @@ func (q *Queries) DeleteExtEscalationPolicyUnimported(ctx context.Context) error
 	return err
 }
 
+const deleteExtScheduleOverrides = `-- name: DeleteExtScheduleOverrides :exec
+DELETE FROM ext_schedule_overrides
+`
+
+func (q *Queries) DeleteExtScheduleOverrides(ctx context.Context) error {
+	_, err := q.db.ExecContext(ctx, deleteExtScheduleOverrides)
+	return err
+}
+
+const deleteExtSchedulesV2 = `-- name: DeleteExtSchedulesV2 :exec
+DELETE FROM ext_schedules_v2
+`
+
+func (q *Queries) DeleteExtSchedulesV2(ctx context.Context) error {
+	_, err := q.db.ExecContext(ctx, deleteExtSchedulesV2)
+	return err
+}
+
 const deleteExtTeamUnimported = `-- name: DeleteExtTeamUnimported :exec
 DELETE FROM ext_teams WHERE to_import = 0
 `
@@ -28,6 +88,69 @@ func (q *Queries) DeleteExtTeamUnimported(ctx context.Context) error {
 	return err
 }
 
+const deleteExtTeams = `-- name: DeleteExtTeams :exec
+DELETE FROM ext_teams
+`
+
+func (q *Queries) DeleteExtTeams(ctx context.Context) error {
+	_, err := q.db.ExecContext(ctx, deleteExtTeams)
+	return err
+}
+
+const deleteExtUserMatch = `-- name: DeleteExtUserMatch :exec
+DELETE FROM ext_user_matches WHERE user_id = ?
+`
+
+func (q *Queries) DeleteExtUserMatch(ctx context.Context, userID string) error {
+	_, err := q.db.ExecContext(ctx, deleteExtUserMatch, userID)
+	return err
+}
+
+const deleteExtUserMatches = `-- name: DeleteExtUserMatches :exec
+DELETE FROM ext_user_matches
+`
+
+func (q *Queries) DeleteExtUserMatches(ctx context.Context) error {
+	_, err := q.db.ExecContext(ctx, deleteExtUserMatches)
+	return err
+}
+
+const deleteExtUsers = `-- name: DeleteExtUsers :exec
+DELETE FROM ext_users
+`
+
+func (q *Queries) DeleteExtUsers(ctx context.Context) error {
+	_, err := q.db.ExecContext(ctx, deleteExtUsers)
+	return err
+}
+
+const deleteFhExistingResources = `-- name: DeleteFhExistingResources :exec
+DELETE FROM fh_existing_resources
+`
+
+func (q *Queries) DeleteFhExistingResources(ctx context.Context) error {
+	_, err := q.db.ExecContext(ctx, deleteFhExistingResources)
+	return err
+}
+
+const deleteFhTeams = `-- name: DeleteFhTeams :exec
+DELETE FROM fh_teams
+`
+
+func (q *Queries) DeleteFhTeams(ctx context.Context) error {
+	_, err := q.db.ExecContext(ctx, deleteFhTeams)
+	return err
+}
+
+const deleteFhUsers = `-- name: DeleteFhUsers :exec
+DELETE FROM fh_users
+`
+
+func (q *Queries) DeleteFhUsers(ctx context.Context) error {
+	_, err := q.db.ExecContext(ctx, deleteFhUsers)
+	return err
+}
+
 const deleteUnmatchedExtUsers = `-- name: DeleteUnmatchedExtUsers :exec
 DELETE FROM ext_users
 WHERE fh_user_id IS NULL
@@ -38,6 +161,44 @@ func (q *Queries) DeleteUnmatchedExtUsers(ctx context.Context) error {
 	return err
 }
 
+const getAppliedResource = `-- name: GetAppliedResource :one
+SELECT fh_id FROM fh_applied_resources WHERE resource_type = ? AND source_id = ?
+`
+
+type GetAppliedResourceParams struct {
+	ResourceType string `json:"resource_type"`
+	SourceID     string `json:"source_id"`
+}
+
+func (q *Queries) GetAppliedResource(ctx context.Context, arg GetAppliedResourceParams) (string, error) {
+	row := q.db.QueryRowContext(ctx, getAppliedResource, arg.ResourceType, arg.SourceID)
+	var fh_id string
+	err := row.Scan(&fh_id)
+	return fh_id, err
+}
+
+const getExtEscalationPolicy = `-- name: GetExtEscalationPolicy :one
+SELECT id, name, description, team_id, repeat_limit, repeat_interval, handoff_target_type, handoff_target_id, annotations, to_import FROM ext_escalation_policies WHERE id = ?
+`
+
+func (q *Queries) GetExtEscalationPolicy(ctx context.Context, id string) (ExtEscalationPolicy, error) {
+	row := q.db.QueryRowContext(ctx, getExtEscalationPolicy, id)
+	var i ExtEscalationPolicy
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.TeamID,
+		&i.RepeatLimit,
+		&i.RepeatInterval,
+		&i.HandoffTargetType,
+		&i.HandoffTargetID,
+		&i.Annotations,
+		&i.ToImport,
+	)
+	return i, err
+}
+
 const getExtRotation = `-- name: GetExtRotation :one
 SELECT id, schedule_id, name, description, strategy, shift_duration, start_time, handoff_time, handoff_day, rotation_order FROM ext_rotations WHERE id = ?
 `
@@ -109,6 +270,27 @@ func (q *Queries) GetExtTeamAnnotation(ctx context.Context, id string) (string,
 	return annotations, err
 }
 
+const getFhExistingResource = `-- name: GetFhExistingResource :one
+SELECT resource_type, source_id, fh_id, import_id FROM fh_existing_resources WHERE resource_type = ? AND source_id = ?
+`
+
+type GetFhExistingResourceParams struct {
+	ResourceType string `json:"resource_type"`
+	SourceID     string `json:"source_id"`
+}
+
+func (q *Queries) GetFhExistingResource(ctx context.Context, arg GetFhExistingResourceParams) (FhExistingResource, error) {
+	row := q.db.QueryRowContext(ctx, getFhExistingResource, arg.ResourceType, arg.SourceID)
+	var i FhExistingResource
+	err := row.Scan(
+		&i.ResourceType,
+		&i.SourceID,
+		&i.FhID,
+		&i.ImportID,
+	)
+	return i, err
+}
+
 const getFhUserByEmail = `-- name: GetFhUserByEmail :one
 SELECT id, name, email FROM fh_users WHERE email = ?
 `
@@ -120,6 +302,17 @@ func (q *Queries) GetFhUserByEmail(ctx context.Context, email string) (FhUser, e
 	return i, err
 }
 
+const getImportSession = `-- name: GetImportSession :one
+SELECT id, provider, answers FROM import_session WHERE id = 1
+`
+
+func (q *Queries) GetImportSession(ctx context.Context) (ImportSession, error) {
+	row := q.db.QueryRowContext(ctx, getImportSession)
+	var i ImportSession
+	err := row.Scan(&i.ID, &i.Provider, &i.Answers)
+	return i, err
+}
+
 const getTeamByExtID = `-- name: GetTeamByExtID :one
 SELECT id, name, slug, fh_team_id, is_group, to_import, annotations, fh_name, fh_slug FROM linked_teams WHERE id = ?
 `
@@ -141,6 +334,25 @@ func (q *Queries) GetTeamByExtID(ctx context.Context, id string) (LinkedTeam, er
 	return i, err
 }
 
+const getUserByEmail = `-- name: GetUserByEmail :one
+SELECT id, name, email, fh_user_id, annotations, fh_name, fh_email FROM linked_users WHERE email = ? LIMIT 1
+`
+
+func (q *Queries) GetUserByEmail(ctx context.Context, email string) (LinkedUser, error) {
+	row := q.db.QueryRowContext(ctx, getUserByEmail, email)
+	var i LinkedUser
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Email,
+		&i.FhUserID,
+		&i.Annotations,
+		&i.FhName,
+		&i.FhEmail,
+	)
+	return i, err
+}
+
 const getUserByExtID = `-- name: GetUserByExtID :one
 SELECT id, name, email, fh_user_id, annotations, fh_name, fh_email FROM linked_users WHERE id = ?
 `
@@ -160,6 +372,36 @@ func (q *Queries) GetUserByExtID(ctx context.Context, id string) (LinkedUser, er
 	return i, err
 }
 
+const insertAppliedResource = `-- name: InsertAppliedResource :exec
+INSERT INTO fh_applied_resources (resource_type, source_id, fh_id) VALUES (?, ?, ?)
+`
+
+type InsertAppliedResourceParams struct {
+	ResourceType string `json:"resource_type"`
+	SourceID     string `json:"source_id"`
+	FhID         string `json:"fh_id"`
+}
+
+func (q *Queries) InsertAppliedResource(ctx context.Context, arg InsertAppliedResourceParams) error {
+	_, err := q.db.ExecContext(ctx, insertAppliedResource, arg.ResourceType, arg.SourceID, arg.FhID)
+	return err
+}
+
+const insertExtDefaultEscalationPolicy = `-- name: InsertExtDefaultEscalationPolicy :exec
+INSERT INTO ext_default_escalation_policies (team_id, escalation_policy_id) VALUES (?, ?)
+  ON CONFLICT (team_id) DO UPDATE SET escalation_policy_id = excluded.escalation_policy_id
+`
+
+type InsertExtDefaultEscalationPolicyParams struct {
+	TeamID             string `json:"team_id"`
+	EscalationPolicyID string `json:"escalation_policy_id"`
+}
+
+func (q *Queries) InsertExtDefaultEscalationPolicy(ctx context.Context, arg InsertExtDefaultEscalationPolicyParams) error {
+	_, err := q.db.ExecContext(ctx, insertExtDefaultEscalationPolicy, arg.TeamID, arg.EscalationPolicyID)
+	return err
+}
+
 const insertExtEscalationPolicy = `-- name: InsertExtEscalationPolicy :exec
 INSERT INTO ext_escalation_policies (id, name, description, team_id, repeat_interval, repeat_limit, handoff_target_type, handoff_target_id, annotations, to_import)
 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
@@ -232,6 +474,31 @@ func (q *Queries) InsertExtEscalationPolicyStepTarget(ctx context.Context, arg I
 	return err
 }
 
+const insertExtEscalationPolicyStepTargetSkip = `-- name: InsertExtEscalationPolicyStepTargetSkip :exec
+INSERT INTO ext_escalation_policy_step_target_skips (escalation_policy_id, step_position, target_type, target_id, reason)
+VALUES (?, ?, ?, ?, ?)
+ON CONFLICT (escalation_policy_id, step_position, target_type, target_id) DO UPDATE SET reason = excluded.reason
+`
+
+type InsertExtEscalationPolicyStepTargetSkipParams struct {
+	EscalationPolicyID string `json:"escalation_policy_id"`
+	StepPosition       int64  `json:"step_position"`
+	TargetType         string `json:"target_type"`
+	TargetID           string `json:"target_id"`
+	Reason             string `json:"reason"`
+}
+
+func (q *Queries) InsertExtEscalationPolicyStepTargetSkip(ctx context.Context, arg InsertExtEscalationPolicyStepTargetSkipParams) error {
+	_, err := q.db.ExecContext(ctx, insertExtEscalationPolicyStepTargetSkip,
+		arg.EscalationPolicyID,
+		arg.StepPosition,
+		arg.TargetType,
+		arg.TargetID,
+		arg.Reason,
+	)
+	return err
+}
+
 const insertExtMembership = `-- name: InsertExtMembership :exec
 INSERT INTO ext_memberships (user_id, team_id) VALUES (?, ?)
 `
@@ -460,6 +727,43 @@ func (q *Queries) InsertExtUser(ctx context.Context, arg InsertExtUserParams) er
 	return err
 }
 
+const insertExtUserMatch = `-- name: InsertExtUserMatch :exec
+INSERT INTO ext_user_matches (user_id, method) VALUES (?, ?)
+  ON CONFLICT (user_id) DO UPDATE SET method = excluded.method
+`
+
+type InsertExtUserMatchParams struct {
+	UserID string `json:"user_id"`
+	Method string `json:"method"`
+}
+
+func (q *Queries) InsertExtUserMatch(ctx context.Context, arg InsertExtUserMatchParams) error {
+	_, err := q.db.ExecContext(ctx, insertExtUserMatch, arg.UserID, arg.Method)
+	return err
+}
+
+const insertFhExistingResource = `-- name: InsertFhExistingResource :exec
+INSERT INTO fh_existing_resources (resource_type, source_id, fh_id, import_id) VALUES (?, ?, ?, ?)
+  ON CONFLICT (resource_type, source_id) DO UPDATE SET fh_id = excluded.fh_id, import_id = excluded.import_id
+`
+
+type InsertFhExistingResourceParams struct {
+	ResourceType string `json:"resource_type"`
+	SourceID     string `json:"source_id"`
+	FhID         string `json:"fh_id"`
+	ImportID     string `json:"import_id"`
+}
+
+func (q *Queries) InsertFhExistingResource(ctx context.Context, arg InsertFhExistingResourceParams) error {
+	_, err := q.db.ExecContext(ctx, insertFhExistingResource,
+		arg.ResourceType,
+		arg.SourceID,
+		arg.FhID,
+		arg.ImportID,
+	)
+	return err
+}
+
 const insertFhTeam = `-- name: InsertFhTeam :exec
 INSERT INTO fh_teams (id, name, slug) VALUES (?, ?, ?)
 `
@@ -518,6 +822,152 @@ func (q *Queries) LinkExtUser(ctx context.Context, arg LinkExtUserParams) error
 	return err
 }
 
+const listAppliedResources = `-- name: ListAppliedResources :many
+SELECT resource_type, source_id, fh_id FROM fh_applied_resources
+`
+
+func (q *Queries) ListAppliedResources(ctx context.Context) ([]FhAppliedResource, error) {
+	rows, err := q.db.QueryContext(ctx, listAppliedResources)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []FhAppliedResource
+	for rows.Next() {
+		var i FhAppliedResource
+		if err := rows.Scan(&i.ResourceType, &i.SourceID, &i.FhID); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listCompletedImportPhases = `-- name: ListCompletedImportPhases :many
+SELECT name FROM import_phases
+`
+
+func (q *Queries) ListCompletedImportPhases(ctx context.Context) ([]string, error) {
+	rows, err := q.db.QueryContext(ctx, listCompletedImportPhases)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		items = append(items, name)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listEscalationPolicyStepTargetSkips = `-- name: ListEscalationPolicyStepTargetSkips :many
+SELECT
+    s.escalation_policy_id,
+    p.name AS escalation_policy_name,
+    s.step_position,
+    s.target_type,
+    s.target_id,
+    s.reason,
+    (
+        SELECT COUNT(*) FROM ext_escalation_policy_steps st
+        JOIN ext_escalation_policy_step_targets t ON t.escalation_policy_step_id = st.id
+        WHERE st.escalation_policy_id = s.escalation_policy_id AND st.position = s.step_position
+        AND NOT EXISTS (
+            SELECT 1 FROM ext_escalation_policy_step_target_skips k
+            WHERE k.escalation_policy_id = st.escalation_policy_id AND k.step_position = st.position
+            AND k.target_type = t.target_type AND k.target_id = t.target_id
+        )
+    ) AS remaining_targets
+FROM ext_escalation_policy_step_target_skips s
+JOIN ext_escalation_policies p ON p.id = s.escalation_policy_id
+ORDER BY p.name, s.step_position, s.target_type, s.target_id
+`
+
+type ListEscalationPolicyStepTargetSkipsRow struct {
+	EscalationPolicyID   string `json:"escalation_policy_id"`
+	EscalationPolicyName string `json:"escalation_policy_name"`
+	StepPosition         int64  `json:"step_position"`
+	TargetType           string `json:"target_type"`
+	TargetID             string `json:"target_id"`
+	Reason               string `json:"reason"`
+	RemainingTargets     int64  `json:"remaining_targets"`
+}
+
+// remaining_targets counts the targets of the step which are left once skipped ones are removed.
+func (q *Queries) ListEscalationPolicyStepTargetSkips(ctx context.Context) ([]ListEscalationPolicyStepTargetSkipsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listEscalationPolicyStepTargetSkips)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListEscalationPolicyStepTargetSkipsRow
+	for rows.Next() {
+		var i ListEscalationPolicyStepTargetSkipsRow
+		if err := rows.Scan(
+			&i.EscalationPolicyID,
+			&i.EscalationPolicyName,
+			&i.StepPosition,
+			&i.TargetType,
+			&i.TargetID,
+			&i.Reason,
+			&i.RemainingTargets,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listExtDefaultEscalationPolicies = `-- name: ListExtDefaultEscalationPolicies :many
+SELECT team_id, escalation_policy_id FROM ext_default_escalation_policies
+`
+
+func (q *Queries) ListExtDefaultEscalationPolicies(ctx context.Context) ([]ExtDefaultEscalationPolicy, error) {
+	rows, err := q.db.QueryContext(ctx, listExtDefaultEscalationPolicies)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ExtDefaultEscalationPolicy
+	for rows.Next() {
+		var i ExtDefaultEscalationPolicy
+		if err := rows.Scan(&i.TeamID, &i.EscalationPolicyID); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listExtEscalationPolicies = `-- name: ListExtEscalationPolicies :many
 SELECT id, name, description, team_id, repeat_limit, repeat_interval, handoff_target_type, handoff_target_id, annotations, to_import FROM ext_escalation_policies
 `
@@ -1403,6 +1853,50 @@ func (q *Queries) ListUnmatchedExtUsers(ctx context.Context) ([]ExtUser, error)
 	return items, nil
 }
 
+const listUserMatches = `-- name: ListUserMatches :many
+SELECT linked_users.id, linked_users.email, linked_users.fh_user_id, linked_users.fh_email, ext_user_matches.method
+FROM linked_users
+LEFT JOIN ext_user_matches ON ext_user_matches.user_id = linked_users.id
+ORDER BY linked_users.email, linked_users.id
+`
+
+type ListUserMatchesRow struct {
+	ID       string         `json:"id"`
+	Email    string         `json:"email"`
+	FhUserID sql.NullString `json:"fh_user_id"`
+	FhEmail  sql.NullString `json:"fh_email"`
+	Method   sql.NullString `json:"method"`
+}
+
+func (q *Queries) ListUserMatches(ctx context.Context) ([]ListUserMatchesRow, error) {
+	rows, err := q.db.QueryContext(ctx, listUserMatches)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListUserMatchesRow
+	for rows.Next() {
+		var i ListUserMatchesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Email,
+			&i.FhUserID,
+			&i.FhEmail,
+			&i.Method,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listUsersJoinByEmail = `-- name: ListUsersJoinByEmail :many
 SELECT ext_users.id, ext_users.name, ext_users.email, ext_users.fh_user_id, ext_users.annotations, fh_users.id, fh_users.name, fh_users.email FROM ext_users
   JOIN fh_users ON fh_users.email = ext_users.email
@@ -1472,6 +1966,21 @@ func (q *Queries) MarkExtTeamToImport(ctx context.Context, id string) error {
 	return err
 }
 
+const saveImportSession = `-- name: SaveImportSession :exec
+INSERT INTO import_session (id, provider, answers) VALUES (1, ?, ?)
+ON CONFLICT (id) DO UPDATE SET provider = excluded.provider, answers = excluded.answers
+`
+
+type SaveImportSessionParams struct {
+	Provider string `json:"provider"`
+	Answers  string `json:"answers"`
+}
+
+func (q *Queries) SaveImportSession(ctx context.Context, arg SaveImportSessionParams) error {
+	_, err := q.db.ExecContext(ctx, saveImportSession, arg.Provider, arg.Answers)
+	return err
+}
+
 const updateExtEscalationPolicyTeam = `-- name: UpdateExtEscalationPolicyTeam :exec
 UPDATE ext_escalation_policies SET team_id = ? WHERE id = ?
 `
@@ -1485,3 +1994,32 @@ func (q *Queries) UpdateExtEscalationPolicyTeam(ctx context.Context, arg UpdateE
 	_, err := q.db.ExecContext(ctx, updateExtEscalationPolicyTeam, arg.TeamID, arg.ID)
 	return err
 }
+
+const updateExtRotationMemberOrder = `-- name: UpdateExtRotationMemberOrder :exec
+UPDATE ext_rotation_members SET member_order = ? WHERE rotation_id = ? AND user_id = ?
+`
+
+type UpdateExtRotationMemberOrderParams struct {
+	MemberOrder int64  `json:"member_order"`
+	RotationID  string `json:"rotation_id"`
+	UserID      string `json:"user_id"`
+}
+
+func (q *Queries) UpdateExtRotationMemberOrder(ctx context.Context, arg UpdateExtRotationMemberOrderParams) error {
+	_, err := q.db.ExecContext(ctx, updateExtRotationMemberOrder, arg.MemberOrder, arg.RotationID, arg.UserID)
+	return err
+}
+
+const updateExtRotationStartTime = `-- name: UpdateExtRotationStartTime :exec
+UPDATE ext_rotations SET start_time = ? WHERE id = ?
+`
+
+type UpdateExtRotationStartTimeParams struct {
+	StartTime string `json:"start_time"`
+	ID        string `json:"id"`
+}
+
+func (q *Queries) UpdateExtRotationStartTime(ctx context.Context, arg UpdateExtRotationStartTimeParams) error {
+	_, err := q.db.ExecContext(ctx, updateExtRotationStartTime, arg.StartTime, arg.ID)
+	return err
+}
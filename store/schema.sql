@@ -19,6 +19,12 @@ CREATE VIEW IF NOT EXISTS linked_users AS
   SELECT ext_users.*, fh_users.name as fh_name, fh_users.email as fh_email FROM ext_users
     LEFT JOIN fh_users ON fh_users.id = ext_users.fh_user_id;
 
+-- How each provider user was linked to their FireHydrant user, one of the USER_MATCH_* constants.
+CREATE TABLE IF NOT EXISTS ext_user_matches (
+  user_id TEXT PRIMARY KEY REFERENCES ext_users(id) ON DELETE CASCADE,
+  method TEXT NOT NULL
+) STRICT;
+
 CREATE TABLE IF NOT EXISTS fh_teams (
   id TEXT PRIMARY KEY,
   name TEXT NOT NULL,
@@ -162,6 +168,12 @@ CREATE TABLE IF NOT EXISTS ext_escalation_policies (
   to_import INTEGER NOT NULL DEFAULT 0
 ) STRICT;
 
+-- The escalation policy chosen as the default of each team, which is paged when the team itself is.
+CREATE TABLE IF NOT EXISTS ext_default_escalation_policies (
+  team_id TEXT PRIMARY KEY,
+  escalation_policy_id TEXT NOT NULL REFERENCES ext_escalation_policies(id) ON DELETE CASCADE
+) STRICT;
+
 CREATE TABLE IF NOT EXISTS ext_escalation_policy_steps (
   id TEXT PRIMARY KEY,
   escalation_policy_id TEXT NOT NULL,
@@ -185,3 +197,40 @@ CREATE TABLE IF NOT EXISTS ext_rotation_member_skips (
   reason      TEXT NOT NULL DEFAULT 'missing_fh_user',
   PRIMARY KEY (rotation_id, user_id)
 ) STRICT;
+
+-- Targets of escalation policy steps which could not be migrated. Steps are referred to by position,
+-- as targets may be dropped before their step or policy is saved, or the step never is.
+CREATE TABLE IF NOT EXISTS ext_escalation_policy_step_target_skips (
+  escalation_policy_id TEXT NOT NULL,
+  step_position        INTEGER NOT NULL,
+  target_type          TEXT NOT NULL,
+  target_id            TEXT NOT NULL,
+  reason               TEXT NOT NULL,
+  PRIMARY KEY (escalation_policy_id, step_position, target_type, target_id)
+) STRICT;
+
+CREATE TABLE IF NOT EXISTS import_session (
+  id INTEGER PRIMARY KEY CHECK (id = 1),
+  provider TEXT NOT NULL,
+  answers TEXT NOT NULL DEFAULT '{}'
+) STRICT;
+
+CREATE TABLE IF NOT EXISTS import_phases (
+  name TEXT PRIMARY KEY,
+  completed_at TEXT NOT NULL
+) STRICT;
+
+CREATE TABLE IF NOT EXISTS fh_applied_resources (
+  resource_type TEXT NOT NULL,
+  source_id TEXT NOT NULL,
+  fh_id TEXT NOT NULL,
+  PRIMARY KEY (resource_type, source_id)
+) STRICT;
+
+CREATE TABLE IF NOT EXISTS fh_existing_resources (
+  resource_type TEXT NOT NULL,
+  source_id TEXT NOT NULL,
+  fh_id TEXT NOT NULL,
+  import_id TEXT NOT NULL,
+  PRIMARY KEY (resource_type, source_id)
+) STRICT;
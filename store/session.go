@@ -0,0 +1,38 @@
+package store
+
+import (
+	"context"
+	"fmt"
+)
+
+// FileDSN returns the DSN for a state file on disk. Pragmas are set through the DSN so that
+// they apply to every pooled connection, not only the one which ran the schema.
+func FileDSN(path string) string {
+	return fmt.Sprintf("file:%s?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)", path)
+}
+
+// ResetImportPhase removes whatever an interrupted import phase managed to save, so that the
+// phase can run again from the start. Phases run in order, which means that later phases have
+// nothing saved yet and only the tables of the given phase need to be cleared.
+func ResetImportPhase(ctx context.Context, phase string) error {
+	q := UseQueries(ctx)
+	var resets []func(context.Context) error
+	switch phase {
+	case IMPORT_PHASE_USERS:
+		resets = []func(context.Context) error{q.DeleteExtUserMatches, q.DeleteExtUsers, q.DeleteFhUsers}
+	case IMPORT_PHASE_TEAMS:
+		resets = []func(context.Context) error{q.DeleteExtTeams, q.DeleteFhTeams}
+	case IMPORT_PHASE_SCHEDULES:
+		resets = []func(context.Context) error{q.DeleteExtScheduleOverrides, q.DeleteExtSchedulesV2}
+	case IMPORT_PHASE_ESCALATION_POLICIES:
+		resets = []func(context.Context) error{q.DeleteExtDefaultEscalationPolicies, q.DeleteExtEscalationPolicies, q.DeleteExtEscalationPolicyStepTargetSkips}
+	default:
+		return fmt.Errorf("unknown import phase '%s'", phase)
+	}
+	for _, reset := range resets {
+		if err := reset(ctx); err != nil {
+			return fmt.Errorf("resetting import phase '%s': %w", phase, err)
+		}
+	}
+	return nil
+}
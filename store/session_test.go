@@ -0,0 +1,75 @@
+package store_test
+
+import (
+	"context"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/firehydrant/signals-migrator/store"
+)
+
+func TestImportSessionResume(t *testing.T) {
+	dsn := store.FileDSN(filepath.Join(t.TempDir(), "state.db"))
+
+	ctx := store.WithContextAndDSN(context.Background(), dsn)
+	q := store.UseQueries(ctx)
+	if err := q.SaveImportSession(ctx, store.SaveImportSessionParams{Provider: "PagerDuty", Answers: `{"team_interface":"service"}`}); err != nil {
+		t.Fatal(err)
+	}
+	if err := q.InsertExtUser(ctx, store.InsertExtUserParams{ID: "PUSER", Name: "User", Email: "user@example.com"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := q.CompleteImportPhase(ctx, store.CompleteImportPhaseParams{Name: store.IMPORT_PHASE_USERS, CompletedAt: "2024-01-01T00:00:00Z"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := q.InsertExtTeam(ctx, store.InsertExtTeamParams{ID: "PTEAM", Name: "Team", Slug: "team"}); err != nil {
+		t.Fatal(err)
+	}
+	store.FromContext(ctx).Close()
+
+	// Re-open the same state file, as a re-run of the import would.
+	ctx = store.WithContextAndDSN(context.Background(), dsn)
+	defer store.FromContext(ctx).Close()
+	q = store.UseQueries(ctx)
+
+	session, err := q.GetImportSession(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if session.Provider != "PagerDuty" || session.Answers != `{"team_interface":"service"}` {
+		t.Errorf("unexpected session: %+v", session)
+	}
+	completed, err := q.ListCompletedImportPhases(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(completed, []string{store.IMPORT_PHASE_USERS}) {
+		t.Errorf("completed phases: got %v", completed)
+	}
+
+	// The teams phase was interrupted, so its rows are reset while the users phase is kept.
+	if err := store.ResetImportPhase(ctx, store.IMPORT_PHASE_TEAMS); err != nil {
+		t.Fatal(err)
+	}
+	if teams, err := q.ListExtTeams(ctx); err != nil || len(teams) != 0 {
+		t.Errorf("expected teams to be reset, got %v (err: %v)", teams, err)
+	}
+	if users, err := q.ListExtUsers(ctx); err != nil || len(users) != 1 {
+		t.Errorf("expected users to be kept, got %v (err: %v)", users, err)
+	}
+
+	if err := store.ResetImportPhase(ctx, "unknown"); err == nil {
+		t.Errorf("expected error for unknown phase")
+	}
+}
+
+func TestFileDSNEnforcesForeignKeys(t *testing.T) {
+	ctx := store.WithContextAndDSN(context.Background(), store.FileDSN(filepath.Join(t.TempDir(), "state.db")))
+	defer store.FromContext(ctx).Close()
+
+	err := store.UseQueries(ctx).InsertExtMembership(ctx, store.InsertExtMembershipParams{UserID: "missing", TeamID: "missing"})
+	if sqlErr, ok := store.AsSQLError(err); !ok || !sqlErr.IsForeignKeyConstraint() {
+		t.Errorf("expected foreign key constraint error, got %v", err)
+	}
+}
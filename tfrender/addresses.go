@@ -0,0 +1,226 @@
+package tfrender
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/firehydrant/signals-migrator/diagnostics"
+	"github.com/firehydrant/signals-migrator/store"
+)
+
+// Block types whose labels are assigned by addresses. Data sources are kept apart from resources,
+// as they don't share an address space.
+const (
+	addressUser             = "data.firehydrant_user"
+	addressTeam             = "firehydrant_team"
+	addressSchedule         = "firehydrant_on_call_schedule"
+	addressRotation         = "firehydrant_rotation"
+	addressEscalationPolicy = "firehydrant_escalation_policy"
+)
+
+// addresses assigns the label of every block of the configuration, so that no two blocks of the
+// same type share an address. Labels are derived from names and emails, which may well come out
+// the same for different resources, e.g. alex@corp.com and alex@corp.co.uk, or policies named
+// "Default" on different teams.
+//
+// Labels are assigned before anything is rendered, so that references always agree with the
+// block they refer to.
+type addresses struct {
+	// labels of the blocks of each type, by the key of what they render.
+	labels map[string]map[string]string
+	// used maps the labels in use for each type to the key of their block.
+	used map[string]map[string]string
+}
+
+// claim is a block asking for a label.
+type claim struct {
+	// key identifies what the block renders, e.g. the ID of a schedule.
+	key string
+	// label is the label the block would have without collisions.
+	label string
+	// alternatives are tried in order when label is taken, before falling back to a numeric suffix.
+	alternatives []string
+
+	resource string
+	sourceID string
+	name     string
+}
+
+func newAddresses() *addresses {
+	return &addresses{
+		labels: map[string]map[string]string{},
+		used:   map[string]map[string]string{},
+	}
+}
+
+// label returns the label assigned to a block, or fallback for blocks which were not assigned
+// one, e.g. references to resources which are not rendered.
+func (a *addresses) label(blockType string, key string, fallback string) string {
+	if l, ok := a.labels[blockType][key]; ok {
+		return l
+	}
+	return fallback
+}
+
+// assign assigns a label to each claim of a block type. Claims are sorted by key so that the same
+// block keeps the label it asked for whatever order the resources were fetched in.
+func (a *addresses) assign(ctx context.Context, blockType string, claims []claim) {
+	if a.labels[blockType] == nil {
+		a.labels[blockType] = map[string]string{}
+		a.used[blockType] = map[string]string{}
+	}
+	labels, used := a.labels[blockType], a.used[blockType]
+
+	slices.SortStableFunc(claims, func(x, y claim) int {
+		return strings.Compare(x.key, y.key)
+	})
+	for _, c := range claims {
+		if _, ok := labels[c.key]; ok {
+			continue
+		}
+		label := c.label
+		for _, alt := range c.alternatives {
+			if _, taken := used[label]; !taken {
+				break
+			}
+			label = alt
+		}
+		for i := 2; ; i++ {
+			if _, taken := used[label]; !taken {
+				break
+			}
+			label = fmt.Sprintf("%s_%d", c.label, i)
+		}
+		if label != c.label {
+			diagnostics.Warnf(ctx, c.resource, c.sourceID,
+				"Renamed %s.%s of '%s' to %s.%s, as another block already has this address.\n",
+				blockType, c.label, c.name, blockType, label)
+		}
+		labels[c.key] = label
+		used[label] = c.key
+	}
+}
+
+// assignAddresses assigns the label of every user, team, schedule, rotation and escalation
+// policy. Labels of dependent blocks are built on the labels of the blocks they belong to, so
+// blocks are assigned labels in dependency order.
+func (r *TFRender) assignAddresses(ctx context.Context) error {
+	q := store.UseQueries(ctx)
+	r.addresses = newAddresses()
+
+	users, err := q.ListFhUsers(ctx)
+	if err != nil {
+		return fmt.Errorf("querying users: %w", err)
+	}
+	claims := []claim{}
+	for _, u := range users {
+		claims = append(claims, claim{key: u.ID, label: u.TFSlug(), resource: diagnostics.ResourceUser, sourceID: u.ID, name: u.Email})
+	}
+	r.addresses.assign(ctx, addressUser, claims)
+
+	teams, err := q.ListTeamsToImport(ctx)
+	if err != nil {
+		return fmt.Errorf("querying teams: %w", err)
+	}
+	// Teams sharing a name are merged into a single block, so teams are keyed by name.
+	claims = []claim{}
+	for _, t := range teams {
+		claims = append(claims, claim{key: t.ValidName(), label: t.TFSlug(), resource: diagnostics.ResourceTeam, sourceID: t.ID, name: t.ValidName()})
+	}
+	r.addresses.assign(ctx, addressTeam, claims)
+
+	schedules, err := q.ListExtSchedulesV2(ctx)
+	if err != nil {
+		return fmt.Errorf("querying schedules: %w", err)
+	}
+	claims = []claim{}
+	for _, s := range schedules {
+		teamLabel, err := r.scheduleTeamLabel(ctx, s)
+		if err != nil {
+			return err
+		}
+		claims = append(claims, claim{
+			key:      s.ID,
+			label:    fmt.Sprintf("%s_%s", teamLabel, s.TFSlug()),
+			resource: diagnostics.ResourceSchedule,
+			sourceID: s.ID,
+			name:     s.Name,
+		})
+	}
+	r.addresses.assign(ctx, addressSchedule, claims)
+
+	claims = []claim{}
+	for _, s := range schedules {
+		rotations, err := q.ListExtRotationsByScheduleID(ctx, s.ID)
+		if err != nil {
+			return fmt.Errorf("querying rotations: %w", err)
+		}
+		// The first rotation is declared along with its schedule.
+		for i, rotation := range rotations {
+			if i == 0 {
+				continue
+			}
+			claims = append(claims, claim{
+				key:      rotation.ID,
+				label:    fmt.Sprintf("%s_%s", r.addresses.label(addressSchedule, s.ID, ""), rotation.TFSlug()),
+				resource: diagnostics.ResourceRotation,
+				sourceID: rotation.ID,
+				name:     rotation.Name,
+			})
+		}
+	}
+	r.addresses.assign(ctx, addressRotation, claims)
+
+	policies, err := q.ListExtEscalationPolicies(ctx)
+	if err != nil {
+		return fmt.Errorf("querying escalation policies: %w", err)
+	}
+	claims = []claim{}
+	for _, p := range policies {
+		c := claim{key: p.ID, label: p.TFSlug(), resource: diagnostics.ResourceEscalationPolicy, sourceID: p.ID, name: p.Name}
+		// Policies of different teams commonly share a name, which the team tells apart.
+		teamLabel, err := r.policyTeamLabel(ctx, p)
+		if err != nil {
+			return err
+		}
+		if teamLabel != "" {
+			c.alternatives = []string{fmt.Sprintf("%s_%s", teamLabel, p.TFSlug())}
+		}
+		claims = append(claims, c)
+	}
+	r.addresses.assign(ctx, addressEscalationPolicy, claims)
+	return nil
+}
+
+// teamLabel returns the label of the team block a linked team is rendered into.
+func (r *TFRender) teamLabel(t store.LinkedTeam) string {
+	return r.addresses.label(addressTeam, t.ValidName(), t.TFSlug())
+}
+
+// userLabel returns the label of the data source of a FireHydrant user.
+func (r *TFRender) userLabel(u store.FhUser) string {
+	return r.addresses.label(addressUser, u.ID, u.TFSlug())
+}
+
+// linkedUserLabel returns the label of the data source of the FireHydrant user an external user
+// is linked to.
+func (r *TFRender) linkedUserLabel(u store.LinkedUser) string {
+	if !u.FhUserID.Valid {
+		return u.TFSlug()
+	}
+	return r.addresses.label(addressUser, u.FhUserID.String, u.TFSlug())
+}
+
+func (r *TFRender) scheduleLabel(s store.ExtSchedulesV2, teamLabel string) string {
+	return r.addresses.label(addressSchedule, s.ID, fmt.Sprintf("%s_%s", teamLabel, s.TFSlug()))
+}
+
+func (r *TFRender) rotationLabel(rotation store.ExtRotation, scheduleLabel string) string {
+	return r.addresses.label(addressRotation, rotation.ID, fmt.Sprintf("%s_%s", scheduleLabel, rotation.TFSlug()))
+}
+
+func (r *TFRender) policyLabel(p store.ExtEscalationPolicy) string {
+	return r.addresses.label(addressEscalationPolicy, p.ID, p.TFSlug())
+}
@@ -0,0 +1,52 @@
+package tfrender_test
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/firehydrant/signals-migrator/diagnostics"
+	"github.com/firehydrant/signals-migrator/store"
+	"gotest.tools/v3/golden"
+)
+
+func TestRenderAddressCollisions(t *testing.T) {
+	seed, err := os.ReadFile(filepath.Join("testdata", t.Name()+"_seed.sql"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, tfr := tfrInit(t)
+	ctx = diagnostics.WithCollector(ctx)
+	if _, err := store.FromContext(ctx).ExecContext(ctx, strings.TrimSpace(string(seed))); err != nil {
+		t.Fatal(err)
+	}
+	if err := tfr.Write(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := os.ReadFile(tfr.Filepath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	golden.Assert(t, string(content), goldenFile(tfr.Filename()))
+
+	renames := []string{}
+	for _, e := range diagnostics.FromContext(ctx).Entries() {
+		if strings.HasPrefix(e.Message, "Renamed ") {
+			renames = append(renames, e.Resource+" "+e.SourceID)
+		}
+	}
+	slices.Sort(renames)
+	want := []string{
+		"escalation_policy policy-2",
+		"rotation rotation-1-night-weekend",
+		"schedule schedule-2",
+		"user u-2",
+	}
+	if !slices.Equal(renames, want) {
+		t.Errorf("expected renames of %v, got %v", want, renames)
+	}
+}
@@ -0,0 +1,254 @@
+package tfrender
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// Format is the syntax the Terraform configuration is written in.
+type Format string
+
+const (
+	FormatHCL  Format = "hcl"
+	FormatJSON Format = "json"
+)
+
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(strings.ToLower(s)); f {
+	case FormatHCL, FormatJSON:
+		return f, nil
+	default:
+		return "", fmt.Errorf("unknown output format '%s', expected one of hcl or json", s)
+	}
+}
+
+// SetFormat sets the syntax the configuration is written in. Configuration in the JSON syntax is
+// written to .tf.json files, with comments carried by "//" properties.
+func (r *TFRender) SetFormat(format Format) {
+	r.format = format
+}
+
+// renderJSON renders HCL configuration as Terraform JSON syntax. Comments are kept as the "//"
+// property of the block they are in, which Terraform ignores.
+func renderJSON(src []byte, filename string) ([]byte, error) {
+	f, diags := hclsyntax.ParseConfig(src, filename, hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, fmt.Errorf("parsing %s: %w", filename, diags)
+	}
+	root := f.Body.(*hclsyntax.Body)
+
+	tokens, diags := hclsyntax.LexConfig(src, filename, hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, fmt.Errorf("parsing %s: %w", filename, diags)
+	}
+	c := jsonConverter{src: src, comments: map[*hclsyntax.Body][]string{}}
+	for _, t := range tokens {
+		if t.Type != hclsyntax.TokenComment {
+			continue
+		}
+		body := innermostBody(root, t.Range.Start.Byte)
+		comment := strings.TrimPrefix(strings.TrimPrefix(string(t.Bytes), "#"), "//")
+		c.comments[body] = append(c.comments[body], strings.TrimSpace(comment))
+	}
+
+	b, err := marshalJSON(c.body(root, ""))
+	if err != nil {
+		return nil, fmt.Errorf("rendering %s as JSON: %w", filename, err)
+	}
+	var out bytes.Buffer
+	if err := json.Indent(&out, b, "", "  "); err != nil {
+		return nil, fmt.Errorf("rendering %s as JSON: %w", filename, err)
+	}
+	out.WriteByte('\n')
+	return out.Bytes(), nil
+}
+
+// marshalJSON encodes v without escaping HTML characters, which are common in annotations.
+func marshalJSON(v any) ([]byte, error) {
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(b.Bytes(), []byte("\n")), nil
+}
+
+func innermostBody(body *hclsyntax.Body, offset int) *hclsyntax.Body {
+	for _, block := range body.Blocks {
+		r := block.Body.SrcRange
+		if r.Start.Byte <= offset && offset < r.End.Byte {
+			return innermostBody(block.Body, offset)
+		}
+	}
+	return body
+}
+
+type jsonConverter struct {
+	src      []byte
+	comments map[*hclsyntax.Body][]string
+}
+
+func (c jsonConverter) body(body *hclsyntax.Body, blockType string) *jsonObject {
+	obj := &jsonObject{}
+	if comments := c.comments[body]; len(comments) > 0 {
+		obj.set("//", strings.Join(comments, "\n"))
+	}
+
+	items := make([]hclsyntax.Node, 0, len(body.Attributes)+len(body.Blocks))
+	for _, a := range body.Attributes {
+		items = append(items, a)
+	}
+	for _, block := range body.Blocks {
+		items = append(items, block)
+	}
+	slices.SortFunc(items, func(a, b hclsyntax.Node) int {
+		return a.Range().Start.Byte - b.Range().Start.Byte
+	})
+
+	// Blocks without labels are an object, or an array of objects when repeated. Labels are
+	// nested objects, keyed by each label in turn.
+	unlabeled := map[string][]any{}
+	for _, item := range items {
+		block, ok := item.(*hclsyntax.Block)
+		if !ok {
+			a := item.(*hclsyntax.Attribute)
+			obj.set(a.Name, c.attribute(a, blockType))
+			continue
+		}
+		value := c.body(block.Body, block.Type)
+		if len(block.Labels) == 0 {
+			if _, ok := unlabeled[block.Type]; !ok {
+				obj.set(block.Type, nil)
+			}
+			unlabeled[block.Type] = append(unlabeled[block.Type], value)
+			continue
+		}
+		parent := obj.object(block.Type)
+		for _, label := range block.Labels[:len(block.Labels)-1] {
+			parent = parent.object(label)
+		}
+		parent.set(block.Labels[len(block.Labels)-1], value)
+	}
+	for blockType, values := range unlabeled {
+		if len(values) == 1 {
+			obj.set(blockType, values[0])
+		} else {
+			obj.set(blockType, values)
+		}
+	}
+	return obj
+}
+
+func (c jsonConverter) attribute(a *hclsyntax.Attribute, blockType string) any {
+	// Type constraints and import addresses are expressions in their own right, and not
+	// templates like other strings.
+	if (blockType == "variable" && a.Name == "type") || (blockType == "import" && a.Name == "to") {
+		return c.source(a.Expr)
+	}
+	return c.expression(a.Expr)
+}
+
+func (c jsonConverter) expression(expr hclsyntax.Expression) any {
+	switch e := expr.(type) {
+	case *hclsyntax.ScopeTraversalExpr:
+		return "${" + c.source(e) + "}"
+	case *hclsyntax.TupleConsExpr:
+		values := make([]any, 0, len(e.Exprs))
+		for _, item := range e.Exprs {
+			values = append(values, c.expression(item))
+		}
+		return values
+	case *hclsyntax.ObjectConsExpr:
+		obj := &jsonObject{}
+		for _, item := range e.Items {
+			key := hcl.ExprAsKeyword(item.KeyExpr)
+			if key == "" {
+				if v, diags := item.KeyExpr.Value(nil); !diags.HasErrors() && v.Type() == cty.String {
+					key = v.AsString()
+				} else {
+					key = "${" + c.source(item.KeyExpr) + "}"
+				}
+			}
+			obj.set(key, c.expression(item.ValueExpr))
+		}
+		return obj
+	}
+
+	v, diags := expr.Value(nil)
+	if diags.HasErrors() || !v.IsWhollyKnown() || v.IsNull() {
+		return "${" + c.source(expr) + "}"
+	}
+	switch v.Type() {
+	case cty.String:
+		s := strings.ReplaceAll(v.AsString(), "${", "$${")
+		return strings.ReplaceAll(s, "%{", "%%{")
+	case cty.Number:
+		return json.Number(v.AsBigFloat().Text('f', -1))
+	case cty.Bool:
+		return v.True()
+	default:
+		return "${" + c.source(expr) + "}"
+	}
+}
+
+func (c jsonConverter) source(expr hclsyntax.Expression) string {
+	return string(expr.Range().SliceBytes(c.src))
+}
+
+// jsonObject is a JSON object which keeps its properties in the order they are set, so that the
+// configuration reads in the same order in either syntax.
+type jsonObject struct {
+	keys   []string
+	values map[string]any
+}
+
+func (o *jsonObject) set(key string, value any) {
+	if o.values == nil {
+		o.values = map[string]any{}
+	}
+	if _, ok := o.values[key]; !ok {
+		o.keys = append(o.keys, key)
+	}
+	o.values[key] = value
+}
+
+// object returns the object at key, setting it first if needed.
+func (o *jsonObject) object(key string) *jsonObject {
+	if child, ok := o.values[key].(*jsonObject); ok {
+		return child
+	}
+	child := &jsonObject{}
+	o.set(key, child)
+	return child
+}
+
+func (o *jsonObject) MarshalJSON() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteByte('{')
+	for i, key := range o.keys {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		k, err := marshalJSON(key)
+		if err != nil {
+			return nil, err
+		}
+		v, err := marshalJSON(o.values[key])
+		if err != nil {
+			return nil, err
+		}
+		b.Write(k)
+		b.WriteByte(':')
+		b.Write(v)
+	}
+	b.WriteByte('}')
+	return b.Bytes(), nil
+}
@@ -0,0 +1,88 @@
+package tfrender_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/firehydrant/signals-migrator/store"
+	"github.com/firehydrant/signals-migrator/tfrender"
+	"github.com/hashicorp/hcl/v2/json"
+	"gotest.tools/v3/golden"
+)
+
+func TestParseFormat(t *testing.T) {
+	for in, want := range map[string]tfrender.Format{
+		"hcl":  tfrender.FormatHCL,
+		"JSON": tfrender.FormatJSON,
+	} {
+		got, err := tfrender.ParseFormat(in)
+		if err != nil {
+			t.Errorf("unexpected error parsing %q: %s", in, err)
+		}
+		if got != want {
+			t.Errorf("expected %q to parse as %q, got %q", in, want, got)
+		}
+	}
+	if _, err := tfrender.ParseFormat("yaml"); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
+
+// assertRenderJSON renders a seed in the JSON syntax, checks that every file written is valid
+// Terraform JSON, and compares the entry point against its golden file.
+func assertRenderJSON(seedPath string, layout tfrender.Layout) func(t *testing.T) {
+	return func(t *testing.T) {
+		seed, err := os.ReadFile(seedPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ctx, tfr := tfrInit(t)
+		if _, err := store.FromContext(ctx).ExecContext(ctx, strings.TrimSpace(string(seed))); err != nil {
+			t.Fatal(err)
+		}
+		tfr.SetLayout(layout)
+		tfr.SetFormat(tfrender.FormatJSON)
+		if err := tfr.Write(ctx); err != nil {
+			t.Fatal(err)
+		}
+
+		err = filepath.WalkDir(filepath.Dir(tfr.Filepath()), func(path string, d os.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return err
+			}
+			if !strings.HasSuffix(path, ".tf.json") {
+				t.Errorf("expected only .tf.json files, found %s", path)
+			}
+			if _, diags := json.ParseFile(path); diags.HasErrors() {
+				t.Errorf("%s is not valid Terraform JSON: %s", path, diags)
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		content, err := os.ReadFile(tfr.Filepath())
+		if err != nil {
+			t.Fatal(err)
+		}
+		golden.Assert(t, string(content), t.Name()+".golden.tf.json")
+	}
+}
+
+func TestRenderJSON(t *testing.T) {
+	// Annotations, handoffs and repeats are carried over as "//" comments.
+	t.Run("EscalationPolicyHandoff", assertRenderJSON(
+		filepath.Join("testdata", "TestRenderOpsgenie", "EscalationPolicyHandoff_seed.sql"),
+		tfrender.LayoutSingle,
+	))
+
+	// Import blocks, module calls and their inputs in the root module.
+	t.Run("Modules", assertRenderJSON(
+		filepath.Join("testdata", "TestRenderLayout", "seed.sql"),
+		tfrender.LayoutModules,
+	))
+}
@@ -0,0 +1,277 @@
+package tfrender
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// Layout is how the Terraform configuration is split across files.
+type Layout string
+
+const (
+	// LayoutSingle writes everything into a single file.
+	LayoutSingle Layout = "single"
+	// LayoutFiles writes the resources of each team into their own file of the root module.
+	LayoutFiles Layout = "files"
+	// LayoutModules writes the resources of each team into their own module, which the root
+	// module wires together.
+	LayoutModules Layout = "modules"
+)
+
+func ParseLayout(s string) (Layout, error) {
+	switch l := Layout(strings.ToLower(s)); l {
+	case LayoutSingle, LayoutFiles, LayoutModules:
+		return l, nil
+	default:
+		return "", fmt.Errorf("unknown output layout '%s', expected one of single, files or modules", s)
+	}
+}
+
+// tfModule is what a team module exchanges with the rest of the configuration.
+type tfModule struct {
+	// users are the slugs of the users the module refers to, passed in by the root module.
+	users map[string]bool
+	// inputs are the IDs of resources outside of the module, by variable name, along with
+	// the expression the root module passes in.
+	inputs map[string]hcl.Traversal
+	// outputs are the IDs of resources of the module which are used outside of it.
+	outputs map[string]hcl.Traversal
+}
+
+// SetLayout sets how the configuration is split across files. In the files and modules layouts,
+// the configuration is written to the directory of the path given to New, with its entry point
+// in main.tf.
+func (r *TFRender) SetLayout(layout Layout) {
+	r.layout = layout
+	if layout != LayoutSingle {
+		r.filename = "main.tf"
+	}
+}
+
+// file returns the file at the given path relative to the output directory, creating it the
+// first time. Files which are the entry point of a module declare the FireHydrant provider.
+func (r *TFRender) file(name string, entrypoint bool) *hclwrite.File {
+	if name == r.filename {
+		return r.f
+	}
+	if f, ok := r.files[name]; ok {
+		return f
+	}
+	f := hclwrite.NewEmptyFile()
+	if entrypoint {
+		appendRequiredProviders(f.Body())
+	}
+	r.files[name] = f
+	return f
+}
+
+// body returns the body the resources of the given team are rendered into. Resources without
+// a team are rendered into the root module.
+func (r *TFRender) body(team string) *hclwrite.Body {
+	switch {
+	case r.layout == LayoutSingle || team == "":
+		return r.root
+	case r.layout == LayoutFiles:
+		return r.file(fmt.Sprintf("team_%s.tf", team), false).Body()
+	default:
+		r.module(team)
+		return r.file(filepath.Join("teams", team, "main.tf"), true).Body()
+	}
+}
+
+// usersBody returns the body the FireHydrant user data sources are rendered into.
+func (r *TFRender) usersBody() *hclwrite.Body {
+	if r.layout == LayoutSingle {
+		return r.root
+	}
+	return r.file("users.tf", false).Body()
+}
+
+func (r *TFRender) module(team string) *tfModule {
+	m, ok := r.modules[team]
+	if !ok {
+		m = &tfModule{
+			users:   map[string]bool{},
+			inputs:  map[string]hcl.Traversal{},
+			outputs: map[string]hcl.Traversal{},
+		}
+		r.modules[team] = m
+	}
+	return m
+}
+
+// userRef refers to the ID of a FireHydrant user from a resource of the given team.
+func (r *TFRender) userRef(team string, userSlug string) hcl.Traversal {
+	if r.layout != LayoutModules || team == "" {
+		return hcl.Traversal{
+			hcl.TraverseRoot{Name: "data"},
+			hcl.TraverseAttr{Name: "firehydrant_user"},
+			hcl.TraverseAttr{Name: userSlug},
+			hcl.TraverseAttr{Name: "id"},
+		}
+	}
+	r.module(team).users[userSlug] = true
+	return hcl.Traversal{
+		hcl.TraverseRoot{Name: "var"},
+		hcl.TraverseAttr{Name: "users"},
+		hcl.TraverseIndex{Key: cty.StringVal(userSlug)},
+	}
+}
+
+// ref refers to the ID of a resource of the owner team from a resource of the given team. Across
+// modules, the ID is an output of the owner's module which is passed in as a variable.
+func (r *TFRender) ref(team string, owner string, resourceType string, name string) hcl.Traversal {
+	id := hcl.Traversal{
+		hcl.TraverseRoot{Name: resourceType},
+		hcl.TraverseAttr{Name: name},
+		hcl.TraverseAttr{Name: "id"},
+	}
+	if r.layout != LayoutModules || team == owner {
+		return id
+	}
+
+	output := fmt.Sprintf("%s_%s_id", strings.TrimPrefix(resourceType, "firehydrant_"), name)
+	if owner != "" {
+		r.module(owner).outputs[output] = id
+		id = hcl.Traversal{
+			hcl.TraverseRoot{Name: "module"},
+			hcl.TraverseAttr{Name: owner},
+			hcl.TraverseAttr{Name: output},
+		}
+	}
+	if team == "" {
+		return id
+	}
+	r.module(team).inputs[output] = id
+	return hcl.Traversal{
+		hcl.TraverseRoot{Name: "var"},
+		hcl.TraverseAttr{Name: output},
+	}
+}
+
+// appendImport declares that an existing FireHydrant resource is imported as a resource of the
+// given team. Import blocks are only allowed in the root module.
+func (r *TFRender) appendImport(team string, resourceType string, name string, id string) {
+	to := hcl.Traversal{
+		hcl.TraverseRoot{Name: resourceType},
+		hcl.TraverseAttr{Name: name},
+	}
+	b := r.body(team)
+	if r.layout == LayoutModules && team != "" {
+		b = r.root
+		to = append(hcl.Traversal{
+			hcl.TraverseRoot{Name: "module"},
+			hcl.TraverseAttr{Name: team},
+			hcl.TraverseAttr{Name: resourceType},
+		}, to[1:]...)
+	}
+	b.AppendNewline()
+	importBody := b.AppendNewBlock("import", []string{}).Body()
+	importBody.SetAttributeValue("id", cty.StringVal(id))
+	importBody.SetAttributeTraversal("to", to)
+}
+
+// renderModules declares the module of each team in the root module, along with the variables
+// and outputs they exchange.
+func (r *TFRender) renderModules() {
+	teams := make([]string, 0, len(r.modules))
+	for team := range r.modules {
+		teams = append(teams, team)
+	}
+	slices.Sort(teams)
+
+	for _, team := range teams {
+		m := r.modules[team]
+
+		r.root.AppendNewline()
+		call := r.root.AppendNewBlock("module", []string{team}).Body()
+		call.SetAttributeValue("source", cty.StringVal("./"+filepath.ToSlash(filepath.Join("teams", team))))
+
+		vars := r.file(filepath.Join("teams", team, "variables.tf"), false).Body()
+		if len(m.users) > 0 {
+			users := []hclwrite.ObjectAttrTokens{}
+			for _, u := range sortedKeys(m.users) {
+				users = append(users, hclwrite.ObjectAttrTokens{
+					Name:  hclwrite.TokensForIdentifier(u),
+					Value: hclwrite.TokensForTraversal(r.userRef("", u)),
+				})
+			}
+			call.SetAttributeRaw("users", hclwrite.TokensForObject(users))
+
+			v := vars.AppendNewBlock("variable", []string{"users"}).Body()
+			v.SetAttributeValue("description", cty.StringVal("IDs of the FireHydrant users referred to by this team, by their slug."))
+			v.SetAttributeRaw("type", hclwrite.TokensForFunctionCall("map", hclwrite.TokensForIdentifier("string")))
+		}
+		for _, name := range sortedKeys(m.inputs) {
+			call.SetAttributeTraversal(name, m.inputs[name])
+
+			vars.AppendNewline()
+			vars.AppendNewBlock("variable", []string{name}).Body().
+				SetAttributeRaw("type", hclwrite.TokensForIdentifier("string"))
+		}
+
+		if len(m.outputs) > 0 {
+			outputs := r.file(filepath.Join("teams", team, "outputs.tf"), false).Body()
+			for _, name := range sortedKeys(m.outputs) {
+				outputs.AppendNewline()
+				outputs.AppendNewBlock("output", []string{name}).Body().
+					SetAttributeTraversal("value", m.outputs[name])
+			}
+		}
+	}
+}
+
+// contents returns the HCL of every file of the configuration, by their path relative to dir.
+func (r *TFRender) contents() map[string][]byte {
+	contents := map[string][]byte{r.filename: r.f.Bytes()}
+	for name, f := range r.files {
+		contents[name] = f.Bytes()
+	}
+	return contents
+}
+
+// writeFiles writes every file of the configuration, and returns their paths.
+func (r *TFRender) writeFiles(contents map[string][]byte) ([]string, error) {
+	paths := []string{}
+	for _, name := range sortedKeys(contents) {
+		content := contents[name]
+		if r.layout != LayoutSingle {
+			content = bytes.TrimLeft(content, "\n")
+			if len(content) == 0 {
+				continue
+			}
+		}
+		if r.format == FormatJSON {
+			var err error
+			if content, err = renderJSON(content, name); err != nil {
+				return nil, err
+			}
+		}
+		path := filepath.Join(r.dir, r.outputName(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return nil, fmt.Errorf("preparing output directory: %w", err)
+		}
+		if err := os.WriteFile(path, content, 0644); err != nil {
+			return nil, fmt.Errorf("writing file: %w", err)
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
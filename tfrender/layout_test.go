@@ -0,0 +1,84 @@
+package tfrender_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/firehydrant/signals-migrator/store"
+	"github.com/firehydrant/signals-migrator/tfrender"
+	"gotest.tools/v3/golden"
+)
+
+func TestParseLayout(t *testing.T) {
+	for in, want := range map[string]tfrender.Layout{
+		"single":  tfrender.LayoutSingle,
+		"Files":   tfrender.LayoutFiles,
+		"modules": tfrender.LayoutModules,
+	} {
+		got, err := tfrender.ParseLayout(in)
+		if err != nil {
+			t.Errorf("unexpected error parsing %q: %s", in, err)
+		}
+		if got != want {
+			t.Errorf("expected %q to parse as %q, got %q", in, want, got)
+		}
+	}
+	if _, err := tfrender.ParseLayout("workspaces"); err == nil {
+		t.Error("expected an error for an unknown layout")
+	}
+}
+
+// assertRenderLayout renders the seed in the given layout, and compares every file written
+// against a single golden file.
+func assertRenderLayout(layout tfrender.Layout) func(t *testing.T) {
+	return func(t *testing.T) {
+		seed, err := os.ReadFile(filepath.Join("testdata", "TestRenderLayout", "seed.sql"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ctx, tfr := tfrInit(t)
+		if _, err := store.FromContext(ctx).ExecContext(ctx, strings.TrimSpace(string(seed))); err != nil {
+			t.Fatal(err)
+		}
+		tfr.SetLayout(layout)
+		if err := tfr.Write(ctx); err != nil {
+			t.Fatal(err)
+		}
+
+		dir := filepath.Dir(tfr.Filepath())
+		var b strings.Builder
+		err = filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return err
+			}
+			content, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			rel, err := filepath.Rel(dir, path)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(&b, "### %s\n%s\n", filepath.ToSlash(rel), content)
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		golden.Assert(t, b.String(), t.Name()+".golden.tf")
+	}
+}
+
+func TestRenderLayout(t *testing.T) {
+	// One file per team, sharing the root module with users.tf and main.tf.
+	t.Run("Files", assertRenderLayout(tfrender.LayoutFiles))
+
+	// One module per team. References across teams go through outputs and variables, and the
+	// imports of the existing team, schedule and policy are declared in the root module.
+	t.Run("Modules", assertRenderLayout(tfrender.LayoutModules))
+}
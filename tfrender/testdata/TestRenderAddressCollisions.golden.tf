@@ -0,0 +1,140 @@
+terraform {
+  required_providers {
+    firehydrant = {
+      source  = "firehydrant/firehydrant"
+      version = ">= 0.15.2"
+    }
+  }
+}
+
+data "firehydrant_user" "alex" {
+  email = "alex@corp.com"
+}
+
+data "firehydrant_user" "alex_2" {
+  email = "alex@corp.co.uk"
+}
+
+resource "firehydrant_team" "payments" {
+  name = "Payments"
+
+  memberships {
+    user_id = data.firehydrant_user.alex.id
+  }
+
+  memberships {
+    user_id = data.firehydrant_user.alex_2.id
+  }
+}
+
+resource "firehydrant_team" "platform" {
+  name = "Platform"
+}
+
+resource "firehydrant_on_call_schedule" "payments_primary" {
+  name          = "Primary"
+  team_id       = firehydrant_team.payments.id
+  rotation_name = "Day"
+  time_zone     = "UTC"
+
+  member_ids = [data.firehydrant_user.alex.id]
+
+  strategy {
+    type         = "weekly"
+    handoff_day  = "monday"
+    handoff_time = "08:00:00"
+  }
+}
+
+resource "firehydrant_on_call_schedule" "payments_primary_2" {
+  name          = "primary"
+  team_id       = firehydrant_team.payments.id
+  rotation_name = "Day"
+  time_zone     = "UTC"
+
+  member_ids = [data.firehydrant_user.alex_2.id]
+
+  strategy {
+    type         = "weekly"
+    handoff_day  = "monday"
+    handoff_time = "08:00:00"
+  }
+}
+
+resource "firehydrant_rotation" "payments_primary_night" {
+  name        = "Night"
+  team_id     = firehydrant_team.payments.id
+  schedule_id = firehydrant_on_call_schedule.payments_primary.id
+  time_zone   = "UTC"
+
+  members {
+    user_id = data.firehydrant_user.alex_2.id
+  }
+
+  strategy {
+    type         = "weekly"
+    handoff_day  = "monday"
+    handoff_time = "20:00:00"
+  }
+}
+
+resource "firehydrant_rotation" "payments_primary_night_2" {
+  name        = "Night!"
+  team_id     = firehydrant_team.payments.id
+  schedule_id = firehydrant_on_call_schedule.payments_primary.id
+  time_zone   = "UTC"
+
+  members {
+    user_id = data.firehydrant_user.alex_2.id
+  }
+
+  strategy {
+    type         = "weekly"
+    handoff_day  = "saturday"
+    handoff_time = "20:00:00"
+  }
+}
+
+resource "firehydrant_escalation_policy" "default" {
+  name    = "Default"
+  team_id = firehydrant_team.payments.id
+
+  step {
+    timeout = "PT5M"
+
+    targets {
+      type = "OnCallSchedule"
+      id   = firehydrant_on_call_schedule.payments_primary_2.id
+    }
+
+    targets {
+      type = "User"
+      id   = data.firehydrant_user.alex_2.id
+    }
+  }
+
+  repetitions = 0
+  default     = "true"
+
+  handoff_step {
+    target_type = "EscalationPolicy"
+    target_id   = firehydrant_escalation_policy.platform_default.id
+  }
+}
+
+resource "firehydrant_escalation_policy" "platform_default" {
+  name    = "Default"
+  team_id = firehydrant_team.platform.id
+
+  step {
+    timeout = "PT5M"
+
+    targets {
+      type = "User"
+      id   = data.firehydrant_user.alex.id
+    }
+  }
+
+  repetitions = 0
+  default     = "true"
+}
@@ -0,0 +1,43 @@
+BEGIN TRANSACTION;
+
+-- Both users would be data.firehydrant_user.alex.
+INSERT INTO fh_users VALUES('u-1','Alex','alex@corp.com');
+INSERT INTO fh_users VALUES('u-2','Alex UK','alex@corp.co.uk');
+
+INSERT INTO ext_users VALUES('ext-u-1','Alex','alex@corp.com','u-1','');
+INSERT INTO ext_users VALUES('ext-u-2','Alex UK','alex@corp.co.uk','u-2','');
+
+INSERT INTO ext_teams VALUES('team-payments','Payments','payments',NULL,0,1,'');
+INSERT INTO ext_teams VALUES('team-platform','Platform','platform',NULL,0,1,'');
+
+INSERT INTO ext_memberships VALUES('ext-u-1','team-payments');
+INSERT INTO ext_memberships VALUES('ext-u-2','team-payments');
+
+-- Both schedules would be firehydrant_on_call_schedule.payments_primary, and the rotations of the
+-- first one firehydrant_rotation.payments_primary_night.
+INSERT INTO ext_schedules_v2 VALUES('schedule-1','Primary','','UTC','team-payments','opsgenie','schedule-1');
+INSERT INTO ext_schedules_v2 VALUES('schedule-2','primary','','UTC','team-payments','opsgenie','schedule-2');
+
+INSERT INTO ext_rotations VALUES('rotation-1-day','schedule-1','Day','','weekly','','','08:00:00','monday',0);
+INSERT INTO ext_rotations VALUES('rotation-1-night','schedule-1','Night','','weekly','','','20:00:00','monday',1);
+INSERT INTO ext_rotations VALUES('rotation-1-night-weekend','schedule-1','Night!','','weekly','','','20:00:00','saturday',2);
+INSERT INTO ext_rotations VALUES('rotation-2-day','schedule-2','Day','','weekly','','','08:00:00','monday',0);
+
+INSERT INTO ext_rotation_members VALUES('rotation-1-day','ext-u-1',0);
+INSERT INTO ext_rotation_members VALUES('rotation-1-night','ext-u-2',0);
+INSERT INTO ext_rotation_members VALUES('rotation-1-night-weekend','ext-u-2',0);
+INSERT INTO ext_rotation_members VALUES('rotation-2-day','ext-u-2',0);
+
+-- Both policies would be firehydrant_escalation_policy.default. The one of Payments hands off to
+-- the one of Platform.
+INSERT INTO ext_escalation_policies VALUES('policy-1','Default','','team-payments',0,NULL,'EscalationPolicy','policy-2','',1);
+INSERT INTO ext_escalation_policies VALUES('policy-2','Default','','team-platform',0,NULL,'','','',1);
+
+INSERT INTO ext_escalation_policy_steps VALUES('policy-1-0','policy-1',0,'PT5M');
+INSERT INTO ext_escalation_policy_steps VALUES('policy-2-0','policy-2',0,'PT5M');
+
+INSERT INTO ext_escalation_policy_step_targets VALUES('policy-1-0','OnCallSchedule','schedule-2');
+INSERT INTO ext_escalation_policy_step_targets VALUES('policy-1-0','User','ext-u-2');
+INSERT INTO ext_escalation_policy_step_targets VALUES('policy-2-0','User','ext-u-1');
+
+COMMIT;
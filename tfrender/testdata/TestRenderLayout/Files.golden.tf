@@ -0,0 +1,154 @@
+### main.tf
+terraform {
+  required_providers {
+    firehydrant = {
+      source  = "firehydrant/firehydrant"
+      version = ">= 0.15.2"
+    }
+  }
+}
+
+resource "firehydrant_escalation_policy" "company_escalation" {
+  name = "Company_escalation"
+
+  step {
+    timeout = "PT5M"
+
+    targets {
+      type = "OnCallSchedule"
+      id   = firehydrant_on_call_schedule.platform_primary.id
+    }
+  }
+
+  repetitions = 0
+  default     = "false"
+
+  handoff_step {
+    target_type = "EscalationPolicy"
+    target_id   = firehydrant_escalation_policy.payments_escalation.id
+  }
+}
+
+### team_payments.tf
+resource "firehydrant_team" "payments" {
+  name = "Payments"
+
+  memberships {
+    user_id = data.firehydrant_user.jsmith.id
+  }
+}
+
+resource "firehydrant_escalation_policy" "payments_escalation" {
+  name    = "Payments_escalation"
+  team_id = firehydrant_team.payments.id
+
+  step {
+    timeout = "PT10M"
+
+    targets {
+      type = "OnCallSchedule"
+      id   = firehydrant_on_call_schedule.platform_primary.id
+    }
+
+    targets {
+      type = "User"
+      id   = data.firehydrant_user.jsmith.id
+    }
+  }
+
+  repetitions = 0
+  default     = "true"
+
+  handoff_step {
+    target_type = "Team"
+    target_id   = firehydrant_team.platform.id
+  }
+}
+
+### team_platform.tf
+resource "firehydrant_team" "platform" {
+  name = "Platform"
+
+  memberships {
+    user_id = data.firehydrant_user.mika.id
+  }
+}
+
+import {
+  id = "f1d2c3b4-a5e6-4f70-8192-a3b4c5d6e7f8"
+  to = firehydrant_team.platform
+}
+
+resource "firehydrant_on_call_schedule" "platform_primary" {
+  name          = "Primary"
+  team_id       = firehydrant_team.platform.id
+  rotation_name = "Day"
+  time_zone     = "America/Los_Angeles"
+
+  member_ids = [data.firehydrant_user.mika.id]
+
+  strategy {
+    type         = "weekly"
+    handoff_day  = "monday"
+    handoff_time = "08:00:00"
+  }
+}
+
+import {
+  id = "f1d2c3b4-a5e6-4f70-8192-a3b4c5d6e7f8:5c8f2a3e-1d4b-4f6a-9e7c-2b3d4e5f6a7b"
+  to = firehydrant_on_call_schedule.platform_primary
+}
+
+resource "firehydrant_rotation" "platform_primary_night" {
+  name        = "Night"
+  team_id     = firehydrant_team.platform.id
+  schedule_id = firehydrant_on_call_schedule.platform_primary.id
+  time_zone   = "America/Los_Angeles"
+
+  members {
+    user_id = data.firehydrant_user.mika.id
+  }
+
+  strategy {
+    type         = "weekly"
+    handoff_day  = "monday"
+    handoff_time = "20:00:00"
+  }
+}
+
+import {
+  id = "f1d2c3b4-a5e6-4f70-8192-a3b4c5d6e7f8:5c8f2a3e-1d4b-4f6a-9e7c-2b3d4e5f6a7b:9b2c3d4e-5f6a-4b7c-9d8e-0f1a2b3c4d5e"
+  to = firehydrant_rotation.platform_primary_night
+}
+
+resource "firehydrant_escalation_policy" "platform_escalation" {
+  name    = "Platform_escalation"
+  team_id = firehydrant_team.platform.id
+
+  step {
+    timeout = "PT15M"
+
+    targets {
+      type = "User"
+      id   = data.firehydrant_user.mika.id
+    }
+  }
+
+  repetitions = 0
+  default     = "true"
+}
+
+import {
+  id = "f1d2c3b4-a5e6-4f70-8192-a3b4c5d6e7f8:d4e5f6a7-b8c9-4d0e-8f1a-2b3c4d5e6f7a"
+  to = firehydrant_escalation_policy.platform_escalation
+}
+
+### users.tf
+data "firehydrant_user" "jsmith" {
+  email = "jsmith@example.com"
+}
+
+data "firehydrant_user" "mika" {
+  email = "mika@example.com"
+}
+
@@ -0,0 +1,222 @@
+### main.tf
+terraform {
+  required_providers {
+    firehydrant = {
+      source  = "firehydrant/firehydrant"
+      version = ">= 0.15.2"
+    }
+  }
+}
+
+import {
+  id = "f1d2c3b4-a5e6-4f70-8192-a3b4c5d6e7f8"
+  to = module.platform.firehydrant_team.platform
+}
+
+import {
+  id = "f1d2c3b4-a5e6-4f70-8192-a3b4c5d6e7f8:5c8f2a3e-1d4b-4f6a-9e7c-2b3d4e5f6a7b"
+  to = module.platform.firehydrant_on_call_schedule.platform_primary
+}
+
+import {
+  id = "f1d2c3b4-a5e6-4f70-8192-a3b4c5d6e7f8:5c8f2a3e-1d4b-4f6a-9e7c-2b3d4e5f6a7b:9b2c3d4e-5f6a-4b7c-9d8e-0f1a2b3c4d5e"
+  to = module.platform.firehydrant_rotation.platform_primary_night
+}
+
+import {
+  id = "f1d2c3b4-a5e6-4f70-8192-a3b4c5d6e7f8:d4e5f6a7-b8c9-4d0e-8f1a-2b3c4d5e6f7a"
+  to = module.platform.firehydrant_escalation_policy.platform_escalation
+}
+
+resource "firehydrant_escalation_policy" "company_escalation" {
+  name = "Company_escalation"
+
+  step {
+    timeout = "PT5M"
+
+    targets {
+      type = "OnCallSchedule"
+      id   = module.platform.on_call_schedule_platform_primary_id
+    }
+  }
+
+  repetitions = 0
+  default     = "false"
+
+  handoff_step {
+    target_type = "EscalationPolicy"
+    target_id   = module.payments.escalation_policy_payments_escalation_id
+  }
+}
+
+module "payments" {
+  source = "./teams/payments"
+  users = {
+    jsmith = data.firehydrant_user.jsmith.id
+  }
+  on_call_schedule_platform_primary_id = module.platform.on_call_schedule_platform_primary_id
+  team_platform_id                     = module.platform.team_platform_id
+}
+
+module "platform" {
+  source = "./teams/platform"
+  users = {
+    mika = data.firehydrant_user.mika.id
+  }
+}
+
+### teams/payments/main.tf
+terraform {
+  required_providers {
+    firehydrant = {
+      source  = "firehydrant/firehydrant"
+      version = ">= 0.15.2"
+    }
+  }
+}
+
+resource "firehydrant_team" "payments" {
+  name = "Payments"
+
+  memberships {
+    user_id = var.users["jsmith"]
+  }
+}
+
+resource "firehydrant_escalation_policy" "payments_escalation" {
+  name    = "Payments_escalation"
+  team_id = firehydrant_team.payments.id
+
+  step {
+    timeout = "PT10M"
+
+    targets {
+      type = "OnCallSchedule"
+      id   = var.on_call_schedule_platform_primary_id
+    }
+
+    targets {
+      type = "User"
+      id   = var.users["jsmith"]
+    }
+  }
+
+  repetitions = 0
+  default     = "true"
+
+  handoff_step {
+    target_type = "Team"
+    target_id   = var.team_platform_id
+  }
+}
+
+### teams/payments/outputs.tf
+output "escalation_policy_payments_escalation_id" {
+  value = firehydrant_escalation_policy.payments_escalation.id
+}
+
+### teams/payments/variables.tf
+variable "users" {
+  description = "IDs of the FireHydrant users referred to by this team, by their slug."
+  type        = map(string)
+}
+
+variable "on_call_schedule_platform_primary_id" {
+  type = string
+}
+
+variable "team_platform_id" {
+  type = string
+}
+
+### teams/platform/main.tf
+terraform {
+  required_providers {
+    firehydrant = {
+      source  = "firehydrant/firehydrant"
+      version = ">= 0.15.2"
+    }
+  }
+}
+
+resource "firehydrant_team" "platform" {
+  name = "Platform"
+
+  memberships {
+    user_id = var.users["mika"]
+  }
+}
+
+resource "firehydrant_on_call_schedule" "platform_primary" {
+  name          = "Primary"
+  team_id       = firehydrant_team.platform.id
+  rotation_name = "Day"
+  time_zone     = "America/Los_Angeles"
+
+  member_ids = [var.users["mika"]]
+
+  strategy {
+    type         = "weekly"
+    handoff_day  = "monday"
+    handoff_time = "08:00:00"
+  }
+}
+
+resource "firehydrant_rotation" "platform_primary_night" {
+  name        = "Night"
+  team_id     = firehydrant_team.platform.id
+  schedule_id = firehydrant_on_call_schedule.platform_primary.id
+  time_zone   = "America/Los_Angeles"
+
+  members {
+    user_id = var.users["mika"]
+  }
+
+  strategy {
+    type         = "weekly"
+    handoff_day  = "monday"
+    handoff_time = "20:00:00"
+  }
+}
+
+resource "firehydrant_escalation_policy" "platform_escalation" {
+  name    = "Platform_escalation"
+  team_id = firehydrant_team.platform.id
+
+  step {
+    timeout = "PT15M"
+
+    targets {
+      type = "User"
+      id   = var.users["mika"]
+    }
+  }
+
+  repetitions = 0
+  default     = "true"
+}
+
+### teams/platform/outputs.tf
+output "on_call_schedule_platform_primary_id" {
+  value = firehydrant_on_call_schedule.platform_primary.id
+}
+
+output "team_platform_id" {
+  value = firehydrant_team.platform.id
+}
+
+### teams/platform/variables.tf
+variable "users" {
+  description = "IDs of the FireHydrant users referred to by this team, by their slug."
+  type        = map(string)
+}
+
+### users.tf
+data "firehydrant_user" "jsmith" {
+  email = "jsmith@example.com"
+}
+
+data "firehydrant_user" "mika" {
+  email = "mika@example.com"
+}
+
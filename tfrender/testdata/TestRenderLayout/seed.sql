@@ -0,0 +1,46 @@
+BEGIN TRANSACTION;
+
+INSERT INTO fh_users VALUES('49ef2cda-ab4f-4599-852c-8cc2c8884523','John Smith','jsmith@example.com');
+INSERT INTO fh_users VALUES('66506894-ecbc-4034-b8e6-30851dabf5f3','Mika','mika@example.com');
+
+INSERT INTO ext_users VALUES('e0a51be7-3c7e-407f-8678-292ab421f55f','John Smith','jsmith@example.com','49ef2cda-ab4f-4599-852c-8cc2c8884523','');
+INSERT INTO ext_users VALUES('9253cf00-6195-4123-a9a6-f9f1e25718d8','Mika','mika@example.com','66506894-ecbc-4034-b8e6-30851dabf5f3','');
+
+INSERT INTO fh_teams VALUES('f1d2c3b4-a5e6-4f70-8192-a3b4c5d6e7f8','Platform','platform');
+
+INSERT INTO ext_teams VALUES('946bf740-0497-4d5d-b31f-23a6e55a2719','Payments','payments',NULL,0,1,'');
+INSERT INTO ext_teams VALUES('c2d8e5b6-3f0a-4d4c-9a55-0d2b1f6e7a10','Platform','platform','f1d2c3b4-a5e6-4f70-8192-a3b4c5d6e7f8',0,1,'');
+
+INSERT INTO ext_memberships VALUES('e0a51be7-3c7e-407f-8678-292ab421f55f','946bf740-0497-4d5d-b31f-23a6e55a2719');
+INSERT INTO ext_memberships VALUES('9253cf00-6195-4123-a9a6-f9f1e25718d8','c2d8e5b6-3f0a-4d4c-9a55-0d2b1f6e7a10');
+
+INSERT INTO ext_schedules_v2 VALUES('schedule-platform','Primary','','America/Los_Angeles','c2d8e5b6-3f0a-4d4c-9a55-0d2b1f6e7a10','opsgenie','8ab5a183-8ef5-47db-9de0-56663cfbae7c');
+
+INSERT INTO ext_rotations VALUES('rotation-platform-day','schedule-platform','Day','','weekly','','','08:00:00','monday',0);
+INSERT INTO ext_rotations VALUES('rotation-platform-night','schedule-platform','Night','','weekly','','','20:00:00','monday',1);
+
+INSERT INTO ext_rotation_members VALUES('rotation-platform-day','9253cf00-6195-4123-a9a6-f9f1e25718d8',0);
+INSERT INTO ext_rotation_members VALUES('rotation-platform-night','9253cf00-6195-4123-a9a6-f9f1e25718d8',0);
+
+-- Payments escalates to the schedule of Platform and hands off to Platform, across teams.
+INSERT INTO ext_escalation_policies VALUES('880ec24e-58db-441b-9681-2cb527bd24b2','Payments_escalation','','946bf740-0497-4d5d-b31f-23a6e55a2719',0,NULL,'Team','c2d8e5b6-3f0a-4d4c-9a55-0d2b1f6e7a10','',1);
+-- Platform already has this policy in FireHydrant.
+INSERT INTO ext_escalation_policies VALUES('5b6c7d8e-9f0a-4b1c-8d2e-3f4a5b6c7d8e','Platform_escalation','','c2d8e5b6-3f0a-4d4c-9a55-0d2b1f6e7a10',0,NULL,'','','',1);
+-- A policy without a team stays in the root module.
+INSERT INTO ext_escalation_policies VALUES('1f3c9d7e-2b4a-4c6d-8e0f-9a1b2c3d4e5f','Company_escalation','',NULL,0,NULL,'EscalationPolicy','880ec24e-58db-441b-9681-2cb527bd24b2','',1);
+
+INSERT INTO ext_escalation_policy_steps VALUES('880ec24e-58db-441b-9681-2cb527bd24b2-0','880ec24e-58db-441b-9681-2cb527bd24b2',0,'PT10M');
+INSERT INTO ext_escalation_policy_steps VALUES('5b6c7d8e-9f0a-4b1c-8d2e-3f4a5b6c7d8e-0','5b6c7d8e-9f0a-4b1c-8d2e-3f4a5b6c7d8e',0,'PT15M');
+INSERT INTO ext_escalation_policy_steps VALUES('1f3c9d7e-2b4a-4c6d-8e0f-9a1b2c3d4e5f-0','1f3c9d7e-2b4a-4c6d-8e0f-9a1b2c3d4e5f',0,'PT5M');
+
+INSERT INTO ext_escalation_policy_step_targets VALUES('880ec24e-58db-441b-9681-2cb527bd24b2-0','User','e0a51be7-3c7e-407f-8678-292ab421f55f');
+INSERT INTO ext_escalation_policy_step_targets VALUES('880ec24e-58db-441b-9681-2cb527bd24b2-0','OnCallSchedule','schedule-platform');
+INSERT INTO ext_escalation_policy_step_targets VALUES('5b6c7d8e-9f0a-4b1c-8d2e-3f4a5b6c7d8e-0','User','9253cf00-6195-4123-a9a6-f9f1e25718d8');
+INSERT INTO ext_escalation_policy_step_targets VALUES('1f3c9d7e-2b4a-4c6d-8e0f-9a1b2c3d4e5f-0','OnCallSchedule','schedule-platform');
+
+-- The schedule, its second rotation and the policy of Platform were matched to existing resources.
+INSERT INTO fh_existing_resources VALUES('on_call_schedule','schedule-platform','5c8f2a3e-1d4b-4f6a-9e7c-2b3d4e5f6a7b','f1d2c3b4-a5e6-4f70-8192-a3b4c5d6e7f8:5c8f2a3e-1d4b-4f6a-9e7c-2b3d4e5f6a7b');
+INSERT INTO fh_existing_resources VALUES('rotation','rotation-platform-night','9b2c3d4e-5f6a-4b7c-9d8e-0f1a2b3c4d5e','f1d2c3b4-a5e6-4f70-8192-a3b4c5d6e7f8:5c8f2a3e-1d4b-4f6a-9e7c-2b3d4e5f6a7b:9b2c3d4e-5f6a-4b7c-9d8e-0f1a2b3c4d5e');
+INSERT INTO fh_existing_resources VALUES('escalation_policy','5b6c7d8e-9f0a-4b1c-8d2e-3f4a5b6c7d8e','d4e5f6a7-b8c9-4d0e-8f1a-2b3c4d5e6f7a','f1d2c3b4-a5e6-4f70-8192-a3b4c5d6e7f8:d4e5f6a7-b8c9-4d0e-8f1a-2b3c4d5e6f7a');
+
+COMMIT;
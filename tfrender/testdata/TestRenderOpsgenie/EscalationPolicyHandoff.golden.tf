@@ -0,0 +1,97 @@
+terraform {
+  required_providers {
+    firehydrant = {
+      source  = "firehydrant/firehydrant"
+      version = ">= 0.15.2"
+    }
+  }
+}
+
+data "firehydrant_user" "jsmith" {
+  email = "jsmith@example.com"
+  # [Opsgenie] e0a51be7-3c7e-407f-8678-292ab421f55f jsmith@example.com
+}
+
+data "firehydrant_user" "fh_eng" {
+  email = "fh-eng@example.com"
+  # [Opsgenie] 9253cf00-6195-4123-a9a6-f9f1e25718d8 fh-eng@example.com
+}
+
+resource "firehydrant_team" "payments" {
+  name = "Payments"
+
+  memberships {
+    user_id = data.firehydrant_user.jsmith.id
+  }
+}
+
+resource "firehydrant_team" "platform" {
+  name = "Platform"
+
+  memberships {
+    user_id = data.firehydrant_user.fh_eng.id
+  }
+}
+
+resource "firehydrant_escalation_policy" "payments_escalation" {
+  name    = "Payments_escalation"
+  team_id = firehydrant_team.payments.id
+
+  step {
+    timeout = "PT10M"
+
+    targets {
+      type = "User"
+      id   = data.firehydrant_user.jsmith.id
+    }
+  }
+
+  repetitions = 2
+  default     = "true"
+
+  handoff_step {
+    target_type = "Team"
+    target_id   = firehydrant_team.platform.id
+  }
+}
+
+resource "firehydrant_escalation_policy" "platform_escalation" {
+  name    = "Platform_escalation"
+  team_id = firehydrant_team.platform.id
+
+  step {
+    timeout = "PT5M"
+
+    targets {
+      type = "User"
+      id   = data.firehydrant_user.fh_eng.id
+    }
+  }
+
+  repetitions = 0
+  default     = "true"
+
+  # Handoff to Team '5e7f1a2b-8c9d-4e0f-a1b2-c3d4e5f60718' was skipped as it is not imported.
+}
+
+resource "firehydrant_escalation_policy" "platform_after_hours" {
+  name    = "Platform_after_hours"
+  team_id = firehydrant_team.platform.id
+
+  step {
+    timeout = "PT5M"
+
+    targets {
+      type = "User"
+      id   = data.firehydrant_user.fh_eng.id
+    }
+  }
+
+  repetitions = 0
+  default     = "false"
+
+  handoff_step {
+    target_type = "EscalationPolicy"
+    target_id   = firehydrant_escalation_policy.platform_escalation.id
+  }
+}
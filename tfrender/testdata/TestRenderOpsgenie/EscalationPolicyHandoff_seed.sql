@@ -0,0 +1,31 @@
+BEGIN TRANSACTION;
+
+INSERT INTO fh_users VALUES('49ef2cda-ab4f-4599-852c-8cc2c8884523','John Smith','jsmith@example.com');
+INSERT INTO fh_users VALUES('66506894-ecbc-4034-b8e6-30851dabf5f3','FireHydrant Eng','fh-eng@example.com');
+
+INSERT INTO ext_users VALUES('e0a51be7-3c7e-407f-8678-292ab421f55f','John Smith','jsmith@example.com','49ef2cda-ab4f-4599-852c-8cc2c8884523', '[Opsgenie] e0a51be7-3c7e-407f-8678-292ab421f55f jsmith@example.com');
+INSERT INTO ext_users VALUES('9253cf00-6195-4123-a9a6-f9f1e25718d8','FireHydrant Eng','fh-eng@example.com','66506894-ecbc-4034-b8e6-30851dabf5f3', '[Opsgenie] 9253cf00-6195-4123-a9a6-f9f1e25718d8 fh-eng@example.com');
+
+INSERT INTO ext_teams VALUES('946bf740-0497-4d5d-b31f-23a6e55a2719','Payments','payments',NULL,0,1,'');
+INSERT INTO ext_teams VALUES('c2d8e5b6-3f0a-4d4c-9a55-0d2b1f6e7a10','Platform','platform',NULL,0,1,'');
+INSERT INTO ext_teams VALUES('5e7f1a2b-8c9d-4e0f-a1b2-c3d4e5f60718','Legacy','legacy',NULL,0,0,'');
+
+INSERT INTO ext_memberships VALUES('e0a51be7-3c7e-407f-8678-292ab421f55f','946bf740-0497-4d5d-b31f-23a6e55a2719');
+INSERT INTO ext_memberships VALUES('9253cf00-6195-4123-a9a6-f9f1e25718d8','c2d8e5b6-3f0a-4d4c-9a55-0d2b1f6e7a10');
+
+INSERT INTO ext_escalation_policies VALUES('880ec24e-58db-441b-9681-2cb527bd24b2','Payments_escalation','','946bf740-0497-4d5d-b31f-23a6e55a2719',2,'PT10M','Team','c2d8e5b6-3f0a-4d4c-9a55-0d2b1f6e7a10','',1);
+INSERT INTO ext_escalation_policies VALUES('1f3c9d7e-2b4a-4c6d-8e0f-9a1b2c3d4e5f','Platform_escalation','','c2d8e5b6-3f0a-4d4c-9a55-0d2b1f6e7a10',0,NULL,'Team','5e7f1a2b-8c9d-4e0f-a1b2-c3d4e5f60718','',1);
+INSERT INTO ext_escalation_policies VALUES('7d6c5b4a-3e2f-4a1b-9c8d-7e6f5a4b3c2d','Platform_after_hours','','c2d8e5b6-3f0a-4d4c-9a55-0d2b1f6e7a10',0,NULL,'EscalationPolicy','1f3c9d7e-2b4a-4c6d-8e0f-9a1b2c3d4e5f','',1);
+
+-- Platform has two policies, so its default was chosen during the import.
+INSERT INTO ext_default_escalation_policies VALUES('c2d8e5b6-3f0a-4d4c-9a55-0d2b1f6e7a10','1f3c9d7e-2b4a-4c6d-8e0f-9a1b2c3d4e5f');
+
+INSERT INTO ext_escalation_policy_steps VALUES('880ec24e-58db-441b-9681-2cb527bd24b2-0','880ec24e-58db-441b-9681-2cb527bd24b2',0,'PT10M');
+INSERT INTO ext_escalation_policy_steps VALUES('1f3c9d7e-2b4a-4c6d-8e0f-9a1b2c3d4e5f-0','1f3c9d7e-2b4a-4c6d-8e0f-9a1b2c3d4e5f',0,'PT5M');
+INSERT INTO ext_escalation_policy_steps VALUES('7d6c5b4a-3e2f-4a1b-9c8d-7e6f5a4b3c2d-0','7d6c5b4a-3e2f-4a1b-9c8d-7e6f5a4b3c2d',0,'PT5M');
+
+INSERT INTO ext_escalation_policy_step_targets VALUES('880ec24e-58db-441b-9681-2cb527bd24b2-0','User','e0a51be7-3c7e-407f-8678-292ab421f55f');
+INSERT INTO ext_escalation_policy_step_targets VALUES('1f3c9d7e-2b4a-4c6d-8e0f-9a1b2c3d4e5f-0','User','9253cf00-6195-4123-a9a6-f9f1e25718d8');
+INSERT INTO ext_escalation_policy_step_targets VALUES('7d6c5b4a-3e2f-4a1b-9c8d-7e6f5a4b3c2d-0','User','9253cf00-6195-4123-a9a6-f9f1e25718d8');
+
+COMMIT;
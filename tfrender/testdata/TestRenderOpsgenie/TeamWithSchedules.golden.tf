@@ -158,7 +158,8 @@ resource "firehydrant_on_call_schedule" "aj_team_aj_team_schedule" {
   }
 
   # Overrides found for this schedule:
-  # User: admin@example.net		Starting: Tues, 11 Oct 3025 18:30:00 +0000		Ending: Wed, 12 Oct 3025 18:30:00 +0000
+  # User: admin@example.net (not imported)		Starting: Tues, 11 Oct 3025 18:30:00 +0000		Ending: Wed, 12 Oct 3025 18:30:00 +0000
+  # Overrides can't be managed with Terraform. Run the import with '--output-mode apply' to create them, or add them manually.
   # You can see documention for adding overrides here: https://docs.firehydrant.com/docs/signals-on-call-schedules#overrides
 }
 
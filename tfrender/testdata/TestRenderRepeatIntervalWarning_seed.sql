@@ -0,0 +1,24 @@
+BEGIN TRANSACTION;
+
+INSERT INTO fh_users VALUES('49ef2cda-ab4f-4599-852c-8cc2c8884523','John Smith','jsmith@example.com');
+
+INSERT INTO ext_users VALUES('e0a51be7-3c7e-407f-8678-292ab421f55f','John Smith','jsmith@example.com','49ef2cda-ab4f-4599-852c-8cc2c8884523', '[Opsgenie] e0a51be7-3c7e-407f-8678-292ab421f55f jsmith@example.com');
+
+INSERT INTO ext_teams VALUES('946bf740-0497-4d5d-b31f-23a6e55a2719','Payments','payments',NULL,0,1,'');
+
+INSERT INTO ext_memberships VALUES('e0a51be7-3c7e-407f-8678-292ab421f55f','946bf740-0497-4d5d-b31f-23a6e55a2719');
+
+-- Opsgenie caps the timeout of the last step to 60 minutes, so only the policy waiting 90 minutes
+-- before it repeats loses its repeat interval.
+INSERT INTO ext_escalation_policies VALUES('880ec24e-58db-441b-9681-2cb527bd24b2','Payments_escalation','','946bf740-0497-4d5d-b31f-23a6e55a2719',2,'PT10M','','','',1);
+INSERT INTO ext_escalation_policies VALUES('1f3c9d7e-2b4a-4c6d-8e0f-9a1b2c3d4e5f','Payments_slow','','946bf740-0497-4d5d-b31f-23a6e55a2719',2,'PT90M','','','',1);
+
+INSERT INTO ext_default_escalation_policies VALUES('946bf740-0497-4d5d-b31f-23a6e55a2719','880ec24e-58db-441b-9681-2cb527bd24b2');
+
+INSERT INTO ext_escalation_policy_steps VALUES('880ec24e-58db-441b-9681-2cb527bd24b2-0','880ec24e-58db-441b-9681-2cb527bd24b2',0,'PT10M');
+INSERT INTO ext_escalation_policy_steps VALUES('1f3c9d7e-2b4a-4c6d-8e0f-9a1b2c3d4e5f-0','1f3c9d7e-2b4a-4c6d-8e0f-9a1b2c3d4e5f',0,'PT60M');
+
+INSERT INTO ext_escalation_policy_step_targets VALUES('880ec24e-58db-441b-9681-2cb527bd24b2-0','User','e0a51be7-3c7e-407f-8678-292ab421f55f');
+INSERT INTO ext_escalation_policy_step_targets VALUES('1f3c9d7e-2b4a-4c6d-8e0f-9a1b2c3d4e5f-0','User','e0a51be7-3c7e-407f-8678-292ab421f55f');
+
+COMMIT;
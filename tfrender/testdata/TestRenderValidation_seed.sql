@@ -0,0 +1,29 @@
+BEGIN TRANSACTION;
+
+INSERT INTO fh_users VALUES('u-1','Alex','alex@corp.com');
+INSERT INTO ext_users VALUES('ext-u-1','Alex','alex@corp.com','u-1','');
+
+INSERT INTO ext_teams VALUES('team-payments','Payments','payments',NULL,0,1,'');
+-- Not imported, so schedules of the team refer to an undeclared firehydrant_team.
+INSERT INTO ext_teams VALUES('team-platform','Platform','platform',NULL,0,0,'');
+
+INSERT INTO ext_memberships VALUES('ext-u-1','team-payments');
+
+INSERT INTO ext_schedules_v2 VALUES('schedule-1','Primary','','UTC','team-payments','pagerduty','schedule-1');
+INSERT INTO ext_schedules_v2 VALUES('schedule-2','Secondary','','UTC','team-payments','pagerduty','schedule-2');
+INSERT INTO ext_schedules_v2 VALUES('schedule-4','Tertiary','','UTC','team-payments','pagerduty','schedule-4');
+INSERT INTO ext_schedules_v2 VALUES('schedule-3','Primary','','UTC','team-platform','pagerduty','schedule-3');
+
+-- Starts more than 30 days before the pinned clock.
+INSERT INTO ext_rotations VALUES('rotation-1','schedule-1','Layer 1','','custom','PT12H','2024-01-01T00:00:00Z','','',0);
+-- Has no members.
+INSERT INTO ext_rotations VALUES('rotation-2','schedule-2','Layer 1','','weekly','','','08:00:00','monday',0);
+-- Has a custom strategy without a start time.
+INSERT INTO ext_rotations VALUES('rotation-4','schedule-4','Layer 1','','custom','PT12H','','','',0);
+INSERT INTO ext_rotations VALUES('rotation-3','schedule-3','Layer 1','','weekly','','','08:00:00','monday',0);
+
+INSERT INTO ext_rotation_members VALUES('rotation-1','ext-u-1',0);
+INSERT INTO ext_rotation_members VALUES('rotation-3','ext-u-1',0);
+INSERT INTO ext_rotation_members VALUES('rotation-4','ext-u-1',0);
+
+COMMIT;
@@ -10,10 +10,11 @@ import (
 	"runtime/debug"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/firehydrant/signals-migrator/console"
+	"github.com/firehydrant/signals-migrator/diagnostics"
 	"github.com/firehydrant/signals-migrator/store"
-	"github.com/gosimple/slug"
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hclsyntax"
 	"github.com/hashicorp/hcl/v2/hclwrite"
@@ -30,6 +31,20 @@ type TFRender struct {
 	dir string
 	// Output file name.
 	filename string
+
+	layout Layout
+	format Format
+	// files are the other files of the configuration, by their path relative to dir.
+	files map[string]*hclwrite.File
+	// modules are the team modules of the modules layout, by team slug.
+	modules map[string]*tfModule
+	// addresses are the labels of the blocks of the configuration.
+	addresses *addresses
+
+	// validation configures the checks of the configuration before it is written, which are
+	// skipped when it is nil.
+	validation *Validation
+	now        func() time.Time
 }
 
 func fhProviderVersion() string {
@@ -57,13 +72,6 @@ func fhProviderVersion() string {
 	return ">= 0.15.2"
 }
 
-// tfScheduleSlug produces a Terraform-safe identifier from a schedule or
-// rotation name. slug.Make handles non-ASCII characters (emojis, accented
-// letters) that the Terraform HCL parser otherwise rejects in resource labels.
-func tfScheduleSlug(name string) string {
-	return strings.ReplaceAll(slug.Make(name), "-", "_")
-}
-
 func New(name string) (*TFRender, error) {
 	baseDir := filepath.Dir(name)
 	if err := os.MkdirAll(baseDir, 0755); err != nil {
@@ -73,35 +81,52 @@ func New(name string) (*TFRender, error) {
 
 	f := hclwrite.NewEmptyFile()
 	root := f.Body()
-	provider := root.AppendNewBlock("terraform", nil).Body().AppendNewBlock("required_providers", nil).Body()
+	provider := appendRequiredProviders(root)
+
+	return &TFRender{
+		f:         f,
+		provider:  provider,
+		root:      root,
+		dir:       baseDir,
+		filename:  baseName,
+		layout:    LayoutSingle,
+		format:    FormatHCL,
+		files:     map[string]*hclwrite.File{},
+		modules:   map[string]*tfModule{},
+		addresses: newAddresses(),
+		now:       time.Now,
+	}, nil
+}
+
+func appendRequiredProviders(b *hclwrite.Body) *hclwrite.Body {
+	provider := b.AppendNewBlock("terraform", nil).Body().AppendNewBlock("required_providers", nil).Body()
 	provider.SetAttributeValue("firehydrant", cty.ObjectVal(map[string]cty.Value{
 		"source":  cty.StringVal("firehydrant/firehydrant"),
 		"version": cty.StringVal(fhProviderVersion()),
 	}))
-
-	return &TFRender{
-		f:        f,
-		provider: provider,
-		root:     root,
-		dir:      baseDir,
-		filename: baseName,
-	}, nil
+	return provider
 }
 
 func (r *TFRender) Filepath() string {
-	return filepath.Join(r.dir, r.filename)
+	return filepath.Join(r.dir, r.Filename())
 }
 
 func (r *TFRender) Filename() string {
-	return r.filename
+	return r.outputName(r.filename)
+}
+
+// outputName is the name a file of the configuration is written to, given the format.
+func (r *TFRender) outputName(name string) string {
+	if r.format == FormatJSON {
+		return name + ".json"
+	}
+	return name
 }
 
 func (r *TFRender) Write(ctx context.Context) error {
-	f, err := os.Create(r.Filepath())
-	if err != nil {
-		return fmt.Errorf("creating file: %w", err)
+	if err := r.assignAddresses(ctx); err != nil {
+		return err
 	}
-	defer f.Close()
 
 	toWrite := []func(context.Context) error{
 		r.DataFireHydrantUsers,
@@ -117,10 +142,26 @@ func (r *TFRender) Write(ctx context.Context) error {
 		}
 	}
 
-	if _, err := f.Write(r.f.Bytes()); err != nil {
-		return fmt.Errorf("writing file: %w", err)
+	if r.layout == LayoutModules {
+		r.renderModules()
+	}
+
+	contents := r.contents()
+	if r.validation != nil {
+		if err := r.validate(ctx, contents); err != nil {
+			return err
+		}
+	}
+
+	paths, err := r.writeFiles(contents)
+	if err != nil {
+		return err
+	}
+	if r.layout == LayoutSingle {
+		console.Successf("Terraform file has been written to %s\n", r.Filepath())
+	} else {
+		console.Successf("Terraform configuration has been written to %d files in %s\n", len(paths), r.dir)
 	}
-	console.Successf("Terraform file has been written to %s\n", r.Filepath())
 
 	return nil
 }
@@ -130,33 +171,30 @@ func (r *TFRender) ResourceFireHydrantEscalationPolicy(ctx context.Context) erro
 	if err != nil {
 		return fmt.Errorf("querying escalation policies: %w", err)
 	}
-	var defaultPolicy bool
-	if len(policies) == 1 {
-		defaultPolicy = true
+	defaults, err := store.DefaultEscalationPolicyIDs(ctx)
+	if err != nil {
+		return err
 	}
 
 	for _, p := range policies {
-		r.root.AppendNewline()
+		teamSlug, err := r.policyTeamLabel(ctx, p)
+		if err != nil {
+			return err
+		}
+		root := r.body(teamSlug)
+		root.AppendNewline()
 
-		b := r.root.AppendNewBlock("resource", []string{
+		b := root.AppendNewBlock("resource", []string{
 			"firehydrant_escalation_policy",
-			p.TFSlug(),
+			r.policyLabel(p),
 		}).Body()
 		b.SetAttributeValue("name", cty.StringVal(p.Name))
 		if p.Description != "" {
 			b.SetAttributeValue("description", cty.StringVal(p.Description))
 		}
 
-		if p.TeamID.Valid && p.TeamID.String != "" {
-			t, err := store.UseQueries(ctx).GetTeamByExtID(ctx, p.TeamID.String)
-			if err != nil {
-				return fmt.Errorf("querying team '%s' for policy '%s': %w", p.TeamID.String, p.Name, err)
-			}
-			b.SetAttributeTraversal("team_id", hcl.Traversal{
-				hcl.TraverseRoot{Name: "firehydrant_team"},
-				hcl.TraverseAttr{Name: t.TFSlug()},
-				hcl.TraverseAttr{Name: "id"},
-			})
+		if teamSlug != "" {
+			b.SetAttributeTraversal("team_id", r.ref(teamSlug, teamSlug, "firehydrant_team", teamSlug))
 		}
 
 		if p.Annotations != "" {
@@ -179,6 +217,15 @@ func (r *TFRender) ResourceFireHydrantEscalationPolicy(ctx context.Context) erro
 				return fmt.Errorf("querying targets for step %d of %s: %w", s.Position, p.Name, err)
 			}
 
+			skip := func(t store.ExtEscalationPolicyStepTarget, reason string) store.InsertExtEscalationPolicyStepTargetSkipParams {
+				return store.InsertExtEscalationPolicyStepTargetSkipParams{
+					EscalationPolicyID: p.ID,
+					StepPosition:       s.Position,
+					TargetType:         t.TargetType,
+					TargetID:           t.TargetID,
+					Reason:             reason,
+				}
+			}
 			for _, t := range targets {
 				idTraversals := []hcl.Traversal{}
 
@@ -186,46 +233,35 @@ func (r *TFRender) ResourceFireHydrantEscalationPolicy(ctx context.Context) erro
 				case store.TARGET_TYPE_USER:
 					u, err := store.UseQueries(ctx).GetUserByExtID(ctx, t.TargetID)
 					if err != nil {
-						console.Errorf("querying user '%s' for step %d of %s: %s\n", t.TargetID, s.Position, p.Name, err.Error())
+						diagnostics.SkipStepTarget(ctx, skip(t, store.TARGET_SKIP_MISSING_FH_USER),
+							"Skipping user '%s' in step %d of %s: %s\n", t.TargetID, s.Position, p.Name, err.Error())
 						continue
 					}
-					idTraversals = append(idTraversals, hcl.Traversal{ //nolint:staticcheck // See "safeguard" below
-						hcl.TraverseRoot{Name: "data"},
-						hcl.TraverseAttr{Name: "firehydrant_user"},
-						hcl.TraverseAttr{Name: u.TFSlug()},
-						hcl.TraverseAttr{Name: "id"},
-					})
+					idTraversals = append(idTraversals, r.userRef(teamSlug, r.linkedUserLabel(u))) //nolint:staticcheck // See "safeguard" below
 				case store.TARGET_TYPE_SCHEDULE:
 					// Get the schedule from the new structure
 					schedule, err := store.UseQueries(ctx).GetExtScheduleV2(ctx, t.TargetID)
 					if err != nil {
-						console.Errorf("querying schedule '%s' for step %d of %s: %s\n", t.TargetID, s.Position, p.Name, err.Error())
+						diagnostics.SkipStepTarget(ctx, skip(t, store.TARGET_SKIP_NOT_IMPORTED),
+							"Skipping schedule '%s' in step %d of %s: %s\n", t.TargetID, s.Position, p.Name, err.Error())
 						continue
 					}
 
 					// Get the team that owns this schedule
-					team, err := store.UseQueries(ctx).GetExtTeam(ctx, schedule.TeamID)
-					if err != nil {
-						console.Errorf("querying team for schedule '%s' in step %d of %s: %s\n", schedule.Name, s.Position, p.Name, err.Error())
+					if _, err := store.UseQueries(ctx).GetExtTeam(ctx, schedule.TeamID); err != nil {
+						diagnostics.SkipStepTarget(ctx, skip(t, store.TARGET_SKIP_NOT_IMPORTED),
+							"Skipping schedule '%s' in step %d of %s, its team is not imported: %s\n", schedule.Name, s.Position, p.Name, err.Error())
 						continue
 					}
-					// the slug we want is the slug of the LinkedTeam, not the slug of the ExtTeam
-					// I blame Wilson and the tragically misnamed var on the second line of ResourceFireHydrantTeams()
-					linkedTeam, err := store.UseQueries(ctx).GetTeamByExtID(ctx, team.ID)
+					scheduleTeamSlug, err := r.scheduleTeamLabel(ctx, schedule)
 					if err != nil {
-						return fmt.Errorf("querying linked_team for team '%s': %w", team.ID, err)
+						return err
 					}
-					teamSlug := linkedTeam.TFSlug()
-
-					scheduleSlug := tfScheduleSlug(schedule.Name)
-					resourceSlug := fmt.Sprintf("%s_%s", teamSlug, scheduleSlug)
-					idTraversals = append(idTraversals, hcl.Traversal{ //nolint:staticcheck // See "safeguard" below
-						hcl.TraverseRoot{Name: "firehydrant_on_call_schedule"},
-						hcl.TraverseAttr{Name: resourceSlug},
-						hcl.TraverseAttr{Name: "id"},
-					})
+					resourceSlug := r.scheduleLabel(schedule, scheduleTeamSlug)
+					idTraversals = append(idTraversals, r.ref(teamSlug, scheduleTeamSlug, "firehydrant_on_call_schedule", resourceSlug)) //nolint:staticcheck // See "safeguard" below
 				default:
-					console.Errorf("unknown target type '%s' for step %d of %s\n", t.TargetType, s.Position, p.Name)
+					diagnostics.SkipStepTarget(ctx, skip(t, store.TARGET_SKIP_UNSUPPORTED_TYPE),
+						"Skipping unknown target type '%s' in step %d of %s.\n", t.TargetType, s.Position, p.Name)
 					continue
 				}
 
@@ -240,10 +276,99 @@ func (r *TFRender) ResourceFireHydrantEscalationPolicy(ctx context.Context) erro
 
 		b.AppendNewline()
 		b.SetAttributeValue("repetitions", cty.NumberIntVal(p.RepeatLimit))
-		b.SetAttributeValue("default", cty.StringVal(strconv.FormatBool(defaultPolicy)))
+		b.SetAttributeValue("default", cty.StringVal(strconv.FormatBool(defaults[p.ID])))
+		if comment, err := diagnostics.RepeatInterval(ctx, p); err != nil {
+			return err
+		} else if comment != "" {
+			r.AppendComment(b, comment)
+		}
 
-		// TODO: add policy handoff
+		if err := r.renderEscalationPolicyHandoff(ctx, p, teamSlug, b); err != nil {
+			return err
+		}
+
+		// If the policy already exists in FireHydrant, declare import to prevent duplication.
+		importID, err := store.ExistingImportID(ctx, store.EXISTING_RESOURCE_ESCALATION_POLICY, p.ID)
+		if err != nil {
+			return err
+		}
+		if importID != "" {
+			r.appendImport(teamSlug, "firehydrant_escalation_policy", r.policyLabel(p), importID)
+		}
+	}
+	return nil
+}
+
+// policyTeamLabel returns the label of the team owning an escalation policy, or an empty string
+// when the policy doesn't belong to a team.
+func (r *TFRender) policyTeamLabel(ctx context.Context, p store.ExtEscalationPolicy) (string, error) {
+	if !p.TeamID.Valid || p.TeamID.String == "" {
+		return "", nil
+	}
+	t, err := store.UseQueries(ctx).GetTeamByExtID(ctx, p.TeamID.String)
+	if err != nil {
+		return "", fmt.Errorf("querying team '%s' for policy '%s': %w", p.TeamID.String, p.Name, err)
+	}
+	return r.teamLabel(t), nil
+}
+
+// scheduleTeamLabel returns the label of the team owning a schedule.
+func (r *TFRender) scheduleTeamLabel(ctx context.Context, s store.ExtSchedulesV2) (string, error) {
+	team, err := store.UseQueries(ctx).GetExtTeam(ctx, s.TeamID)
+	if err != nil {
+		return "", fmt.Errorf("querying team for schedule '%s': %w", s.Name, err)
+	}
+	// the slug we want is the slug of the LinkedTeam, not the slug of the ExtTeam
+	// I blame Wilson and the tragically misnamed var on the second line of ResourceFireHydrantTeams()
+	linkedTeam, err := store.UseQueries(ctx).GetTeamByExtID(ctx, team.ID)
+	if err != nil {
+		return "", fmt.Errorf("querying linked_team for team '%s': %w", team.ID, err)
+	}
+	return r.teamLabel(linkedTeam), nil
+}
+
+// renderEscalationPolicyHandoff renders the step FireHydrant hands off to once every step and
+// repetition of the policy is exhausted. Handoffs to resources which are not imported are left
+// as a comment for the user to resolve.
+func (r *TFRender) renderEscalationPolicyHandoff(ctx context.Context, p store.ExtEscalationPolicy, teamSlug string, b *hclwrite.Body) error {
+	var target hcl.Traversal
+	switch p.HandoffTargetType {
+	case "":
+		return nil
+	case store.TARGET_TYPE_ESCALATION_POLICY:
+		handoff, err := store.UseQueries(ctx).GetExtEscalationPolicy(ctx, p.HandoffTargetID)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("querying handoff policy '%s' for policy '%s': %w", p.HandoffTargetID, p.Name, err)
+		}
+		if err == nil {
+			owner, err := r.policyTeamLabel(ctx, handoff)
+			if err != nil {
+				return err
+			}
+			target = r.ref(teamSlug, owner, "firehydrant_escalation_policy", r.policyLabel(handoff))
+		}
+	case store.TARGET_TYPE_TEAM:
+		t, err := store.UseQueries(ctx).GetTeamByExtID(ctx, p.HandoffTargetID)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("querying handoff team '%s' for policy '%s': %w", p.HandoffTargetID, p.Name, err)
+		}
+		if err == nil && t.ToImport == 1 {
+			target = r.ref(teamSlug, r.teamLabel(t), "firehydrant_team", r.teamLabel(t))
+		}
+	default:
+		diagnostics.Errorf(ctx, diagnostics.ResourceEscalationPolicy, p.ID, "unknown handoff target type '%s' for %s\n", p.HandoffTargetType, p.Name)
+		return nil
+	}
+
+	b.AppendNewline()
+	if target == nil {
+		diagnostics.Warnf(ctx, diagnostics.ResourceEscalationPolicy, p.ID, "Handoff of %s to %s '%s' was skipped as it is not imported.\n", p.Name, p.HandoffTargetType, p.HandoffTargetID)
+		r.AppendComment(b, fmt.Sprintf("Handoff to %s '%s' was skipped as it is not imported.", p.HandoffTargetType, p.HandoffTargetID))
+		return nil
 	}
+	handoff := b.AppendNewBlock("handoff_step", nil).Body()
+	handoff.SetAttributeValue("target_type", cty.StringVal(p.HandoffTargetType))
+	handoff.SetAttributeTraversal("target_id", target)
 	return nil
 }
 
@@ -266,25 +391,22 @@ func (r *TFRender) ResourceFireHydrantOnCallSchedule(ctx context.Context) error
 		if err != nil {
 			return fmt.Errorf("querying linked_team for team '%s': %w", team.ID, err)
 		}
-		teamSlug := linkedTeam.TFSlug()
+		teamSlug := r.teamLabel(linkedTeam)
 
-		r.root.AppendNewline()
+		root := r.body(teamSlug)
+		root.AppendNewline()
 
-		scheduleSlug := tfScheduleSlug(s.Name)
+		scheduleSlug := r.scheduleLabel(s, teamSlug)
 
-		b := r.root.AppendNewBlock("resource", []string{
+		b := root.AppendNewBlock("resource", []string{
 			"firehydrant_on_call_schedule",
-			fmt.Sprintf("%s_%s", teamSlug, scheduleSlug),
+			scheduleSlug,
 		}).Body()
 		b.SetAttributeValue("name", cty.StringVal(s.Name))
 		if s.Description != "" {
 			b.SetAttributeValue("description", cty.StringVal(s.Description))
 		}
-		b.SetAttributeTraversal("team_id", hcl.Traversal{
-			hcl.TraverseRoot{Name: "firehydrant_team"},
-			hcl.TraverseAttr{Name: teamSlug},
-			hcl.TraverseAttr{Name: "id"},
-		})
+		b.SetAttributeTraversal("team_id", r.ref(teamSlug, teamSlug, "firehydrant_team", teamSlug))
 		rotations, err := store.UseQueries(ctx).ListExtRotationsByScheduleID(ctx, s.ID)
 		if err != nil {
 			return fmt.Errorf("querying rotations: %w", err)
@@ -303,7 +425,7 @@ func (r *TFRender) ResourceFireHydrantOnCallSchedule(ctx context.Context) error
 			if first.Description != "" && first.Description != s.Description {
 				b.SetAttributeValue("rotation_description", cty.StringVal(first.Description))
 			}
-			err = renderRotationData(ctx, first, r, b, false)
+			err = renderRotationData(ctx, first, r, teamSlug, b, false)
 			if err != nil {
 				return fmt.Errorf("rendering rotation data '%s': %w", first.Name, err)
 			}
@@ -322,10 +444,23 @@ func (r *TFRender) ResourceFireHydrantOnCallSchedule(ctx context.Context) error
 			b.AppendNewline()
 			r.AppendComment(b, "Overrides found for this schedule:")
 			for _, override := range overrides {
-				r.AppendComment(b, fmt.Sprintf("User: %s		Starting: %s		Ending: %s", override.Username, override.StartTime, override.EndTime))
+				user := override.Username
+				if u, err := store.UseQueries(ctx).GetUserByEmail(ctx, override.Username); err != nil || !u.FhUserID.Valid {
+					user += " (not imported)"
+				}
+				r.AppendComment(b, fmt.Sprintf("User: %s		Starting: %s		Ending: %s", user, override.StartTime, override.EndTime))
 			}
+			r.AppendComment(b, "Overrides can't be managed with Terraform. Run the import with '--output-mode apply' to create them, or add them manually.")
 			r.AppendComment(b, "You can see documention for adding overrides here: https://docs.firehydrant.com/docs/signals-on-call-schedules#overrides")
 		}
+
+		importID, err := store.ExistingImportID(ctx, store.EXISTING_RESOURCE_SCHEDULE, s.ID)
+		if err != nil {
+			return err
+		}
+		if importID != "" {
+			r.appendImport(teamSlug, "firehydrant_on_call_schedule", scheduleSlug, importID)
+		}
 	}
 	return nil
 }
@@ -364,33 +499,26 @@ func (r *TFRender) ResourceFireHydrantRotation(ctx context.Context) error {
 		if err != nil {
 			return fmt.Errorf("querying linked_team for team '%s': %w", team.ID, err)
 		}
-		teamSlug := linkedTeam.TFSlug()
+		teamSlug := r.teamLabel(linkedTeam)
 
-		r.root.AppendNewline()
+		root := r.body(teamSlug)
+		root.AppendNewline()
 
-		rotationSlug := tfScheduleSlug(rotation.Name)
-		scheduleSlug := tfScheduleSlug(schedule.Name)
+		scheduleSlug := r.scheduleLabel(schedule, teamSlug)
+		rotationSlug := r.rotationLabel(rotation, scheduleSlug)
 
-		b := r.root.AppendNewBlock("resource", []string{
+		b := root.AppendNewBlock("resource", []string{
 			"firehydrant_rotation",
-			fmt.Sprintf("%s_%s_%s", teamSlug, scheduleSlug, rotationSlug),
+			rotationSlug,
 		}).Body()
 		b.SetAttributeValue("name", cty.StringVal(rotation.Name))
 		if rotation.Description != "" {
 			b.SetAttributeValue("description", cty.StringVal(rotation.Description))
 		}
-		b.SetAttributeTraversal("team_id", hcl.Traversal{
-			hcl.TraverseRoot{Name: "firehydrant_team"},
-			hcl.TraverseAttr{Name: teamSlug},
-			hcl.TraverseAttr{Name: "id"},
-		})
-		b.SetAttributeTraversal("schedule_id", hcl.Traversal{
-			hcl.TraverseRoot{Name: "firehydrant_on_call_schedule"},
-			hcl.TraverseAttr{Name: fmt.Sprintf("%s_%s", teamSlug, scheduleSlug)},
-			hcl.TraverseAttr{Name: "id"},
-		})
-
-		err = renderRotationData(ctx, rotation, r, b, true)
+		b.SetAttributeTraversal("team_id", r.ref(teamSlug, teamSlug, "firehydrant_team", teamSlug))
+		b.SetAttributeTraversal("schedule_id", r.ref(teamSlug, teamSlug, "firehydrant_on_call_schedule", scheduleSlug))
+
+		err = renderRotationData(ctx, rotation, r, teamSlug, b, true)
 		if err != nil {
 			return fmt.Errorf("rendering rotation data '%s': %w", rotation.Name, err)
 		}
@@ -399,11 +527,19 @@ func (r *TFRender) ResourceFireHydrantRotation(ctx context.Context) error {
 			b.AppendNewline()
 			r.AppendComment(b, team.Annotations)
 		}
+
+		importID, err := store.ExistingImportID(ctx, store.EXISTING_RESOURCE_ROTATION, rotation.ID)
+		if err != nil {
+			return err
+		}
+		if importID != "" {
+			r.appendImport(teamSlug, "firehydrant_rotation", rotationSlug, importID)
+		}
 	}
 	return nil
 }
 
-func renderRotationData(ctx context.Context, rotation store.ExtRotation, r *TFRender, body *hclwrite.Body, useMembers bool) error {
+func renderRotationData(ctx context.Context, rotation store.ExtRotation, r *TFRender, teamSlug string, body *hclwrite.Body, useMembers bool) error {
 	// Get the schedule that owns this rotation
 	schedule, err := store.UseQueries(ctx).GetExtScheduleV2(ctx, rotation.ScheduleID)
 	if err != nil {
@@ -430,24 +566,13 @@ func renderRotationData(ctx context.Context, rotation store.ExtRotation, r *TFRe
 		// Rotation resources use nested "members" blocks with user_id attribute.
 		for _, m := range members {
 			body.AppendNewBlock("members", nil).Body().
-				SetAttributeTraversal("user_id", hcl.Traversal{
-					hcl.TraverseRoot{Name: "data"},
-					hcl.TraverseAttr{Name: "firehydrant_user"},
-					hcl.TraverseAttr{Name: m.TFSlug()},
-					hcl.TraverseAttr{Name: "id"},
-				})
+				SetAttributeTraversal("user_id", r.userRef(teamSlug, r.userLabel(m)))
 		}
 	} else {
 		// Schedule resources use a flat "member_ids" list.
 		memberList := []hclwrite.Tokens{}
 		for _, m := range members {
-			member := hcl.Traversal{
-				hcl.TraverseRoot{Name: "data"},
-				hcl.TraverseAttr{Name: "firehydrant_user"},
-				hcl.TraverseAttr{Name: m.TFSlug()},
-				hcl.TraverseAttr{Name: "id"},
-			}
-			memberList = append(memberList, hclwrite.TokensForTraversal(member))
+			memberList = append(memberList, hclwrite.TokensForTraversal(r.userRef(teamSlug, r.userLabel(m))))
 		}
 		body.SetAttributeRaw("member_ids", hclwrite.TokensForTuple(memberList))
 	}
@@ -496,11 +621,12 @@ func (r *TFRender) ResourceFireHydrantTeams(ctx context.Context) error {
 	fhTeamBlocks := map[string]*hclwrite.Body{}
 	for _, t := range extTeams {
 		name := t.ValidName()
-		tfSlug := t.TFSlug()
+		tfSlug := r.teamLabel(t)
 
 		if _, ok := fhTeamBlocks[name]; !ok {
-			r.root.AppendNewline()
-			fhTeamBlocks[name] = r.root.AppendNewBlock("resource", []string{"firehydrant_team", tfSlug}).Body()
+			root := r.body(tfSlug)
+			root.AppendNewline()
+			fhTeamBlocks[name] = root.AppendNewBlock("resource", []string{"firehydrant_team", tfSlug}).Body()
 			fhTeamBlocks[name].SetAttributeValue("name", cty.StringVal(name))
 		}
 
@@ -541,31 +667,20 @@ func (r *TFRender) ResourceFireHydrantTeams(ctx context.Context) error {
 				return fmt.Errorf("querying team members: %w", err)
 			}
 			for _, m := range members {
-				if importedMembership[tfSlug+m.TFSlug()] {
+				if importedMembership[tfSlug+m.ID] {
 					continue
 				}
 
 				b.AppendNewline()
 				b.AppendNewBlock("memberships", []string{}).Body().
-					SetAttributeTraversal("user_id", hcl.Traversal{
-						hcl.TraverseRoot{Name: "data"},
-						hcl.TraverseAttr{Name: "firehydrant_user"},
-						hcl.TraverseAttr{Name: m.TFSlug()},
-						hcl.TraverseAttr{Name: "id"},
-					})
-				importedMembership[tfSlug+m.TFSlug()] = true
+					SetAttributeTraversal("user_id", r.userRef(tfSlug, r.userLabel(m)))
+				importedMembership[tfSlug+m.ID] = true
 			}
 		}
 
 		// If there is an existing FireHydrant team already, declare import to prevent duplication.
 		if t.FhTeamID.Valid && t.FhTeamID.String != "" && !importedTeams[t.FhTeamID.String] {
-			r.root.AppendNewline()
-			importBody := r.root.AppendNewBlock("import", []string{}).Body()
-			importBody.SetAttributeValue("id", cty.StringVal(t.FhTeamID.String))
-			importBody.SetAttributeTraversal("to", hcl.Traversal{
-				hcl.TraverseRoot{Name: "firehydrant_team"},
-				hcl.TraverseAttr{Name: tfSlug},
-			})
+			r.appendImport(tfSlug, "firehydrant_team", tfSlug, t.FhTeamID.String)
 			importedTeams[t.FhTeamID.String] = true
 		}
 	}
@@ -577,9 +692,10 @@ func (r *TFRender) DataFireHydrantUsers(ctx context.Context) error {
 	if err != nil {
 		return fmt.Errorf("querying users: %w", err)
 	}
+	root := r.usersBody()
 	for _, u := range users {
-		r.root.AppendNewline()
-		b := r.root.AppendNewBlock("data", []string{"firehydrant_user", u.TFSlug()}).Body()
+		root.AppendNewline()
+		b := root.AppendNewBlock("data", []string{"firehydrant_user", r.userLabel(u)}).Body()
 		b.SetAttributeValue("email", cty.StringVal(u.Email))
 
 		annotations, err := store.UseQueries(ctx).ListFhUserAnnotations(ctx, sql.NullString{String: u.ID, Valid: true})
@@ -1,7 +1,13 @@
 package tfrender_test
 
 import (
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+
+	"github.com/firehydrant/signals-migrator/diagnostics"
+	"github.com/firehydrant/signals-migrator/store"
 )
 
 func TestRenderOpsgenie(t *testing.T) {
@@ -10,4 +16,35 @@ func TestRenderOpsgenie(t *testing.T) {
 
 	// Render Terraform configuration for a base case for escalation policy.
 	t.Run("EscalationPolicy", assertRenderPager)
+
+	// Render Terraform configuration for escalation policies repeating and handing off to teams or
+	// other policies, including a handoff to a team which is not imported.
+	t.Run("EscalationPolicyHandoff", assertRenderPager)
+}
+
+func TestRenderRepeatIntervalWarning(t *testing.T) {
+	seed, err := os.ReadFile(filepath.Join("testdata", "TestRenderRepeatIntervalWarning_seed.sql"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, tfr := tfrInit(t)
+	ctx = diagnostics.WithCollector(ctx)
+	if _, err := store.FromContext(ctx).ExecContext(ctx, strings.TrimSpace(string(seed))); err != nil {
+		t.Fatal(err)
+	}
+	if err := tfr.Write(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// Only the repeat interval which FireHydrant cannot reproduce shows up in the report.
+	warned := []string{}
+	for _, e := range diagnostics.FromContext(ctx).Entries() {
+		if e.Resource == diagnostics.ResourceEscalationPolicy && strings.Contains(e.Message, "which FireHydrant does not support") {
+			warned = append(warned, e.SourceID)
+		}
+	}
+	if len(warned) != 1 || warned[0] != "1f3c9d7e-2b4a-4c6d-8e0f-9a1b2c3d4e5f" {
+		t.Errorf("expected a warning about the repeat interval of Payments_slow only, got %+v", diagnostics.FromContext(ctx).Entries())
+	}
 }
@@ -10,4 +10,7 @@ func TestRenderPagerDuty(t *testing.T) {
 
 	// Render Terraform configuration for a base case for escalation policy.
 	t.Run("EscalationPolicy", assertRenderPager)
+
+	// The only escalation policy is the default, even without a team.
+	t.Run("SingleEscalationPolicy", assertRenderPager)
 }
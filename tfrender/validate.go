@@ -0,0 +1,318 @@
+package tfrender
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/firehydrant/signals-migrator/diagnostics"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// maxStartTimeAge is how far in the past FireHydrant accepts the start time of a rotation with a
+// custom strategy.
+const maxStartTimeAge = 30 * 24 * time.Hour
+
+// ValidationError lists the problems found in the generated configuration, which is not written.
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "generated Terraform configuration is invalid, %d problem(s) found:", len(e.Problems))
+	for _, p := range e.Problems {
+		fmt.Fprintf(&b, "\n  - %s", p)
+	}
+	return b.String()
+}
+
+// Validation configures the checks of the generated configuration before it is written.
+type Validation struct {
+	// AllowEmptySchedules accepts schedules and rotations without members, which are then only
+	// warned about.
+	AllowEmptySchedules bool
+}
+
+// SetValidation checks the generated configuration before it is written, and fails with a
+// ValidationError instead of writing it when a problem is found.
+func (r *TFRender) SetValidation(v Validation) {
+	r.validation = &v
+}
+
+// SetNow overrides the clock used to validate start times, so tests can pin it.
+func (r *TFRender) SetNow(now func() time.Time) {
+	r.now = now
+}
+
+// tfModuleConfig is what a module of the generated configuration declares.
+type tfModuleConfig struct {
+	files map[string]*hclsyntax.Body
+	// addresses of the declared resources, data sources, variables, modules and outputs, e.g.
+	// "firehydrant_team.platform", "data.firehydrant_user.mika", "var.users", "module.platform"
+	// and "output.team_platform_id".
+	declared map[string]bool
+	// modules are the directories of the module calls, by name.
+	modules map[string]string
+}
+
+// validate parses the generated configuration back, and checks that it can be planned and applied:
+// every reference resolves to a declared block, custom strategies start within the window accepted
+// by FireHydrant, every team has exactly one default escalation policy, and schedules and rotations
+// have members.
+//
+// Schedules and rotations without members are accepted by FireHydrant, but nobody gets paged for
+// them. They are common when the only members of a schedule were deactivated in the source, so
+// they may be allowed, in which case they are only warned about.
+func (r *TFRender) validate(ctx context.Context, contents map[string][]byte) error {
+	problems := []string{}
+	modules := map[string]*tfModuleConfig{}
+	for _, name := range sortedKeys(contents) {
+		f, diags := hclsyntax.ParseConfig(contents[name], name, hcl.InitialPos)
+		if diags.HasErrors() {
+			for _, d := range diags.Errs() {
+				problems = append(problems, d.Error())
+			}
+			continue
+		}
+		dir := filepath.ToSlash(filepath.Dir(name))
+		m, ok := modules[dir]
+		if !ok {
+			m = &tfModuleConfig{files: map[string]*hclsyntax.Body{}, declared: map[string]bool{}, modules: map[string]string{}}
+			modules[dir] = m
+		}
+		body := f.Body.(*hclsyntax.Body)
+		m.files[name] = body
+		for _, block := range body.Blocks {
+			switch {
+			case block.Type == "resource" && len(block.Labels) == 2:
+				m.declared[block.Labels[0]+"."+block.Labels[1]] = true
+			case block.Type == "data" && len(block.Labels) == 2:
+				m.declared["data."+block.Labels[0]+"."+block.Labels[1]] = true
+			case block.Type == "variable" && len(block.Labels) == 1:
+				m.declared["var."+block.Labels[0]] = true
+			case block.Type == "output" && len(block.Labels) == 1:
+				m.declared["output."+block.Labels[0]] = true
+			case block.Type == "module" && len(block.Labels) == 1:
+				m.declared["module."+block.Labels[0]] = true
+				if source, ok := block.Body.Attributes["source"]; ok {
+					if v, diags := source.Expr.Value(nil); !diags.HasErrors() && v.Type() == cty.String {
+						m.modules[block.Labels[0]] = path.Clean(path.Join(dir, v.AsString()))
+					}
+				}
+			}
+		}
+	}
+
+	now := r.now()
+	for _, dir := range sortedKeys(modules) {
+		m := modules[dir]
+		// policies and defaults are the escalation policies of each team, by the reference to the team.
+		policies, defaults := map[string][]string{}, map[string][]string{}
+		for _, name := range sortedKeys(m.files) {
+			for _, block := range m.files[name].Blocks {
+				problems = append(problems, m.checkReferences(modules, block)...)
+
+				if block.Type != "resource" || len(block.Labels) != 2 {
+					continue
+				}
+				address := block.Labels[0] + "." + block.Labels[1]
+				at := fmt.Sprintf("%s (%s)", address, block.DefRange().String())
+				switch block.Labels[0] {
+				case "firehydrant_on_call_schedule":
+					if !hasMemberIDs(block.Body) {
+						problems = append(problems, r.checkNoMembers(ctx, diagnostics.ResourceSchedule, address, at)...)
+					}
+					problems = append(problems, checkStartTime(at, block.Body, now)...)
+				case "firehydrant_rotation":
+					if !slices.ContainsFunc(block.Body.Blocks, func(b *hclsyntax.Block) bool { return b.Type == "members" }) {
+						problems = append(problems, r.checkNoMembers(ctx, diagnostics.ResourceRotation, address, at)...)
+					}
+					problems = append(problems, checkStartTime(at, block.Body, now)...)
+				case "firehydrant_escalation_policy":
+					team, ok := block.Body.Attributes["team_id"]
+					if !ok {
+						continue
+					}
+					teamRef := string(team.Expr.Range().SliceBytes(contents[name]))
+					policies[teamRef] = append(policies[teamRef], address)
+					if isDefault(block.Body) {
+						defaults[teamRef] = append(defaults[teamRef], address)
+					}
+				}
+			}
+		}
+		for _, team := range sortedKeys(policies) {
+			if d := defaults[team]; len(d) != 1 {
+				problems = append(problems, fmt.Sprintf("team %s has %d default escalation policies, rather than one of: %s", team, len(d), strings.Join(policies[team], ", ")))
+			}
+		}
+	}
+
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+	return nil
+}
+
+// checkReferences checks that every reference of a block resolves to a block declared in the
+// module, or to an output of a module it calls.
+func (m *tfModuleConfig) checkReferences(modules map[string]*tfModuleConfig, block *hclsyntax.Block) []string {
+	problems := []string{}
+	check := func(traversal hcl.Traversal) {
+		address, ok := m.resolve(modules, traversal)
+		if !ok {
+			problems = append(problems, fmt.Sprintf("reference to undeclared %s (%s)", address, traversal.SourceRange().String()))
+		}
+	}
+
+	var walk func(body *hclsyntax.Body, blockType string)
+	walk = func(body *hclsyntax.Body, blockType string) {
+		for _, name := range sortedKeys(body.Attributes) {
+			a := body.Attributes[name]
+			// Type constraints of variables are keywords, rather than references.
+			if blockType == "variable" && a.Name == "type" {
+				continue
+			}
+			// The address of an import block is a resource, rather than a reference to its value.
+			if blockType == "import" && a.Name == "to" {
+				if traversal, diags := hcl.AbsTraversalForExpr(a.Expr); !diags.HasErrors() {
+					check(traversal)
+				}
+				continue
+			}
+			for _, traversal := range a.Expr.Variables() {
+				check(traversal)
+			}
+		}
+		for _, b := range body.Blocks {
+			walk(b.Body, b.Type)
+		}
+	}
+	walk(block.Body, block.Type)
+	return problems
+}
+
+// resolve returns the address a traversal refers to, and whether it is declared.
+func (m *tfModuleConfig) resolve(modules map[string]*tfModuleConfig, traversal hcl.Traversal) (string, bool) {
+	names := []string{traversal.RootName()}
+	for _, step := range traversal[1:] {
+		if attr, ok := step.(hcl.TraverseAttr); ok {
+			names = append(names, attr.Name)
+		} else {
+			break
+		}
+	}
+
+	switch names[0] {
+	case "var":
+		if len(names) < 2 {
+			return "var", false
+		}
+		return "var." + names[1], m.declared["var."+names[1]]
+	case "data":
+		if len(names) < 3 {
+			return strings.Join(names, "."), false
+		}
+		address := strings.Join(names[:3], ".")
+		return address, m.declared[address]
+	case "module":
+		if len(names) < 3 {
+			return strings.Join(names, "."), false
+		}
+		address := strings.Join(names[:2], ".")
+		called, ok := modules[m.modules[names[1]]]
+		if !m.declared[address] || !ok {
+			return address, false
+		}
+		// Imports refer to the resources of a module, and everything else to its outputs.
+		if len(names) >= 4 {
+			return strings.Join(names[:4], "."), called.declared[names[2]+"."+names[3]] || called.declared["output."+names[2]]
+		}
+		return strings.Join(names[:3], "."), called.declared["output."+names[2]]
+	case "local", "path", "terraform", "count", "each", "self":
+		return names[0], true
+	default:
+		if len(names) < 2 {
+			return names[0], false
+		}
+		address := strings.Join(names[:2], ".")
+		return address, m.declared[address]
+	}
+}
+
+// checkStartTime checks that a custom strategy has a start time, no further in the past than
+// FireHydrant accepts.
+func checkStartTime(at string, body *hclsyntax.Body, now time.Time) []string {
+	strategy := slices.IndexFunc(body.Blocks, func(b *hclsyntax.Block) bool { return b.Type == "strategy" })
+	if strategy < 0 || stringAttribute(body.Blocks[strategy].Body, "type") != "custom" {
+		return nil
+	}
+	startTime := stringAttribute(body, "start_time")
+	if startTime == "" {
+		return []string{fmt.Sprintf("%s has a custom strategy without a start time", at)}
+	}
+	t, err := time.Parse(time.RFC3339, startTime)
+	if err != nil {
+		return []string{fmt.Sprintf("%s has an invalid start time %q: %s", at, startTime, err)}
+	}
+	if t.Before(now.Add(-maxStartTimeAge)) {
+		return []string{fmt.Sprintf("%s starts at %s, more than 30 days in the past, which FireHydrant rejects for custom strategies, re-run with --adjust-start-times to move it forward", at, startTime)}
+	}
+	return nil
+}
+
+// checkNoMembers reports a schedule or rotation without members, or only warns about it when empty
+// schedules are allowed.
+func (r *TFRender) checkNoMembers(ctx context.Context, resource string, address string, at string) []string {
+	if r.validation.AllowEmptySchedules {
+		diagnostics.Warnf(ctx, resource, address, "%s has no members, so nobody will be on call for it.\n", address)
+		return nil
+	}
+	return []string{fmt.Sprintf("%s has no members, so nobody would be on call for it, re-run with --allow-empty-schedules to import it anyway", at)}
+}
+
+func hasMemberIDs(body *hclsyntax.Body) bool {
+	a, ok := body.Attributes["member_ids"]
+	if !ok {
+		return false
+	}
+	members, ok := a.Expr.(*hclsyntax.TupleConsExpr)
+	return !ok || len(members.Exprs) > 0
+}
+
+func stringAttribute(body *hclsyntax.Body, name string) string {
+	a, ok := body.Attributes[name]
+	if !ok {
+		return ""
+	}
+	v, diags := a.Expr.Value(nil)
+	if diags.HasErrors() || v.IsNull() || v.Type() != cty.String {
+		return ""
+	}
+	return v.AsString()
+}
+
+func isDefault(body *hclsyntax.Body) bool {
+	a, ok := body.Attributes["default"]
+	if !ok {
+		return false
+	}
+	v, diags := a.Expr.Value(nil)
+	if diags.HasErrors() || v.IsNull() {
+		return false
+	}
+	switch v.Type() {
+	case cty.Bool:
+		return v.True()
+	case cty.String:
+		return v.AsString() == "true"
+	}
+	return false
+}
@@ -0,0 +1,99 @@
+package tfrender_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/firehydrant/signals-migrator/diagnostics"
+	"github.com/firehydrant/signals-migrator/store"
+	"github.com/firehydrant/signals-migrator/tfrender"
+)
+
+// validationNow is the clock the start times of the validation fixture are checked against.
+var validationNow = time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+
+func TestRenderValidation(t *testing.T) {
+	seed, err := os.ReadFile(filepath.Join("testdata", t.Name()+"_seed.sql"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// validate renders the fixture with the given validation, and returns the problems found and
+	// the resources warned about.
+	validate := func(t *testing.T, v tfrender.Validation) ([]string, []string) {
+		ctx, tfr := tfrInit(t)
+		ctx = diagnostics.WithCollector(ctx)
+		tfr.SetNow(func() time.Time { return validationNow })
+		tfr.SetValidation(v)
+		if _, err := store.FromContext(ctx).ExecContext(ctx, strings.TrimSpace(string(seed))); err != nil {
+			t.Fatal(err)
+		}
+
+		err := tfr.Write(ctx)
+		var validationErr *tfrender.ValidationError
+		if !errors.As(err, &validationErr) {
+			t.Fatalf("expected a validation error, got %v", err)
+		}
+		if _, err := os.Stat(tfr.Filepath()); !os.IsNotExist(err) {
+			t.Errorf("expected %s not to be written", tfr.Filepath())
+		}
+
+		warnings := []string{}
+		for _, e := range diagnostics.FromContext(ctx).Entries() {
+			if e.Severity == diagnostics.SeverityWarning {
+				warnings = append(warnings, e.Resource+" "+e.SourceID)
+			}
+		}
+		return validationErr.Problems, warnings
+	}
+
+	assertProblems := func(t *testing.T, problems []string, want []string) {
+		t.Helper()
+		for i, w := range want {
+			if i >= len(problems) || !strings.Contains(problems[i], w) {
+				t.Errorf("expected problem %d to contain %q, got %q", i, w, problems)
+			}
+		}
+		if len(problems) != len(want) {
+			t.Errorf("expected %d problems, got %d: %q", len(want), len(problems), problems)
+		}
+	}
+
+	t.Run("EmptySchedules", func(t *testing.T) {
+		problems, _ := validate(t, tfrender.Validation{})
+		assertProblems(t, problems, []string{
+			"firehydrant_on_call_schedule.payments_primary (",
+			"firehydrant_on_call_schedule.payments_secondary (",
+			"firehydrant_on_call_schedule.payments_tertiary (",
+			"reference to undeclared firehydrant_team.platform (",
+		})
+		if len(problems) == 4 {
+			if !strings.Contains(problems[0], "more than 30 days in the past") || !strings.Contains(problems[0], "--adjust-start-times") {
+				t.Errorf("expected start time of payments_primary to be rejected, got %q", problems[0])
+			}
+			if !strings.Contains(problems[1], "has no members") || !strings.Contains(problems[1], "--allow-empty-schedules") {
+				t.Errorf("expected payments_secondary to be rejected for having no members, got %q", problems[1])
+			}
+			if !strings.Contains(problems[2], "custom strategy without a start time") {
+				t.Errorf("expected payments_tertiary to be rejected for having no start time, got %q", problems[2])
+			}
+		}
+	})
+
+	t.Run("AllowEmptySchedules", func(t *testing.T) {
+		problems, warnings := validate(t, tfrender.Validation{AllowEmptySchedules: true})
+		assertProblems(t, problems, []string{
+			"firehydrant_on_call_schedule.payments_primary (",
+			"firehydrant_on_call_schedule.payments_tertiary (",
+			"reference to undeclared firehydrant_team.platform (",
+		})
+		if !slices.Contains(warnings, "schedule firehydrant_on_call_schedule.payments_secondary") {
+			t.Errorf("expected payments_secondary to be warned about having no members, got %v", warnings)
+		}
+	})
+}